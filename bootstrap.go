@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+
+	bootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"sigs.k8s.io/yaml"
+)
+
+// buildBootstrap wraps the translated resources into a static Envoy bootstrap config,
+// so that Envoy can run the configuration without a control plane.
+func buildBootstrap(
+	resources map[resourcev3.Type][]envoyproxytypes.Resource,
+	nodeID string,
+	nodeCluster string,
+	adminPort uint32,
+) (*bootstrapv3.Bootstrap, error) {
+	routeConfigs := make(map[string]*routev3.RouteConfiguration)
+	for _, res := range resources[resourcev3.RouteType] {
+		routeConfig := res.(*routev3.RouteConfiguration)
+		routeConfigs[routeConfig.Name] = routeConfig
+	}
+
+	staticResources := &bootstrapv3.Bootstrap_StaticResources{}
+	for _, res := range resources[resourcev3.ListenerType] {
+		// Static listeners can't use RDS, so the route configs are inlined.
+		envoyListener, err := inlineRouteConfigs(res.(*listenerv3.Listener), routeConfigs)
+		if err != nil {
+			return nil, err
+		}
+		// The TLS certificates are served from the static secrets instead of SDS.
+		envoyListener, err = useStaticSecrets(envoyListener)
+		if err != nil {
+			return nil, err
+		}
+		staticResources.Listeners = append(staticResources.Listeners, envoyListener)
+	}
+	endpoints := make(map[string]*endpointv3.ClusterLoadAssignment)
+	for _, res := range resources[resourcev3.EndpointType] {
+		cla := res.(*endpointv3.ClusterLoadAssignment)
+		endpoints[cla.ClusterName] = cla
+	}
+	for _, res := range resources[resourcev3.ClusterType] {
+		// Static clusters can't use EDS, so the endpoints are inlined.
+		cluster, err := inlineEndpoints(res.(*clusterv3.Cluster), endpoints)
+		if err != nil {
+			return nil, err
+		}
+		staticResources.Clusters = append(staticResources.Clusters, cluster)
+	}
+	for _, res := range resources[resourcev3.SecretType] {
+		staticResources.Secrets = append(staticResources.Secrets, res.(*tlsv3.Secret))
+	}
+
+	bootstrap := &bootstrapv3.Bootstrap{
+		Node: &corev3.Node{
+			Id:      nodeID,
+			Cluster: nodeCluster,
+		},
+		Admin: &bootstrapv3.Admin{
+			Address: &corev3.Address{
+				Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{
+						Address: "127.0.0.1",
+						PortSpecifier: &corev3.SocketAddress_PortValue{
+							PortValue: adminPort,
+						},
+					},
+				},
+			},
+		},
+		StaticResources: staticResources,
+	}
+	if err := bootstrap.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bootstrap config: %w", err)
+	}
+	return bootstrap, nil
+}
+
+// inlineRouteConfigs returns a copy of the listener whose HTTP connection managers embed
+// the referenced route configuration instead of fetching it over RDS.
+func inlineRouteConfigs(envoyListener *listenerv3.Listener, routeConfigs map[string]*routev3.RouteConfiguration) (*listenerv3.Listener, error) {
+	envoyListener = proto.Clone(envoyListener).(*listenerv3.Listener)
+	for _, filterChain := range envoyListener.FilterChains {
+		for _, filter := range filterChain.Filters {
+			if filter.Name != wellknown.HTTPConnectionManager {
+				continue
+			}
+			hcmConfig := &hcm.HttpConnectionManager{}
+			if err := filter.GetTypedConfig().UnmarshalTo(hcmConfig); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal HTTP connection manager of listener %s: %w", envoyListener.Name, err)
+			}
+			rds := hcmConfig.GetRds()
+			if rds == nil {
+				continue
+			}
+			routeConfig, ok := routeConfigs[rds.RouteConfigName]
+			if !ok {
+				return nil, fmt.Errorf("listener %s references unknown route config %s", envoyListener.Name, rds.RouteConfigName)
+			}
+			hcmConfig.RouteSpecifier = &hcm.HttpConnectionManager_RouteConfig{
+				RouteConfig: routeConfig,
+			}
+			hcmAny, err := anypb.New(hcmConfig)
+			if err != nil {
+				return nil, err
+			}
+			filter.ConfigType = &listenerv3.Filter_TypedConfig{TypedConfig: hcmAny}
+		}
+	}
+	return envoyListener, nil
+}
+
+// useStaticSecrets returns a copy of the listener whose downstream TLS contexts look up their
+// certificates in the static secrets of the bootstrap instead of fetching them over SDS.
+func useStaticSecrets(envoyListener *listenerv3.Listener) (*listenerv3.Listener, error) {
+	envoyListener = proto.Clone(envoyListener).(*listenerv3.Listener)
+	for _, filterChain := range envoyListener.FilterChains {
+		transportSocket := filterChain.GetTransportSocket()
+		if transportSocket == nil || transportSocket.GetTypedConfig() == nil {
+			continue
+		}
+		tlsContext := &tlsv3.DownstreamTlsContext{}
+		if err := transportSocket.GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal TLS context of listener %s: %w", envoyListener.Name, err)
+		}
+		// An SDS secret config without a config source refers to a static secret.
+		for _, sdsConfig := range tlsContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs() {
+			sdsConfig.SdsConfig = nil
+		}
+		if sdsConfig := tlsContext.GetCommonTlsContext().GetValidationContextSdsSecretConfig(); sdsConfig != nil {
+			sdsConfig.SdsConfig = nil
+		}
+		tlsContextAny, err := anypb.New(tlsContext)
+		if err != nil {
+			return nil, err
+		}
+		transportSocket.ConfigType = &corev3.TransportSocket_TypedConfig{TypedConfig: tlsContextAny}
+	}
+	return envoyListener, nil
+}
+
+// inlineEndpoints returns a copy of an EDS cluster that is turned into a STATIC cluster
+// with the endpoints embedded. Clusters of other types are returned unchanged.
+func inlineEndpoints(cluster *clusterv3.Cluster, endpoints map[string]*endpointv3.ClusterLoadAssignment) (*clusterv3.Cluster, error) {
+	if cluster.GetType() != clusterv3.Cluster_EDS {
+		return cluster, nil
+	}
+	serviceName := cluster.GetEdsClusterConfig().GetServiceName()
+	if serviceName == "" {
+		serviceName = cluster.Name
+	}
+	cla, ok := endpoints[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s references unknown endpoints %s", cluster.Name, serviceName)
+	}
+
+	cluster = proto.Clone(cluster).(*clusterv3.Cluster)
+	cluster.ClusterDiscoveryType = &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STATIC}
+	cluster.EdsClusterConfig = nil
+	cluster.LoadAssignment = cla
+	return cluster, nil
+}
+
+// marshalBootstrap serializes the bootstrap config in the given output format.
+func marshalBootstrap(bootstrap *bootstrapv3.Bootstrap, format string) ([]byte, error) {
+	bootstrapJSON, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(bootstrap)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case outputFormatJSON:
+		return bootstrapJSON, nil
+	case outputFormatYAML:
+		return yaml.JSONToYAML(bootstrapJSON)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+)
+
+func TestBuildBootstrap(t *testing.T) {
+	bootstrap, err := buildBootstrap(testResources(t), "test-node", "test-cluster", 9901)
+	if err != nil {
+		t.Fatalf("buildBootstrap() error = %v", err)
+	}
+	if err := bootstrap.ValidateAll(); err != nil {
+		t.Errorf("bootstrap.ValidateAll() error = %v", err)
+	}
+	if bootstrap.Node.GetId() != "test-node" || bootstrap.Node.GetCluster() != "test-cluster" {
+		t.Errorf("node = %v, want test-node in test-cluster", bootstrap.Node)
+	}
+	if port := bootstrap.Admin.GetAddress().GetSocketAddress().GetPortValue(); port != 9901 {
+		t.Errorf("admin port = %d, want 9901", port)
+	}
+
+	staticResources := bootstrap.StaticResources
+	if len(staticResources.Listeners) != 1 || len(staticResources.Clusters) != 1 || len(staticResources.Secrets) != 1 {
+		t.Fatalf("static resources = %v, want a listener, a cluster and a secret", staticResources)
+	}
+	filterChain := staticResources.Listeners[0].FilterChains[0]
+	manager := &hcm.HttpConnectionManager{}
+	if err := filterChain.Filters[0].GetTypedConfig().UnmarshalTo(manager); err != nil {
+		t.Fatal(err)
+	}
+	if manager.GetRouteConfig().GetName() != "route-443" {
+		t.Errorf("HCM route specifier = %v, want route-443 inlined", manager.RouteSpecifier)
+	}
+	tlsContext := &tlsv3.DownstreamTlsContext{}
+	if err := filterChain.TransportSocket.GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+		t.Fatal(err)
+	}
+	for _, sdsConfig := range tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs {
+		if sdsConfig.SdsConfig != nil {
+			t.Errorf("certificate %s is fetched over SDS, want the static secret", sdsConfig.Name)
+		}
+	}
+
+	cluster := staticResources.Clusters[0]
+	if cluster.GetType() != clusterv3.Cluster_STATIC || cluster.EdsClusterConfig != nil {
+		t.Errorf("cluster type = %v, want STATIC without EDS", cluster.GetType())
+	}
+	if cluster.GetLoadAssignment().GetClusterName() != testClusterName {
+		t.Errorf("cluster load assignment = %v, want the endpoints of %s inlined", cluster.LoadAssignment, testClusterName)
+	}
+}
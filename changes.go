@@ -0,0 +1,107 @@
+package main
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8scache "k8s.io/client-go/tools/cache"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+// changeSet collects the objects changed since the last reconcile in --watch mode, so that
+// only the Gateways they affect are translated again. It is safe for concurrent use.
+type changeSet struct {
+	mu sync.Mutex
+	// all is set by changes to objects that are not tracked per Gateway, such as
+	// ReferenceGrants or Namespaces, which may affect every Gateway.
+	all bool
+	// gateways are the Gateways that changed or whose attached routes changed.
+	gateways sets.Set[types.NamespacedName]
+	// services are the changed Services, including those whose EndpointSlices changed.
+	services sets.Set[types.NamespacedName]
+	secrets  sets.Set[types.NamespacedName]
+}
+
+func newChangeSet() *changeSet {
+	return &changeSet{
+		gateways: sets.New[types.NamespacedName](),
+		services: sets.New[types.NamespacedName](),
+		secrets:  sets.New[types.NamespacedName](),
+	}
+}
+
+// record adds a changed object to the change set.
+func (c *changeSet) record(obj interface{}) {
+	if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch o := obj.(type) {
+	case *corev1.Service:
+		c.services.Insert(types.NamespacedName{Namespace: o.Namespace, Name: o.Name})
+	case *discoveryv1.EndpointSlice:
+		serviceName, ok := o.Labels[discoveryv1.LabelServiceName]
+		if !ok {
+			return
+		}
+		c.services.Insert(types.NamespacedName{Namespace: o.Namespace, Name: serviceName})
+	case *corev1.Secret:
+		c.secrets.Insert(types.NamespacedName{Namespace: o.Namespace, Name: o.Name})
+	case *gatewayv1.Gateway:
+		c.gateways.Insert(types.NamespacedName{Namespace: o.Namespace, Name: o.Name})
+	case *gatewayv1.HTTPRoute:
+		c.recordParentGateways(o.Namespace, o.Spec.ParentRefs)
+	case *gatewayv1.GRPCRoute:
+		c.recordParentGateways(o.Namespace, o.Spec.ParentRefs)
+	case *gatewayv1alpha2.TCPRoute:
+		c.recordParentGateways(o.Namespace, o.Spec.ParentRefs)
+	case *gatewayv1alpha2.TLSRoute:
+		c.recordParentGateways(o.Namespace, o.Spec.ParentRefs)
+	case *gatewayv1alpha2.UDPRoute:
+		c.recordParentGateways(o.Namespace, o.Spec.ParentRefs)
+	default:
+		c.all = true
+	}
+}
+
+// recordParentGateways adds the Gateways referenced by the parentRefs of a route in namespace.
+func (c *changeSet) recordParentGateways(namespace string, parentRefs []gatewayv1.ParentReference) {
+	for _, parentRef := range parentRefs {
+		if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+			continue
+		}
+		refNamespace := namespace
+		if parentRef.Namespace != nil {
+			refNamespace = string(*parentRef.Namespace)
+		}
+		c.gateways.Insert(types.NamespacedName{Namespace: refNamespace, Name: string(parentRef.Name)})
+	}
+}
+
+// take returns the recorded changes and resets the change set.
+func (c *changeSet) take() *changeSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changes := &changeSet{all: c.all, gateways: c.gateways, services: c.services, secrets: c.secrets}
+	c.all = false
+	c.gateways = sets.New[types.NamespacedName]()
+	c.services = sets.New[types.NamespacedName]()
+	c.secrets = sets.New[types.NamespacedName]()
+	return changes
+}
+
+// affects reports whether the changes affect the translation of the Gateway, given the
+// dependencies of its last translation.
+func (c *changeSet) affects(gateway types.NamespacedName, deps translator.Dependencies) bool {
+	return c.all || c.gateways.Has(gateway) ||
+		c.services.HasAny(deps.Services.UnsortedList()...) ||
+		c.secrets.HasAny(deps.Secrets.UnsortedList()...)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8scache "k8s.io/client-go/tools/cache"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+func TestChangeSetRecord(t *testing.T) {
+	crossNamespaceRoute := testHTTPRoute("web", "gw", "backend")
+	crossNamespaceRoute.Spec.ParentRefs = append(crossNamespaceRoute.Spec.ParentRefs, gatewayv1.ParentReference{
+		Name:      "shared",
+		Namespace: (*gatewayv1.Namespace)(&[]string{"infra"}[0]),
+	})
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: testNamespace}}
+
+	for _, tc := range []struct {
+		name         string
+		obj          interface{}
+		wantAll      bool
+		wantGateways []types.NamespacedName
+		wantServices []types.NamespacedName
+		wantSecrets  []types.NamespacedName
+	}{
+		{
+			name:         "Service",
+			obj:          testService("backend"),
+			wantServices: []types.NamespacedName{{Namespace: testNamespace, Name: "backend"}},
+		},
+		{
+			name:         "EndpointSlice",
+			obj:          testEndpointSlice("backend-abc", "backend", "10.1.0.1"),
+			wantServices: []types.NamespacedName{{Namespace: testNamespace, Name: "backend"}},
+		},
+		{
+			name:        "Secret",
+			obj:         secret,
+			wantSecrets: []types.NamespacedName{{Namespace: testNamespace, Name: "cert"}},
+		},
+		{
+			name:         "Gateway",
+			obj:          testGateway("gw", 80),
+			wantGateways: []types.NamespacedName{{Namespace: testNamespace, Name: "gw"}},
+		},
+		{
+			name: "HTTPRoute",
+			obj:  crossNamespaceRoute,
+			wantGateways: []types.NamespacedName{
+				{Namespace: testNamespace, Name: "gw"},
+				{Namespace: "infra", Name: "shared"},
+			},
+		},
+		{
+			name:         "deleted Service",
+			obj:          k8scache.DeletedFinalStateUnknown{Key: "default/backend", Obj: testService("backend")},
+			wantServices: []types.NamespacedName{{Namespace: testNamespace, Name: "backend"}},
+		},
+		{
+			// Kinds that are not tracked per Gateway may affect any of them.
+			name:    "ReferenceGrant",
+			obj:     &gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: testNamespace}},
+			wantAll: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := newChangeSet()
+			changes.record(tc.obj)
+			if changes.all != tc.wantAll {
+				t.Errorf("all = %v, want %v", changes.all, tc.wantAll)
+			}
+			for _, set := range []struct {
+				name string
+				got  sets.Set[types.NamespacedName]
+				want []types.NamespacedName
+			}{
+				{"gateways", changes.gateways, tc.wantGateways},
+				{"services", changes.services, tc.wantServices},
+				{"secrets", changes.secrets, tc.wantSecrets},
+			} {
+				if !set.got.Equal(sets.New(set.want...)) {
+					t.Errorf("%s = %v, want %v", set.name, set.got.UnsortedList(), set.want)
+				}
+			}
+		})
+	}
+}
+
+func TestChangeSetAffects(t *testing.T) {
+	gateway := types.NamespacedName{Namespace: testNamespace, Name: "gw"}
+	deps := translator.Dependencies{
+		Services: sets.New(types.NamespacedName{Namespace: testNamespace, Name: "backend"}),
+		Secrets:  sets.New(types.NamespacedName{Namespace: testNamespace, Name: "cert"}),
+	}
+	for _, tc := range []struct {
+		name    string
+		changed []runtime.Object
+		want    bool
+	}{
+		{name: "nothing", want: false},
+		{name: "unrelated Service", changed: []runtime.Object{testService("other")}, want: false},
+		{name: "unrelated Gateway", changed: []runtime.Object{testGateway("other", 80)}, want: false},
+		{name: "dependent Service", changed: []runtime.Object{testService("backend")}, want: true},
+		{name: "dependent EndpointSlice", changed: []runtime.Object{testEndpointSlice("backend-abc", "backend")}, want: true},
+		{name: "dependent Secret", changed: []runtime.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: testNamespace}}}, want: true},
+		{name: "the Gateway", changed: []runtime.Object{testGateway("gw", 80)}, want: true},
+		{name: "attached route", changed: []runtime.Object{testHTTPRoute("web", "gw", "other")}, want: true},
+		{name: "untracked kind", changed: []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "infra"}}}, want: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := newChangeSet()
+			for _, obj := range tc.changed {
+				changes.record(obj)
+			}
+			if got := changes.affects(gateway, deps); got != tc.want {
+				t.Errorf("affects() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestChangeSetTake(t *testing.T) {
+	changes := newChangeSet()
+	changes.record(testService("backend"))
+	changes.record(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: testNamespace}})
+
+	taken := changes.take()
+	if !taken.all || taken.services.Len() != 1 {
+		t.Errorf("take() = %+v, want the recorded changes", taken)
+	}
+	if changes.all || changes.services.Len() != 0 {
+		t.Errorf("change set after take() = %+v, want it empty", changes)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+)
+
+// diffResourceKinds maps the xDS types compared in --diff mode to the names they are
+// reported by. Endpoints and secrets are left out: endpoints change whenever pods are
+// rescheduled, and secrets would print key material.
+var diffResourceKinds = map[types.ResponseType]string{
+	types.Listener: "listener",
+	types.Cluster:  "cluster",
+	types.Route:    "route",
+}
+
+// snapshotJSON is the layout of a snapshot written with --output-format json. The
+// resources are kept as generic JSON values, as the protos cannot be decoded from the
+// encoding/json output.
+type snapshotJSON struct {
+	Resources []struct {
+		Items map[string]struct {
+			Resource any
+		}
+	}
+}
+
+// resourceChange is a listener, cluster or route that differs between two snapshots.
+type resourceChange struct {
+	Kind string
+	Name string
+	// Op is '+' for added, '-' for removed and '~' for modified resources.
+	Op byte
+	// Fields describes the changed fields of modified resources.
+	Fields []string
+}
+
+// loadSnapshotResources decodes the listeners, clusters and routes of a JSON snapshot, keyed
+// by kind and name.
+func loadSnapshotResources(data []byte) (map[string]map[string]any, error) {
+	var snapshot snapshotJSON
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	resources := make(map[string]map[string]any)
+	for responseType, kind := range diffResourceKinds {
+		resources[kind] = make(map[string]any)
+		if int(responseType) >= len(snapshot.Resources) {
+			continue
+		}
+		for name, item := range snapshot.Resources[responseType].Items {
+			resources[kind][name] = item.Resource
+		}
+	}
+	return resources, nil
+}
+
+// snapshotResources returns the listeners, clusters and routes of the snapshot in the form
+// returned by loadSnapshotResources, so that they can be compared with a loaded snapshot.
+func snapshotResources(snapshot *cache.Snapshot) (map[string]map[string]any, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return loadSnapshotResources(data)
+}
+
+// diffSnapshots returns the resources added, removed or modified from old to new, sorted by
+// kind and name. The snapshot versions are not compared.
+func diffSnapshots(old, new map[string]map[string]any) []resourceChange {
+	var changes []resourceChange
+	for _, kind := range diffResourceKinds {
+		for name, oldResource := range old[kind] {
+			newResource, ok := new[kind][name]
+			if !ok {
+				changes = append(changes, resourceChange{Kind: kind, Name: name, Op: '-'})
+				continue
+			}
+			var fields []string
+			diffValues("", oldResource, newResource, &fields)
+			if len(fields) > 0 {
+				sort.Strings(fields)
+				changes = append(changes, resourceChange{Kind: kind, Name: name, Op: '~', Fields: fields})
+			}
+		}
+		for name := range new[kind] {
+			if _, ok := old[kind][name]; !ok {
+				changes = append(changes, resourceChange{Kind: kind, Name: name, Op: '+'})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// diffValues appends a description of every field that differs between the JSON values
+// old and new to fields. Fields are identified by their path from the resource.
+func diffValues(path string, old, new any, fields *[]string) {
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+	switch oldValue := old.(type) {
+	case map[string]any:
+		newValue, ok := new.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{})
+		for key := range oldValue {
+			keys[key] = struct{}{}
+		}
+		for key := range newValue {
+			keys[key] = struct{}{}
+		}
+		for key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			diffValues(fieldPath, oldValue[key], newValue[key], fields)
+		}
+		return
+	case []any:
+		newValue, ok := new.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(oldValue), len(newValue)); i++ {
+			var oldElem, newElem any
+			if i < len(oldValue) {
+				oldElem = oldValue[i]
+			}
+			if i < len(newValue) {
+				newElem = newValue[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), oldElem, newElem, fields)
+		}
+		return
+	}
+	*fields = append(*fields, fmt.Sprintf("%s: %s -> %s", path, formatJSONValue(old), formatJSONValue(new)))
+}
+
+// formatJSONValue returns the compact JSON encoding of a value, or "<none>" if it is absent.
+func formatJSONValue(value any) string {
+	if value == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// formatDiff renders the changes as a human-readable report, one line per resource followed
+// by the changed fields of modified resources.
+func formatDiff(changes []resourceChange) string {
+	if len(changes) == 0 {
+		return "No differences\n"
+	}
+	var b strings.Builder
+	for _, change := range changes {
+		fmt.Fprintf(&b, "%c %s %s\n", change.Op, change.Kind, change.Name)
+		for _, field := range change.Fields {
+			fmt.Fprintf(&b, "    %s\n", field)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// loadTestSnapshot returns the resources of the snapshot as loaded from a --output file.
+func loadTestSnapshot(t *testing.T, resources map[resourcev3.Type][]envoyproxytypes.Resource) map[string]map[string]any {
+	t.Helper()
+	snapshot, err := generateXDS(resources, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := marshalSnapshot(snapshot, outputFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadSnapshotResources(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := loadTestSnapshot(t, testResources(t))
+
+	resources := testResources(t)
+	resources[resourcev3.ClusterType][0].(*clusterv3.Cluster).ConnectTimeout = durationpb.New(10 * time.Second)
+	snapshot, err := generateXDS(resources, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := snapshotResources(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := diffSnapshots(previous, current)
+	if len(changes) != 1 {
+		t.Fatalf("diffSnapshots() = %v, want only the modified cluster", changes)
+	}
+	change := changes[0]
+	if change.Kind != "cluster" || change.Name != testClusterName || change.Op != '~' {
+		t.Errorf("change = %c %s %s, want ~ cluster %s", change.Op, change.Kind, change.Name, testClusterName)
+	}
+	wantReport := "~ cluster " + testClusterName + "\n    connect_timeout: <none> -> {\"seconds\":10}\n"
+	if report := formatDiff(changes); report != wantReport {
+		t.Errorf("formatDiff() = %q, want %q", report, wantReport)
+	}
+}
+
+func TestDiffSnapshotsAddedAndRemoved(t *testing.T) {
+	previous := loadTestSnapshot(t, testResources(t))
+	resources := testResources(t)
+	resources[resourcev3.ClusterType][0].(*clusterv3.Cluster).Name = "default_api_core_Service_80"
+	current := loadTestSnapshot(t, resources)
+
+	got := formatDiff(diffSnapshots(previous, current))
+	want := "+ cluster default_api_core_Service_80\n- cluster " + testClusterName + "\n"
+	if got != want {
+		t.Errorf("formatDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffSnapshotsIdentical(t *testing.T) {
+	previous := loadTestSnapshot(t, testResources(t))
+	current, err := snapshotResources(testSnapshot(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := diffSnapshots(previous, current)
+	if len(changes) != 0 {
+		t.Errorf("diffSnapshots() = %v, want no changes", changes)
+	}
+	if got := formatDiff(changes); got != "No differences\n" {
+		t.Errorf("formatDiff() = %q, want No differences", got)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// health tracks the readiness of the generator in --serve mode. It is not ready until the
+// informer caches have synced and the first snapshot has been set.
+type health struct {
+	ready atomic.Bool
+}
+
+// setReady marks the generator as ready to serve Envoys.
+func (h *health) setReady() {
+	h.ready.Store(true)
+}
+
+// handler returns the handler of the /healthz liveness and /readyz readiness endpoints.
+func (h *health) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !h.ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// serveHealth serves the health endpoints on listenAddr until ctx is cancelled.
+func serveHealth(ctx context.Context, listenAddr string, h *health) error {
+	lis, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+	}
+
+	server := &http.Server{Handler: h.handler()}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("serving health endpoints", "address", lis.Addr().String())
+	if err := server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("health server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// probe returns the status code of a GET request to the path of the health handler.
+func probe(t *testing.T, h *health, path string) int {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	h.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder.Code
+}
+
+func TestHealthReadiness(t *testing.T) {
+	h := &health{}
+	// The generator is live while the informer caches sync, but not ready.
+	if got := probe(t, h, "/healthz"); got != http.StatusOK {
+		t.Errorf("/healthz status = %d, want %d", got, http.StatusOK)
+	}
+	if got := probe(t, h, "/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz status before the first snapshot = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+
+	// The generator is marked ready once the first snapshot is set.
+	h.setReady()
+	if got := probe(t, h, "/readyz"); got != http.StatusOK {
+		t.Errorf("/readyz status after the first snapshot = %d, want %d", got, http.StatusOK)
+	}
+	if got := probe(t, h, "/healthz"); got != http.StatusOK {
+		t.Errorf("/healthz status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestServeHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	addr := freeAddress(t)
+	h := &health{}
+	h.setReady()
+	served := make(chan error, 1)
+	go func() { served <- serveHealth(ctx, addr, h) }()
+
+	var resp *http.Response
+	var err error
+	for range 50 {
+		if resp, err = http.Get("http://" + addr + "/readyz"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET /readyz error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/readyz status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// Cancelling the context shuts the server down.
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("serveHealth() error = %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	routerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+	gatewaylistersv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1alpha2"
+	gatewaylistersv1beta1 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+// testClusterName is the name of the cluster, and of its endpoints, in testResources.
+const testClusterName = "default_web_core_Service_80"
+
+// adsConfigSource returns the config source of resources fetched over ADS, as the translator
+// sets it.
+func adsConfigSource() *corev3.ConfigSource {
+	return &corev3.ConfigSource{
+		ResourceApiVersion:    corev3.ApiVersion_V3,
+		ConfigSourceSpecifier: &corev3.ConfigSource_Ads{Ads: &corev3.AggregatedConfigSource{}},
+	}
+}
+
+// testResources returns the resources of a Gateway with an HTTPS listener on port 443 that
+// routes to a single EDS cluster, shaped like the output of the translator: the listener
+// fetches its routes over RDS and its certificate over SDS, and the cluster its endpoints
+// over EDS, all from ADS.
+func testResources(t testing.TB) map[resourcev3.Type][]envoyproxytypes.Resource {
+	t.Helper()
+	router, err := anypb.New(&routerv3.Router{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hcmAny, err := anypb.New(&hcm.HttpConnectionManager{
+		StatPrefix: "https",
+		RouteSpecifier: &hcm.HttpConnectionManager_Rds{
+			Rds: &hcm.Rds{ConfigSource: adsConfigSource(), RouteConfigName: "route-443"},
+		},
+		HttpFilters: []*hcm.HttpFilter{{
+			Name:       wellknown.Router,
+			ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: router},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsContext, err := anypb.New(&tlsv3.DownstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			TlsCertificateSdsSecretConfigs: []*tlsv3.SdsSecretConfig{{
+				Name:      "default-web-cert",
+				SdsConfig: adsConfigSource(),
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[resourcev3.Type][]envoyproxytypes.Resource{
+		resourcev3.ListenerType: {&listenerv3.Listener{
+			Name: "listener-443",
+			Address: &corev3.Address{
+				Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{
+						Address:       "0.0.0.0",
+						PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: 443},
+					},
+				},
+			},
+			FilterChains: []*listenerv3.FilterChain{{
+				Filters: []*listenerv3.Filter{{
+					Name:       wellknown.HTTPConnectionManager,
+					ConfigType: &listenerv3.Filter_TypedConfig{TypedConfig: hcmAny},
+				}},
+				TransportSocket: &corev3.TransportSocket{
+					Name:       wellknown.TransportSocketTLS,
+					ConfigType: &corev3.TransportSocket_TypedConfig{TypedConfig: tlsContext},
+				},
+			}},
+		}},
+		resourcev3.RouteType: {&routev3.RouteConfiguration{
+			Name: "route-443",
+			VirtualHosts: []*routev3.VirtualHost{{
+				Name:    "gw-vh-443-*",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{{
+					Name:  "default-web-rule0-match0",
+					Match: &routev3.RouteMatch{PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"}},
+					Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+						ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: testClusterName},
+					}},
+				}},
+			}},
+		}},
+		resourcev3.ClusterType: {&clusterv3.Cluster{
+			Name:                 testClusterName,
+			ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_EDS},
+			EdsClusterConfig:     &clusterv3.Cluster_EdsClusterConfig{EdsConfig: adsConfigSource()},
+		}},
+		resourcev3.EndpointType: {&endpointv3.ClusterLoadAssignment{
+			ClusterName: testClusterName,
+			Endpoints: []*endpointv3.LocalityLbEndpoints{{
+				LbEndpoints: []*endpointv3.LbEndpoint{{
+					HostIdentifier: &endpointv3.LbEndpoint_Endpoint{Endpoint: &endpointv3.Endpoint{
+						Address: &corev3.Address{
+							Address: &corev3.Address_SocketAddress{
+								SocketAddress: &corev3.SocketAddress{
+									Address:       "10.0.0.1",
+									PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: 8080},
+								},
+							},
+						},
+					}},
+				}},
+			}},
+		}},
+		resourcev3.SecretType: {&tlsv3.Secret{
+			Name: "default-web-cert",
+			Type: &tlsv3.Secret_TlsCertificate{TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{Specifier: &corev3.DataSource_InlineString{InlineString: "cert"}},
+				PrivateKey:       &corev3.DataSource{Specifier: &corev3.DataSource_InlineString{InlineString: "key"}},
+			}},
+		}},
+	}
+}
+
+// testSnapshot returns a snapshot of testResources.
+func testSnapshot(t testing.TB) *cache.Snapshot {
+	t.Helper()
+	snapshot, err := generateXDS(testResources(t), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+// testNamespace is the namespace of the Kubernetes objects built by the test helpers.
+const testNamespace = "default"
+
+// testListers holds the indexers behind the listers of a test Translator, by kind, so that
+// tests can change the objects after the Translator is built.
+type testListers map[string]k8scache.Indexer
+
+func (l testListers) indexer(kind string) k8scache.Indexer {
+	if l[kind] == nil {
+		l[kind] = k8scache.NewIndexer(k8scache.MetaNamespaceKeyFunc, k8scache.Indexers{k8scache.NamespaceIndex: k8scache.MetaNamespaceIndexFunc})
+	}
+	return l[kind]
+}
+
+// add adds or updates the objects.
+func (l testListers) add(t testing.TB, objs ...runtime.Object) {
+	t.Helper()
+	for _, obj := range objs {
+		var kind string
+		switch obj.(type) {
+		case *corev1.Service:
+			kind = "Service"
+		case *discoveryv1.EndpointSlice:
+			kind = "EndpointSlice"
+		case *gatewayv1.Gateway:
+			kind = "Gateway"
+		case *gatewayv1.GatewayClass:
+			kind = "GatewayClass"
+		case *gatewayv1.HTTPRoute:
+			kind = "HTTPRoute"
+		default:
+			t.Fatalf("unsupported object %T", obj)
+		}
+		if err := l.indexer(kind).Update(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newTestTranslator returns a Translator without clients whose listers serve the objects.
+func newTestTranslator(t testing.TB, options translator.Options, objs ...runtime.Object) (*translator.Translator, testListers) {
+	t.Helper()
+	listers := make(testListers)
+	listers.add(t, objs...)
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
+	return translator.New(nil, nil,
+		corev1listers.NewNamespaceLister(listers.indexer("Namespace")),
+		corev1listers.NewServiceLister(listers.indexer("Service")),
+		discoverylisters.NewEndpointSliceLister(listers.indexer("EndpointSlice")),
+		corev1listers.NewSecretLister(listers.indexer("Secret")),
+		corev1listers.NewConfigMapLister(listers.indexer("ConfigMap")),
+		gatewaylisters.NewGatewayLister(listers.indexer("Gateway")),
+		gatewaylisters.NewGatewayClassLister(listers.indexer("GatewayClass")),
+		gatewaylisters.NewHTTPRouteLister(listers.indexer("HTTPRoute")),
+		gatewaylisters.NewGRPCRouteLister(listers.indexer("GRPCRoute")),
+		gatewaylistersv1alpha2.NewTCPRouteLister(listers.indexer("TCPRoute")),
+		gatewaylistersv1alpha2.NewTLSRouteLister(listers.indexer("TLSRoute")),
+		gatewaylistersv1alpha2.NewUDPRouteLister(listers.indexer("UDPRoute")),
+		gatewaylistersv1beta1.NewReferenceGrantLister(listers.indexer("ReferenceGrant")),
+		gatewaylisters.NewBackendTLSPolicyLister(listers.indexer("BackendTLSPolicy")),
+		options,
+	), listers
+}
+
+// testGatewayClass returns a GatewayClass managed by the Translator.
+func testGatewayClass() *gatewayv1.GatewayClass {
+	return &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: translator.DefaultControllerName},
+	}
+}
+
+// testGateway returns a Gateway of testGatewayClass with an HTTP listener on the port.
+func testGateway(name string, port gatewayv1.PortNumber) *gatewayv1.Gateway {
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "test",
+			Listeners:        []gatewayv1.Listener{{Name: "http", Port: port, Protocol: gatewayv1.HTTPProtocolType}},
+		},
+	}
+}
+
+// testService returns a ClusterIP Service with a single port 80.
+func testService(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.1",
+			Ports:     []corev1.ServicePort{{Port: 80}},
+		},
+	}
+}
+
+// testEndpointSlice returns an EndpointSlice of the Service with a ready endpoint per address.
+func testEndpointSlice(name, service string, addresses ...string) *discoveryv1.EndpointSlice {
+	port := int32(8080)
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+	}
+	for _, address := range addresses {
+		endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{Addresses: []string{address}})
+	}
+	return endpointSlice
+}
+
+// testHTTPRoute returns an HTTPRoute attached to the Gateway that forwards to port 80 of the
+// Service.
+func testHTTPRoute(name, gateway, service string) *gatewayv1.HTTPRoute {
+	port := gatewayv1.PortNumber(80)
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(service), Port: &port},
+				}}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// buildRESTConfig returns the config of the Kubernetes client. It is loaded from the given
+// kubeconfig file if one is specified, then from the files listed in $KUBECONFIG, from the
+// service account of the pod when running in-cluster, or else from ~/.kube/config. The
+// context overrides the current context of the kubeconfig.
+func buildRESTConfig(kubeconfig, context string) (*rest.Config, error) {
+	return resolveRESTConfig(kubeconfig, os.Getenv(clientcmd.RecommendedConfigPathEnvVar), context, rest.InClusterConfig)
+}
+
+// resolveRESTConfig implements buildRESTConfig with the value of $KUBECONFIG and the
+// in-cluster config supplied by the caller.
+func resolveRESTConfig(
+	kubeconfig string,
+	kubeconfigEnv string,
+	context string,
+	inClusterConfig func() (*rest.Config, error),
+) (*rest.Config, error) {
+	// Asking for a kubeconfig or one of its contexts takes precedence over the in-cluster config.
+	if kubeconfig == "" && kubeconfigEnv == "" && context == "" {
+		config, err := inClusterConfig()
+		if err == nil {
+			return config, nil
+		}
+		slog.Debug("not running in-cluster, falling back to the default kubeconfig", "err", err)
+	}
+	loadingRules := kubeconfigLoadingRules(kubeconfig, kubeconfigEnv)
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig %s: %w", kubeconfigPaths(loadingRules), err)
+	}
+	return config, nil
+}
+
+// kubeconfigLoadingRules returns the rules to load the kubeconfig from the given file, or
+// else from the files listed in the value of $KUBECONFIG, or else from ~/.kube/config.
+func kubeconfigLoadingRules(kubeconfig, kubeconfigEnv string) *clientcmd.ClientConfigLoadingRules {
+	if kubeconfig != "" {
+		return &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
+	}
+	var precedence []string
+	for _, path := range filepath.SplitList(kubeconfigEnv) {
+		if path != "" {
+			precedence = append(precedence, path)
+		}
+	}
+	if len(precedence) == 0 {
+		precedence = []string{clientcmd.RecommendedHomeFile}
+	}
+	return &clientcmd.ClientConfigLoadingRules{Precedence: precedence}
+}
+
+func kubeconfigPaths(loadingRules *clientcmd.ClientConfigLoadingRules) string {
+	if loadingRules.ExplicitPath != "" {
+		return loadingRules.ExplicitPath
+	}
+	return strings.Join(loadingRules.Precedence, string(filepath.ListSeparator))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// writeKubeconfig writes a kubeconfig whose contexts connect to https://<context>.<name>.example.com,
+// the first one being the current context, and returns its path.
+func writeKubeconfig(t *testing.T, name string, contexts ...string) string {
+	t.Helper()
+	kubeconfig := "apiVersion: v1\nkind: Config\ncurrent-context: " + contexts[0] + "\nclusters:\n"
+	for _, context := range contexts {
+		kubeconfig += "- name: " + context + "\n  cluster:\n    server: https://" + context + "." + name + ".example.com\n"
+	}
+	kubeconfig += "contexts:\n"
+	for _, context := range contexts {
+		kubeconfig += "- name: " + context + "\n  context:\n    cluster: " + context + "\n    user: user\n"
+	}
+	kubeconfig += "users:\n- name: user\n  user:\n    token: token\n"
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveRESTConfig(t *testing.T) {
+	flagKubeconfig := writeKubeconfig(t, "flag", "default", "other")
+	envKubeconfig := writeKubeconfig(t, "env", "default", "other")
+	inCluster := func() (*rest.Config, error) {
+		return &rest.Config{Host: "https://in-cluster.example.com"}, nil
+	}
+	notInCluster := func() (*rest.Config, error) {
+		return nil, rest.ErrNotInCluster
+	}
+
+	for _, tc := range []struct {
+		name            string
+		kubeconfig      string
+		kubeconfigEnv   string
+		context         string
+		inClusterConfig func() (*rest.Config, error)
+		wantHost        string
+	}{
+		{name: "in-cluster", inClusterConfig: inCluster, wantHost: "https://in-cluster.example.com"},
+		// The flag takes precedence over the in-cluster config.
+		{name: "flag in-cluster", kubeconfig: flagKubeconfig, inClusterConfig: inCluster, wantHost: "https://default.flag.example.com"},
+		{name: "flag out of cluster", kubeconfig: flagKubeconfig, inClusterConfig: notInCluster, wantHost: "https://default.flag.example.com"},
+		// So do $KUBECONFIG and the context, and the flag takes precedence over $KUBECONFIG.
+		{name: "env in-cluster", kubeconfigEnv: envKubeconfig, inClusterConfig: inCluster, wantHost: "https://default.env.example.com"},
+		{name: "flag and env", kubeconfig: flagKubeconfig, kubeconfigEnv: envKubeconfig, inClusterConfig: inCluster, wantHost: "https://default.flag.example.com"},
+		{name: "flag context", kubeconfig: flagKubeconfig, context: "other", inClusterConfig: inCluster, wantHost: "https://other.flag.example.com"},
+		{name: "env context", kubeconfigEnv: envKubeconfig, context: "other", inClusterConfig: notInCluster, wantHost: "https://other.env.example.com"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := resolveRESTConfig(tc.kubeconfig, tc.kubeconfigEnv, tc.context, tc.inClusterConfig)
+			if err != nil {
+				t.Fatalf("resolveRESTConfig() error = %v", err)
+			}
+			if config.Host != tc.wantHost {
+				t.Errorf("host = %s, want %s", config.Host, tc.wantHost)
+			}
+		})
+	}
+}
+
+func TestResolveRESTConfigMissingKubeconfig(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	inClusterCalled := false
+	inClusterConfig := func() (*rest.Config, error) {
+		inClusterCalled = true
+		return nil, errors.New("unexpected in-cluster config")
+	}
+	_, err := resolveRESTConfig(missing, "", "", inClusterConfig)
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Fatalf("resolveRESTConfig() error = %v, want an error naming %s", err, missing)
+	}
+	if inClusterCalled {
+		t.Error("the in-cluster config was consulted despite the kubeconfig flag")
+	}
+}
+
+func TestKubeconfigLoadingRules(t *testing.T) {
+	sep := string(filepath.ListSeparator)
+	for _, tc := range []struct {
+		name           string
+		kubeconfig     string
+		kubeconfigEnv  string
+		wantExplicit   string
+		wantPrecedence []string
+	}{
+		{name: "default", wantPrecedence: []string{clientcmd.RecommendedHomeFile}},
+		{name: "flag", kubeconfig: "/etc/kubeconfig", kubeconfigEnv: "/env/a", wantExplicit: "/etc/kubeconfig"},
+		{name: "env", kubeconfigEnv: "/env/a" + sep + sep + "/env/b", wantPrecedence: []string{"/env/a", "/env/b"}},
+		{name: "empty env", kubeconfigEnv: sep, wantPrecedence: []string{clientcmd.RecommendedHomeFile}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			loadingRules := kubeconfigLoadingRules(tc.kubeconfig, tc.kubeconfigEnv)
+			if loadingRules.ExplicitPath != tc.wantExplicit || !slices.Equal(loadingRules.Precedence, tc.wantPrecedence) {
+				t.Errorf("kubeconfigLoadingRules(%q, %q) = %q, %q, want %q, %q", tc.kubeconfig, tc.kubeconfigEnv,
+					loadingRules.ExplicitPath, loadingRules.Precedence, tc.wantExplicit, tc.wantPrecedence)
+			}
+		})
+	}
+}
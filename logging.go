@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// The formats of the log output.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// newLogger returns a logger that writes the records at or above the level, one of debug,
+// info, warn or error, to w in the given format.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unsupported log level %q, must be debug, info, warn or error", level)
+	}
+	handlerOptions := &slog.HandlerOptions{Level: logLevel}
+	switch strings.ToLower(format) {
+	case logFormatText:
+		return slog.New(slog.NewTextHandler(w, handlerOptions)), nil
+	case logFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, handlerOptions)), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, must be text or json", format)
+	}
+}
+
+// setupLogging makes the logger the default logger, which also receives the logs of klog.
+func setupLogging(logger *slog.Logger) {
+	slog.SetDefault(logger)
+	klog.SetSlogLogger(logger)
+}
+
+// logErrors logs each of the errors joined in err as a separate record.
+func logErrors(msg string, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			logErrors(msg, e)
+		}
+		return
+	}
+	slog.Error(msg, "err", err)
+}
+
+// fatal logs the error and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -2,75 +2,216 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/user"
-	"path/filepath"
+	"os/signal"
+	"runtime"
+	"slices"
+	"sort"
+	"syscall"
 	"time"
 
 	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	k8scache "k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
 
 	"gateway-xds-generator/pkg/translator"
 )
 
 var (
-	gatewayName = flag.String("gateway", "", "Name of the Gateway resource")
-	gatewayNs   = flag.String("namespace", "default", "Namespace of the Gateway resource")
-	outputFile  = flag.String("output", "envoy-xds.json", "Output file for the Envoy XDS configuration")
+	kubeconfig        = flag.String("kubeconfig", "", "Path of the kubeconfig file, the files in $KUBECONFIG, the in-cluster config or else ~/.kube/config are used if empty")
+	kubeContext       = flag.String("context", "", "Kubeconfig context to use instead of the current context")
+	logLevel          = flag.String("log-level", "info", "Minimum level of the logged messages: debug, info, warn or error")
+	logFormat         = flag.String("log-format", logFormatText, "Format of the log output: text or json")
+	gatewayName       = flag.String("gateway", "", "Name of the Gateway resource, all Gateways in --namespace are translated if empty")
+	gatewayNs         = flag.String("namespace", "default", "Namespace of the Gateway resource")
+	outputFile        = flag.String("output", "envoy-xds.json", "Output file for the Envoy XDS configuration, - writes it to stdout")
+	outputDir         = flag.String("output-dir", "", "Write the resources of each XDS type to a file of its own in this directory instead of to --output, e.g. lds.json for the listeners, for filesystem-based XDS")
+	gzipOutput        = flag.Bool("gzip", false, "Gzip-compress the output file, whose name gets a .gz suffix if it has none")
+	outputFmt         = flag.String("output-format", outputFormatJSON, "Format of the output file: json or yaml")
+	serve             = flag.Bool("serve", false, "Serve the Envoy XDS configuration over ADS instead of writing it to --output")
+	nodeID            = flag.String("node-id", "envoy", "Envoy node ID the XDS configuration is served to in --serve mode, or set in --bootstrap mode")
+	nodePerGateway    = flag.Bool("node-per-gateway", false, "Serve a separate snapshot for each Gateway in --serve mode, to the node ID namespace/name of the Gateway, instead of serving all Gateways to --node-id")
+	listenAddr        = flag.String("listen", ":18000", "Address the ADS server listens on in --serve mode")
+	metricsAddr       = flag.String("metrics-listen", ":18001", "Address the Prometheus /metrics endpoint listens on in --serve mode, metrics are not served if empty")
+	healthAddr        = flag.String("health-listen", ":18002", "Address the /healthz liveness and /readyz readiness endpoints listen on in --serve mode, they are not served if empty")
+	watch             = flag.Bool("watch", false, "Re-translate the Gateway when watched resources change and push new snapshots (requires --serve)")
+	bootstrap         = flag.Bool("bootstrap", false, "Write a static Envoy bootstrap config embedding the XDS resources to --output")
+	adminPort         = flag.Uint("admin-port", 9901, "Port of the Envoy admin interface in --bootstrap mode")
+	nodeCluster       = flag.String("node-cluster", "gateway", "Envoy node cluster in --bootstrap mode")
+	accessLog         = flag.Bool("access-log", false, "Log every HTTP request to stdout")
+	accessLogFmt      = flag.String("access-log-format", translator.AccessLogFormatJSON, "Format of the access log entries: json or text")
+	accessLogStr      = flag.String("access-log-format-string", "", "Overrides the default access log format: a JSON object mapping fields to Envoy command operators for json, an Envoy format string for text")
+	maxConns          = flag.Uint("max-connections", 0, "Default maximum number of connections to each backend, overridable with the gateway.xds/max-connections Service annotation (0 keeps Envoy's default)")
+	maxPending        = flag.Uint("max-pending-requests", 0, "Default maximum number of pending requests to each backend, overridable with the gateway.xds/max-pending-requests Service annotation (0 keeps Envoy's default)")
+	maxRequests       = flag.Uint("max-requests", 0, "Default maximum number of parallel requests to each backend, overridable with the gateway.xds/max-requests Service annotation (0 keeps Envoy's default)")
+	maxRetries        = flag.Uint("max-retries", 0, "Default maximum number of parallel retries to each backend, overridable with the gateway.xds/max-retries Service annotation (0 keeps Envoy's default)")
+	connectTimeout    = flag.Duration("default-connect-timeout", translator.DefaultConnectTimeout, "Timeout of new connections to the backends, overridable with the gateway.xds/connect-timeout Service annotation")
+	keepaliveTime     = flag.Duration("tcp-keepalive-time", 0, "Idle time of backend connections before TCP keepalive probes are sent, in whole seconds, overridable with the gateway.xds/tcp-keepalive-time Service annotation (0 keeps the OS default)")
+	keepaliveInterval = flag.Duration("tcp-keepalive-interval", 0, "Time between TCP keepalive probes of backend connections, in whole seconds, overridable with the gateway.xds/tcp-keepalive-interval Service annotation (0 keeps the OS default)")
+	keepaliveProbes   = flag.Uint("tcp-keepalive-probes", 0, "Number of unanswered TCP keepalive probes before a backend connection is dropped, overridable with the gateway.xds/tcp-keepalive-probes Service annotation (0 keeps the OS default)")
+	dsKeepaliveTime   = flag.Duration("listener-tcp-keepalive-time", 0, "Idle time of downstream connections before TCP keepalive probes are sent, in whole seconds (0 keeps the OS default)")
+	dsKeepaliveIntvl  = flag.Duration("listener-tcp-keepalive-interval", 0, "Time between TCP keepalive probes of downstream connections, in whole seconds (0 keeps the OS default)")
+	dsKeepaliveProbes = flag.Uint("listener-tcp-keepalive-probes", 0, "Number of unanswered TCP keepalive probes before a downstream connection is dropped (0 keeps the OS default)")
+	compression       = flag.String("compression", "", "Compress responses with gzip or brotli, unless an HTTPRoute sets the gateway.xds/compression-disabled annotation (disabled if empty)")
+	bufferMaxRequest  = flag.Uint("buffer-max-request-bytes", 0, "Buffer requests up to this size before forwarding them and reject larger ones with a 413, overridable with the gateway.xds/buffer-max-request-bytes HTTPRoute annotation (0 streams requests)")
+	enableWebSockets  = flag.Bool("enable-websockets", false, "Accept WebSocket upgrades on all HTTPRoutes, instead of only those with the gateway.xds/websocket annotation")
+	routeConcurrency  = flag.Int("route-concurrency", runtime.GOMAXPROCS(0), "Number of HTTPRoutes of a listener translated in parallel")
+	clusterDomain     = flag.String("cluster-domain", translator.DefaultClusterDomain, "DNS domain of the Kubernetes cluster, used for Services with the gateway.xds/resolution: dns annotation")
+	lbPolicy          = flag.String("default-lb-policy", "ROUND_ROBIN", "Load balancing policy of the backends: ROUND_ROBIN, LEAST_REQUEST, RANDOM, RING_HASH or MAGLEV, overridable with the gateway.xds/lb-policy Service annotation")
+	controllerName    = flag.String("controller-name", translator.DefaultControllerName, "Controller name of the GatewayClasses whose Gateways are translated, Gateways of other classes are skipped")
+	rlsCluster        = flag.String("rls-cluster", "", "host:port address of an external gRPC rate limit service enforcing the gateway.xds/rate-limit-descriptors of HTTPRoutes")
+	rlsDomain         = flag.String("rls-domain", "gateway", "Domain of the rate limits in the rate limit service")
+	extAuthzCluster   = flag.String("ext-authz-cluster", "", "host:port address of an external HTTP authorization service authorizing all requests, unless an HTTPRoute sets the gateway.xds/ext-authz-disabled annotation")
+	extAuthzPath      = flag.String("ext-authz-path", "", "Path prefix of the requests to the external authorization service")
+	extAuthzAllow     = flag.Bool("ext-authz-failure-mode-allow", false, "Allow requests if the external authorization service is unavailable instead of denying them")
+	defaultBackend    = flag.String("default-backend", "", "namespace/name:port of a Service that the requests matching no route are forwarded to, instead of answering them with --not-found-status")
+	notFoundStatus    = flag.Uint("not-found-status", translator.DefaultNotFoundStatus, "Status of the responses to requests that match no route")
+	notFoundBody      = flag.String("not-found-body", translator.DefaultNotFoundBody, "Body of the responses to requests that match no route")
+	tlsMinVersion     = flag.String("tls-min-version", translator.DefaultTLSMinVersion, "Minimum TLS version of listeners terminating TLS: 1.0, 1.1, 1.2 or 1.3, overridable with the gateway.xds/tls-min-version listener TLS option")
+	tlsMaxVersion     = flag.String("tls-max-version", "", "Maximum TLS version of listeners terminating TLS: 1.0, 1.1, 1.2 or 1.3, overridable with the gateway.xds/tls-max-version listener TLS option (Envoy's default if empty)")
+	tracing           = flag.String("tracing", "", "host:port address of an OpenTelemetry collector that the spans of traced requests are exported to, tracing is disabled if empty")
+	tracingSampling   = flag.Float64("tracing-sampling", translator.DefaultTracingSampling, "Percentage of requests that are traced")
+	httpsRedirect     = flag.Bool("https-redirect", false, "Redirect HTTP listeners to HTTPS if an HTTPS listener of the Gateway has the same hostname")
+	bestEffort        = flag.Bool("best-effort", false, "Log translation errors and carry on with the resources that could be translated instead of exiting")
+	validateOnly      = flag.Bool("validate-only", false, "Translate and validate the Envoy XDS configuration and print a report of the problems found, without writing output or status")
+	diffFile          = flag.String("diff", "", "Compare the translated Envoy XDS configuration with a JSON snapshot previously written to --output, print the added, removed and modified listeners, clusters and routes and exit with 1 if they differ, without writing output or status")
+	timestampVersions = flag.Bool("timestamp-versions", false, "Version snapshots by the current time instead of a hash of their resources, so that Envoy reloads the configuration on every run")
+	debounceFor       = flag.Duration("debounce", 500*time.Millisecond, "Time to wait for further changes before re-translating in --watch mode")
 )
 
 func main() {
 	flag.Parse()
 
-	if *gatewayName == "" || *gatewayNs == "" {
-		fmt.Println("Error: --gateway and --namespace are required")
+	logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	setupLogging(logger)
 
-	usr, err := user.Current()
-	if err != nil {
-		fmt.Printf("Failed to get current user: %v\n", err)
-		os.Exit(1)
+	if *gatewayNs == "" {
+		fatal("--namespace is required")
+	}
+	if *outputFmt != outputFormatJSON && *outputFmt != outputFormatYAML {
+		fatal("unsupported --output-format, must be json or yaml", "format", *outputFmt)
+	}
+	if *bootstrap && *serve {
+		fatal("--bootstrap and --serve are mutually exclusive")
+	}
+	if *outputDir != "" && (*serve || *bootstrap) {
+		fatal("--output-dir is mutually exclusive with --serve and --bootstrap")
+	}
+	if *gzipOutput && (*outputDir != "" || *serve) {
+		fatal("--gzip is mutually exclusive with --output-dir and --serve")
+	}
+	if *gzipOutput {
+		*outputFile = gzipOutputPath(*outputFile)
+	}
+	if *watch && !*serve {
+		fatal("--watch requires --serve")
+	}
+	if *nodePerGateway && !*serve {
+		fatal("--node-per-gateway requires --serve")
 	}
-	kubeconfig := filepath.Join(usr.HomeDir, ".kube", "config")
+	if *validateOnly && (*serve || *bootstrap) {
+		fatal("--validate-only is mutually exclusive with --serve and --bootstrap")
+	}
+	if *diffFile != "" && (*serve || *bootstrap || *validateOnly) {
+		fatal("--diff is mutually exclusive with --serve, --bootstrap and --validate-only")
+	}
+	translatorOptions := translator.Options{
+		Logger:                   logger,
+		ControllerName:           *controllerName,
+		HTTPSRedirect:            *httpsRedirect,
+		AccessLog:                *accessLog,
+		AccessLogFormat:          *accessLogFmt,
+		AccessLogFormatString:    *accessLogStr,
+		DefaultLBPolicy:          *lbPolicy,
+		RateLimitService:         *rlsCluster,
+		RateLimitDomain:          *rlsDomain,
+		ExtAuthzService:          *extAuthzCluster,
+		ExtAuthzPathPrefix:       *extAuthzPath,
+		ExtAuthzFailureModeAllow: *extAuthzAllow,
+		Compression:              *compression,
+		BufferMaxRequestBytes:    uint32(*bufferMaxRequest),
+		WebSockets:               *enableWebSockets,
+		DefaultBackend:           *defaultBackend,
+		NotFoundStatus:           uint32(*notFoundStatus),
+		NotFoundBody:             *notFoundBody,
+		TLSMinVersion:            *tlsMinVersion,
+		TLSMaxVersion:            *tlsMaxVersion,
+		TracingCollector:         *tracing,
+		TracingSampling:          *tracingSampling,
+		ConnectTimeout:           *connectTimeout,
+		RouteConcurrency:         *routeConcurrency,
+		ClusterDomain:            *clusterDomain,
+		TCPKeepalive: translator.TCPKeepalive{
+			Time:     *keepaliveTime,
+			Interval: *keepaliveInterval,
+			Probes:   uint32(*keepaliveProbes),
+		},
+		ListenerTCPKeepalive: translator.TCPKeepalive{
+			Time:     *dsKeepaliveTime,
+			Interval: *dsKeepaliveIntvl,
+			Probes:   uint32(*dsKeepaliveProbes),
+		},
+		CircuitBreakers: translator.CircuitBreakers{
+			MaxConnections:     uint32(*maxConns),
+			MaxPendingRequests: uint32(*maxPending),
+			MaxRequests:        uint32(*maxRequests),
+			MaxRetries:         uint32(*maxRetries),
+		},
+	}
+	if err := translatorOptions.Validate(); err != nil {
+		fatal("invalid options", "err", err)
+	}
+
+	// The health endpoints are served before the informer caches sync, so that the
+	// generator is live but not ready while they do.
+	serverHealth := &health{}
+	if *serve && *healthAddr != "" {
+		healthCtx, stopHealth := context.WithCancel(context.Background())
+		defer stopHealth()
+		go func() {
+			if err := serveHealth(healthCtx, *healthAddr, serverHealth); err != nil {
+				fatal("failed to serve health endpoints", "err", err)
+			}
+		}()
+	}
+
 	// Build Kubernetes config
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := buildRESTConfig(*kubeconfig, *kubeContext)
 	if err != nil {
-		fmt.Printf("Error building kubeconfig: %v\n", err)
-		os.Exit(1)
+		fatal("failed to build kubeconfig", "err", err)
 	}
 
 	kubeClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		fmt.Printf("Error creating kube client: %v\n", err)
+		fatal("failed to create kube client", "err", err)
 	}
 	// Create Gateway API clientset
 	gatewayClientset, err := gatewayclient.NewForConfig(config)
 	if err != nil {
-		fmt.Printf("Error creating Gateway API clientset: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Fetch Gateway resource
-	gw, err := gatewayClientset.GatewayV1().Gateways(*gatewayNs).Get(context.Background(), *gatewayName, metav1.GetOptions{})
-	if err != nil {
-		fmt.Printf("Error fetching Gateway %s/%s: %v\n", *gatewayNs, *gatewayName, err)
-		os.Exit(1)
+		fatal("failed to create Gateway API clientset", "err", err)
 	}
 
-	fmt.Printf("Fetched Gateway: %s/%s\n", gw.Namespace, gw.Name)
-
 	sharedInformers := informers.NewSharedInformerFactory(kubeClient, 60*time.Second)
 	sharedGwInformers := gatewayinformers.NewSharedInformerFactory(gatewayClientset, 60*time.Second)
 
@@ -80,67 +221,375 @@ func main() {
 	go sharedInformers.Start(stopCh)
 
 	hasSynced := []k8scache.InformerSynced{
-		// sharedInformers.Core().V1().Namespaces().Informer().HasSynced,
+		sharedInformers.Core().V1().Namespaces().Informer().HasSynced,
 		sharedInformers.Core().V1().Services().Informer().HasSynced,
-		// sharedInformers.Core().V1().Secrets().Informer().HasSynced,
+		sharedInformers.Discovery().V1().EndpointSlices().Informer().HasSynced,
+		sharedInformers.Core().V1().Secrets().Informer().HasSynced,
+		sharedInformers.Core().V1().ConfigMaps().Informer().HasSynced,
 		sharedGwInformers.Gateway().V1().Gateways().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1().GatewayClasses().Informer().HasSynced,
 		sharedGwInformers.Gateway().V1().HTTPRoutes().Informer().HasSynced,
-		// sharedGwInformers.Gateway().V1beta1().ReferenceGrants().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1().GRPCRoutes().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1alpha2().TCPRoutes().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1alpha2().TLSRoutes().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1alpha2().UDPRoutes().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1beta1().ReferenceGrants().Informer().HasSynced,
+		sharedGwInformers.Gateway().V1().BackendTLSPolicies().Informer().HasSynced,
+	}
+	// trigger is signaled on every change to a watched resource in --watch mode.
+	trigger := make(chan struct{}, 1)
+	// changes records what changed, so that only the affected Gateways are translated again.
+	changes := newChangeSet()
+	if *watch {
+		err := registerEventHandlers(trigger, changes,
+			// Namespace label changes can change which routes listener selectors allow.
+			sharedInformers.Core().V1().Namespaces().Informer(),
+			sharedInformers.Core().V1().Services().Informer(),
+			sharedInformers.Discovery().V1().EndpointSlices().Informer(),
+			sharedInformers.Core().V1().Secrets().Informer(),
+			sharedInformers.Core().V1().ConfigMaps().Informer(),
+			sharedGwInformers.Gateway().V1().Gateways().Informer(),
+			sharedGwInformers.Gateway().V1().GatewayClasses().Informer(),
+			sharedGwInformers.Gateway().V1().HTTPRoutes().Informer(),
+			sharedGwInformers.Gateway().V1().GRPCRoutes().Informer(),
+			sharedGwInformers.Gateway().V1alpha2().TCPRoutes().Informer(),
+			sharedGwInformers.Gateway().V1alpha2().TLSRoutes().Informer(),
+			sharedGwInformers.Gateway().V1alpha2().UDPRoutes().Informer(),
+			sharedGwInformers.Gateway().V1beta1().ReferenceGrants().Informer(),
+			sharedGwInformers.Gateway().V1().BackendTLSPolicies().Informer(),
+		)
+		if err != nil {
+			fatal("failed to register event handlers", "err", err)
+		}
 	}
 	k8scache.WaitForNamedCacheSync("test", stopCh, hasSynced...)
 
+	// Statuses are written through the Gateway API client, which is left out in
+	// --validate-only and --diff modes so that they have no side effects.
+	var statusClient gatewayclient.Interface = gatewayClientset
+	if *validateOnly || *diffFile != "" {
+		statusClient = nil
+	}
+
 	// Initialize translator
 	translator := translator.New(
 		kubeClient,
-		gatewayClientset,
+		statusClient,
 		sharedInformers.Core().V1().Namespaces().Lister(),
 		sharedInformers.Core().V1().Services().Lister(),
+		sharedInformers.Discovery().V1().EndpointSlices().Lister(),
 		sharedInformers.Core().V1().Secrets().Lister(),
+		sharedInformers.Core().V1().ConfigMaps().Lister(),
 		sharedGwInformers.Gateway().V1().Gateways().Lister(),
+		sharedGwInformers.Gateway().V1().GatewayClasses().Lister(),
 		sharedGwInformers.Gateway().V1().HTTPRoutes().Lister(),
+		sharedGwInformers.Gateway().V1().GRPCRoutes().Lister(),
+		sharedGwInformers.Gateway().V1alpha2().TCPRoutes().Lister(),
+		sharedGwInformers.Gateway().V1alpha2().TLSRoutes().Lister(),
+		sharedGwInformers.Gateway().V1alpha2().UDPRoutes().Lister(),
 		sharedGwInformers.Gateway().V1beta1().ReferenceGrants().Lister(),
+		sharedGwInformers.Gateway().V1().BackendTLSPolicies().Lister(),
+		translatorOptions,
 	)
 
-	// Translate Gateway and HTTPRoute to Envoy XDS
-	resources, err := translator.TranslateGatewayToXDS(context.Background(), gw)
+	gateways, err := getGateways(sharedGwInformers.Gateway().V1().Gateways().Lister(), *gatewayNs, *gatewayName)
 	if err != nil {
-		fmt.Printf("Error translating Gateway to XDS: %v\n", err)
-		os.Exit(1)
+		fatal("failed to fetch Gateways", "err", err)
+	}
+	for _, gw := range gateways {
+		slog.Info("fetched Gateway", "namespace", gw.Namespace, "name", gw.Name)
 	}
 
-	snapshot, err := generateXDS(resources)
+	var generatorMetrics *metrics
+	if *serve && *metricsAddr != "" {
+		generatorMetrics, err = newMetrics(prometheus.DefaultRegisterer)
+		if err != nil {
+			fatal("failed to create metrics", "err", err)
+		}
+	}
+
+	// Translate Gateways and their routes to Envoy XDS
+	start := time.Now()
+	resourcesByNode, err := translateNodes(context.Background(), translator, gateways, *nodeID, *nodePerGateway)
+	generatorMetrics.observeTranslation(time.Since(start), err)
 	if err != nil {
-		fmt.Printf("Error generating XDS: %v\n", err)
-		os.Exit(1)
+		if !*bestEffort {
+			logErrors("failed to translate Gateways to XDS", err)
+			os.Exit(1)
+		}
+		logErrors("skipped resources that failed to translate", err)
+	}
+	// All Gateways are merged into the resources of --node-id outside of --serve mode.
+	resources := resourcesByNode[*nodeID]
+
+	if *validateOnly {
+		snapshot, err := generateXDS(resources, *timestampVersions)
+		if err != nil {
+			fatal("failed to generate XDS", "err", err)
+		}
+		report := validateResources(resources, snapshot)
+		reportOutput, err := marshalValidationReport(report)
+		if err != nil {
+			fatal("failed to marshal validation report", "err", err)
+		}
+		fmt.Println(string(reportOutput))
+		if !report.Valid {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *bootstrap {
+		bs, err := buildBootstrap(resources, *nodeID, *nodeCluster, uint32(*adminPort))
+		if err != nil {
+			fatal("failed to build bootstrap config", "err", err)
+		}
+		bootstrapOutput, err := marshalBootstrap(bs, *outputFmt)
+		if err != nil {
+			fatal("failed to marshal bootstrap config", "format", *outputFmt, "err", err)
+		}
+		if err := writeOutput(*outputFile, bootstrapOutput, *gzipOutput); err != nil {
+			fatal("failed to write output file", "file", *outputFile, "err", err)
+		}
+		slog.Info("wrote bootstrap config", "file", *outputFile)
+		return
+	}
+
+	if *serve {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		snapshots, err := generateNodeSnapshots(resourcesByNode, *timestampVersions)
+		if err != nil {
+			fatal("failed to generate XDS", "err", err)
+		}
+		snapshotCache, err := newSnapshotCache(ctx, snapshots)
+		if err != nil {
+			fatal("failed to create snapshot cache", "err", err)
+		}
+		generatorMetrics.setSnapshotResources(resourcesByNode)
+		serverHealth.setReady()
+		if *metricsAddr != "" {
+			go func() {
+				if err := serveMetrics(ctx, *metricsAddr); err != nil {
+					fatal("failed to serve metrics", "err", err)
+				}
+			}()
+		}
+		if *watch {
+			r := &reconciler{
+				translator:        translator,
+				gatewayLister:     sharedGwInformers.Gateway().V1().Gateways().Lister(),
+				namespace:         *gatewayNs,
+				name:              *gatewayName,
+				nodeID:            *nodeID,
+				nodePerGateway:    *nodePerGateway,
+				snapshotCache:     snapshotCache,
+				resourcesByNode:   resourcesByNode,
+				timestampVersions: *timestampVersions,
+				metrics:           generatorMetrics,
+				bestEffort:        *bestEffort,
+				changes:           changes,
+			}
+			go debounce(ctx, trigger, *debounceFor, func() { r.reconcile(ctx) })
+		}
+		if err := serveXDS(ctx, *listenAddr, snapshotCache); err != nil {
+			fatal("failed to serve XDS", "err", err)
+		}
+		return
+	}
+
+	snapshot, err := generateXDS(resources, *timestampVersions)
+	if err != nil {
+		fatal("failed to generate XDS", "err", err)
 	}
 	if err := snapshot.Consistent(); err != nil {
-		fmt.Printf("Snapshot is inconsistent: %v\n", err)
-		os.Exit(1)
+		fatal("snapshot is inconsistent", "err", err)
 	}
 
-	// Serialize snapshot to JSON
-	xdsJSON, err := json.MarshalIndent(snapshot, "", "  ")
+	if *diffFile != "" {
+		previous, err := os.ReadFile(*diffFile)
+		if err != nil {
+			fatal("failed to read snapshot file", "file", *diffFile, "err", err)
+		}
+		oldResources, err := loadSnapshotResources(previous)
+		if err != nil {
+			fatal("failed to load snapshot file", "file", *diffFile, "err", err)
+		}
+		newResources, err := snapshotResources(snapshot)
+		if err != nil {
+			fatal("failed to load translated snapshot", "err", err)
+		}
+		changes := diffSnapshots(oldResources, newResources)
+		fmt.Print(formatDiff(changes))
+		if len(changes) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *outputDir != "" {
+		if err := writeSnapshotFiles(snapshot, *outputDir, *outputFmt); err != nil {
+			fatal("failed to write output files", "dir", *outputDir, "err", err)
+		}
+		slog.Info("wrote XDS", "dir", *outputDir)
+		return
+	}
+
+	// Serialize snapshot
+	xdsOutput, err := marshalSnapshot(snapshot, *outputFmt)
 	if err != nil {
-		fmt.Printf("Error marshaling XDS to JSON: %v\n", err)
-		os.Exit(1)
+		fatal("failed to marshal XDS", "format", *outputFmt, "err", err)
 	}
 	// Write XDS to output file
-	err = os.WriteFile(*outputFile, xdsJSON, 0644)
+	err = writeOutput(*outputFile, xdsOutput, *gzipOutput)
 	if err != nil {
-		fmt.Printf("Error writing to output file %s: %v\n", *outputFile, err)
-		os.Exit(1)
+		fatal("failed to write output file", "file", *outputFile, "err", err)
 	}
 
-	fmt.Printf("Successfully wrote XDS to %s\n", *outputFile)
+	slog.Info("wrote XDS", "file", *outputFile)
 }
 
-func generateXDS(resources map[resourcev3.Type][]envoyproxytypes.Resource) (*cache.Snapshot, error) {
-	version := time.Now().Format(time.RFC3339Nano)
+// generateXDS builds a snapshot of the resources. Its version is a hash of the resources, so
+// that identical resources always get the same version, or the current time if timestampVersion is set.
+// Every resource is also versioned by its own hash, so that delta xDS clients only receive the
+// resources that changed.
+func generateXDS(resources map[resourcev3.Type][]envoyproxytypes.Resource, timestampVersion bool) (*cache.Snapshot, error) {
+	var version string
+	if timestampVersion {
+		version = time.Now().Format(time.RFC3339Nano)
+	} else {
+		var err error
+		version, err = resourcesVersion(resources)
+		if err != nil {
+			return nil, err
+		}
+	}
 	snapshot, err := cache.NewSnapshot(version, resources)
 	if err != nil {
-		fmt.Printf("Error generating snapshot: %v\n", err)
-		os.Exit(1)
+		return nil, err
+	}
+	// The snapshot would otherwise derive the versions from the binary encoding, which
+	// depends on map iteration order.
+	snapshot.VersionMap, err = resourceVersions(resources)
+	if err != nil {
+		return nil, err
 	}
-
 	return snapshot, nil
 }
+
+// resourceVersions returns the SHA-256 hash of every resource, keyed by type and name.
+func resourceVersions(resources map[resourcev3.Type][]envoyproxytypes.Resource) (map[string]map[string]string, error) {
+	versions := make(map[string]map[string]string, len(resources))
+	for typeURL, typeResources := range resources {
+		versions[typeURL] = make(map[string]string, len(typeResources))
+		for _, res := range typeResources {
+			resourceBytes, err := marshalDeterministic(res)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s %s: %w", typeURL, cache.GetResourceName(res), err)
+			}
+			hash := sha256.Sum256(resourceBytes)
+			versions[typeURL][cache.GetResourceName(res)] = hex.EncodeToString(hash[:])
+		}
+	}
+	return versions, nil
+}
+
+// resourcesVersion returns the SHA-256 hash of the resources. The order of the resources
+// within a type is not significant.
+func resourcesVersion(resources map[resourcev3.Type][]envoyproxytypes.Resource) (string, error) {
+	typeURLs := make([]resourcev3.Type, 0, len(resources))
+	for typeURL := range resources {
+		typeURLs = append(typeURLs, typeURL)
+	}
+	sort.Strings(typeURLs)
+
+	hash := sha256.New()
+	for _, typeURL := range typeURLs {
+		typeResources := slices.Clone(resources[typeURL])
+		sort.Slice(typeResources, func(i, j int) bool {
+			return cache.GetResourceName(typeResources[i]) < cache.GetResourceName(typeResources[j])
+		})
+		for _, res := range typeResources {
+			resourceBytes, err := marshalDeterministic(res)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal %s %s: %w", typeURL, cache.GetResourceName(res), err)
+			}
+			fmt.Fprintf(hash, "%s\n%s\n%d\n", typeURL, cache.GetResourceName(res), len(resourceBytes))
+			hash.Write(resourceBytes)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// marshalDeterministic returns the binary encoding of the resource with the entries of its
+// maps sorted by key, so that identical resources are encoded identically across runs and
+// replicas built with the same protobuf library. protojson can't be used instead, as its
+// output deliberately varies between builds. The payloads of Any fields were encoded by
+// anypb.New without sorting their maps, so they are encoded again.
+func marshalDeterministic(res envoyproxytypes.Resource) ([]byte, error) {
+	msg := proto.Clone(res)
+	if err := encodeAnysDeterministic(msg.ProtoReflect()); err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
+// encodeAnysDeterministic encodes the payloads of the Any fields of the message, and of the
+// messages within it, again with the entries of their maps sorted by key.
+func encodeAnysDeterministic(msg protoreflect.Message) error {
+	if anyMsg, ok := msg.Interface().(*anypb.Any); ok {
+		payload, err := anyMsg.UnmarshalNew()
+		if err != nil {
+			return err
+		}
+		if err := encodeAnysDeterministic(payload.ProtoReflect()); err != nil {
+			return err
+		}
+		anyMsg.Value, err = proto.MarshalOptions{Deterministic: true}.Marshal(payload)
+		return err
+	}
+	var err error
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList() && field.Message() != nil:
+			list := value.List()
+			for i := 0; i < list.Len() && err == nil; i++ {
+				err = encodeAnysDeterministic(list.Get(i).Message())
+			}
+		case field.IsMap() && field.MapValue().Message() != nil:
+			value.Map().Range(func(_ protoreflect.MapKey, mapValue protoreflect.Value) bool {
+				err = encodeAnysDeterministic(mapValue.Message())
+				return err == nil
+			})
+		case field.Message() != nil && !field.IsList() && !field.IsMap():
+			err = encodeAnysDeterministic(value.Message())
+		}
+		return err == nil
+	})
+	return err
+}
+
+// getGateways returns the named Gateway, or all Gateways in the namespace sorted by name
+// if name is empty.
+func getGateways(gatewayLister gatewaylisters.GatewayLister, namespace, name string) ([]*gatewayv1.Gateway, error) {
+	if name != "" {
+		gw, err := gatewayLister.Gateways(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return []*gatewayv1.Gateway{gw}, nil
+	}
+
+	gateways, err := gatewayLister.Gateways(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	if len(gateways) == 0 {
+		return nil, fmt.Errorf("no Gateways found in namespace %s", namespace)
+	}
+	sort.Slice(gateways, func(i, j int) bool {
+		return gateways[i].Name < gateways[j].Name
+	})
+	return gateways, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+func TestGenerateXDSVersionMap(t *testing.T) {
+	resources := testResources(t)
+	snapshot, err := generateXDS(resources, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for typeURL, typeResources := range resources {
+		for _, res := range typeResources {
+			if snapshot.VersionMap[typeURL][cache.GetResourceName(res)] == "" {
+				t.Errorf("%s %s has no version", typeURL, cache.GetResourceName(res))
+			}
+		}
+	}
+
+	// Identical resources get identical versions.
+	again, err := generateXDS(testResources(t), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.GetVersion(resourcev3.ClusterType) != snapshot.GetVersion(resourcev3.ClusterType) {
+		t.Errorf("version = %s, want %s for identical resources", again.GetVersion(resourcev3.ClusterType), snapshot.GetVersion(resourcev3.ClusterType))
+	}
+
+	// Changing a cluster only bumps the version of that cluster.
+	changed := testResources(t)
+	secondCluster := &clusterv3.Cluster{
+		Name:                 "default_other_core_Service_80",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STRICT_DNS},
+	}
+	resources[resourcev3.ClusterType] = append(resources[resourcev3.ClusterType], secondCluster)
+	if snapshot, err = generateXDS(resources, false); err != nil {
+		t.Fatal(err)
+	}
+	changed[resourcev3.ClusterType][0].(*clusterv3.Cluster).ConnectTimeout = durationpb.New(3e9)
+	changed[resourcev3.ClusterType] = append(changed[resourcev3.ClusterType], secondCluster)
+	changedSnapshot, err := generateXDS(changed, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertChangedVersions(t, snapshot, changedSnapshot, changed, map[resourcev3.Type]map[string]bool{
+		resourcev3.ClusterType: {testClusterName: true},
+	})
+}
+
+// assertChangedVersions checks that exactly the resources in want, by type and name, have a
+// different version in after than in before.
+func assertChangedVersions(t *testing.T, before, after *cache.Snapshot, resources map[resourcev3.Type][]envoyproxytypes.Resource, want map[resourcev3.Type]map[string]bool) {
+	t.Helper()
+	for typeURL, typeResources := range resources {
+		for _, res := range typeResources {
+			name := cache.GetResourceName(res)
+			changed := before.VersionMap[typeURL][name] != after.VersionMap[typeURL][name]
+			if changed != want[typeURL][name] {
+				t.Errorf("%s %s version changed = %v, want %v", typeURL, name, changed, want[typeURL][name])
+			}
+		}
+	}
+}
+
+// The versions of the resources are hashes even if the snapshot is versioned by time.
+func TestGenerateXDSTimestampVersion(t *testing.T) {
+	hashed, err := generateXDS(testResources(t), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timestamped, err := generateXDS(testResources(t), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timestamped.GetVersion(resourcev3.ClusterType) == hashed.GetVersion(resourcev3.ClusterType) {
+		t.Error("the timestamped snapshot has the hashed version")
+	}
+	if got, want := timestamped.VersionMap[resourcev3.ClusterType][testClusterName], hashed.VersionMap[resourcev3.ClusterType][testClusterName]; got != want {
+		t.Errorf("cluster version = %s, want %s", got, want)
+	}
+}
+
+func TestGenerateXDSVersionOfTranslatedGateway(t *testing.T) {
+	gw := testGateway("gw", 80)
+	tl, _ := newTestTranslator(t, translator.Options{}, testGatewayClass(), gw, testService("backend"),
+		testEndpointSlice("backend-1", "backend", "10.1.0.1", "10.1.0.2"), testHTTPRoute("web", "gw", "backend"))
+
+	// Translating the same Gateway twice yields the same version, so Envoy doesn't reload.
+	var versions []string
+	for range 2 {
+		resources, err := tl.TranslateGatewayToXDS(context.Background(), gw)
+		if err != nil {
+			t.Fatalf("TranslateGatewayToXDS() error = %v", err)
+		}
+		snapshot, err := generateXDS(resources, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, snapshot.GetVersion(resourcev3.ListenerType))
+	}
+	if versions[0] == "" || versions[0] != versions[1] {
+		t.Errorf("versions = %q, want the same version twice", versions)
+	}
+}
+
+// The payloads of Any fields are encoded without sorting their maps, which must not change
+// the version of the resources.
+func TestResourcesVersionIgnoresMapOrder(t *testing.T) {
+	fields := make(map[string]interface{})
+	for i := range 32 {
+		fields[fmt.Sprintf("key-%d", i)] = i
+	}
+	routeConfiguration := func() *routev3.RouteConfiguration {
+		metadata, err := structpb.NewStruct(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metadataAny, err := anypb.New(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &routev3.RouteConfiguration{
+			Name: "route-80",
+			VirtualHosts: []*routev3.VirtualHost{{
+				Name:                 "vh",
+				Domains:              []string{"*"},
+				TypedPerFilterConfig: map[string]*anypb.Any{"a": metadataAny, "b": metadataAny, "c": metadataAny},
+			}},
+		}
+	}
+
+	want, err := resourcesVersion(map[resourcev3.Type][]envoyproxytypes.Resource{resourcev3.RouteType: {routeConfiguration()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		got, err := resourcesVersion(map[resourcev3.Type][]envoyproxytypes.Resource{resourcev3.RouteType: {routeConfiguration()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("version = %s, want %s for the same resources", got, want)
+		}
+	}
+}
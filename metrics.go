@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metrics are the Prometheus metrics of the generator in --serve mode. A nil *metrics
+// records nothing.
+type metrics struct {
+	translations        prometheus.Counter
+	translationErrors   prometheus.Counter
+	translationDuration prometheus.Histogram
+	snapshotResources   *prometheus.GaugeVec
+}
+
+// newMetrics creates the metrics and registers them with registerer.
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		translations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gateway_xds_translations_total",
+			Help: "Number of translations of the Gateways to xDS resources.",
+		}),
+		translationErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gateway_xds_translation_errors_total",
+			Help: "Number of translations of the Gateways to xDS resources that failed.",
+		}),
+		translationDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "gateway_xds_translation_duration_seconds",
+			Help:    "Duration of the translations of the Gateways to xDS resources.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		snapshotResources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gateway_xds_snapshot_resources",
+			Help: "Number of resources in the current snapshots of all nodes, by xDS type.",
+		}, []string{"type"}),
+	}
+	for _, collector := range []prometheus.Collector{m.translations, m.translationErrors, m.translationDuration, m.snapshotResources} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// observeTranslation records a translation that took duration and failed with err, if not nil.
+func (m *metrics) observeTranslation(duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.translations.Inc()
+	m.translationDuration.Observe(duration.Seconds())
+	if err != nil {
+		m.translationErrors.Inc()
+	}
+}
+
+// setSnapshotResources records the number of resources of each type in the current snapshots
+// of all nodes.
+func (m *metrics) setSnapshotResources(resourcesByNode map[string]map[resourcev3.Type][]envoyproxytypes.Resource) {
+	if m == nil {
+		return
+	}
+	for typeURL, key := range yamlResourceKeys {
+		count := 0
+		for _, resources := range resourcesByNode {
+			count += len(resources[typeURL])
+		}
+		m.snapshotResources.WithLabelValues(key).Set(float64(count))
+	}
+}
+
+// serveMetrics serves the metrics of the default Prometheus registry at /metrics on
+// listenAddr until ctx is cancelled.
+func serveMetrics(ctx context.Context, listenAddr string) error {
+	lis, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("serving metrics", "address", lis.Addr().String())
+	if err := server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := newMetrics(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.observeTranslation(20*time.Millisecond, nil)
+	m.observeTranslation(10*time.Millisecond, errors.New("translation failed"))
+	m.setSnapshotResources(map[string]map[resourcev3.Type][]envoyproxytypes.Resource{"envoy": testResources(t)})
+
+	server := httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"gateway_xds_translations_total 2",
+		"gateway_xds_translation_errors_total 1",
+		"gateway_xds_translation_duration_seconds_count 2",
+		`gateway_xds_snapshot_resources{type="clusters"} 1`,
+		`gateway_xds_snapshot_resources{type="listeners"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	// Metrics are only created in --serve mode, the other modes record nothing.
+	var m *metrics
+	m.observeTranslation(time.Second, nil)
+	m.setSnapshotResources(map[string]map[resourcev3.Type][]envoyproxytypes.Resource{"envoy": testResources(t)})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+// gatewayNodeID returns the Envoy node ID that the resources of gw are served to with
+// --node-per-gateway.
+func gatewayNodeID(gw *gatewayv1.Gateway) string {
+	return gw.Namespace + "/" + gw.Name
+}
+
+// translateNodes translates the Gateways to the resources served to each Envoy node ID, as
+// merged by mergeNodes. The resources of the nodes are returned along with any translation
+// errors, so that callers can carry on in best-effort mode.
+func translateNodes(ctx context.Context, t *translator.Translator, gateways []*gatewayv1.Gateway, nodeID string, perGateway bool) (map[string]map[resourcev3.Type][]envoyproxytypes.Resource, error) {
+	resourcesByGateway := make([]map[resourcev3.Type][]envoyproxytypes.Resource, len(gateways))
+	var errs []error
+	for i, gw := range gateways {
+		resources, err := t.TranslateManagedGatewayToXDS(ctx, gw)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		resourcesByGateway[i] = resources
+	}
+	resourcesByNode, err := mergeNodes(gateways, resourcesByGateway, nodeID, perGateway)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return resourcesByNode, errors.Join(errs...)
+}
+
+// mergeNodes merges the resources of the Gateways, resourcesByGateway[i] being the resources
+// of gateways[i], into the resources of each Envoy node ID. All Gateways are merged into the
+// resources of nodeID, unless perGateway is set, in which case every Gateway gets the node ID
+// returned by gatewayNodeID.
+func mergeNodes(gateways []*gatewayv1.Gateway, resourcesByGateway []map[resourcev3.Type][]envoyproxytypes.Resource, nodeID string, perGateway bool) (map[string]map[resourcev3.Type][]envoyproxytypes.Resource, error) {
+	if !perGateway {
+		resources, err := translator.MergeGatewayResources(gateways, resourcesByGateway)
+		return map[string]map[resourcev3.Type][]envoyproxytypes.Resource{nodeID: resources}, err
+	}
+
+	resourcesByNode := make(map[string]map[resourcev3.Type][]envoyproxytypes.Resource, len(gateways))
+	var errs []error
+	for i, gw := range gateways {
+		resources, err := translator.MergeGatewayResources(gateways[i:i+1], resourcesByGateway[i:i+1])
+		if err != nil {
+			errs = append(errs, err)
+		}
+		resourcesByNode[gatewayNodeID(gw)] = resources
+	}
+	return resourcesByNode, errors.Join(errs...)
+}
+
+// generateNodeSnapshots generates a consistent snapshot of the resources of each node ID.
+func generateNodeSnapshots(resourcesByNode map[string]map[resourcev3.Type][]envoyproxytypes.Resource, timestampVersion bool) (map[string]*cache.Snapshot, error) {
+	snapshots := make(map[string]*cache.Snapshot, len(resourcesByNode))
+	for node, resources := range resourcesByNode {
+		snapshot, err := generateXDS(resources, timestampVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate XDS for node %s: %w", node, err)
+		}
+		if err := snapshot.Consistent(); err != nil {
+			return nil, fmt.Errorf("snapshot of node %s is inconsistent: %w", node, err)
+		}
+		snapshots[node] = snapshot
+	}
+	return snapshots, nil
+}
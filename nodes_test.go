@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+// TestTranslateNodesBestEffort checks that the resources of the valid routes are returned
+// along with the errors of the invalid ones, which are logged one by one.
+func TestTranslateNodesBestEffort(t *testing.T) {
+	gw := testGateway("gw", 80)
+	broken := testHTTPRoute("broken", "gw", "legacy")
+	regex, path := gatewayv1.PathMatchRegularExpression, `/(a)\1`
+	broken.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: &regex, Value: &path}}}
+	otherBroken := broken.DeepCopy()
+	otherBroken.Name = "other-broken"
+	tl, _ := newTestTranslator(t, translator.Options{}, testGatewayClass(), gw,
+		testService("web"), testService("legacy"), testHTTPRoute("web", "gw", "web"), broken, otherBroken)
+
+	resourcesByNode, err := translateNodes(context.Background(), tl, []*gatewayv1.Gateway{gw}, "envoy", false)
+	if err == nil {
+		t.Fatal("translateNodes() error = nil, want the errors of the broken HTTPRoutes")
+	}
+	clusters := resourcesByNode["envoy"][resourcev3.ClusterType]
+	if len(clusters) != 1 || cache.GetResourceName(clusters[0]) != testClusterName {
+		t.Errorf("clusters = %v, want only %s", clusters, testClusterName)
+	}
+
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+	logErrors("skipped resources that failed to translate", err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged errors = %q, want one line per broken HTTPRoute", lines)
+	}
+	for _, route := range []string{"HTTPRoute default/broken ", "HTTPRoute default/other-broken "} {
+		if !slices.ContainsFunc(lines, func(line string) bool { return strings.Contains(line, route) }) {
+			t.Errorf("logged errors = %q, want an error of %s", lines, route)
+		}
+	}
+}
+
+// TestTranslateNodesPerGateway checks that every Gateway gets a snapshot of its own, served
+// to the node ID named after it.
+func TestTranslateNodesPerGateway(t *testing.T) {
+	gwA, gwB := testGateway("gw-a", 80), testGateway("gw-b", 8080)
+	tl, _ := newTestTranslator(t, translator.Options{}, testGatewayClass(), gwA, gwB,
+		testService("web"), testService("api"), testHTTPRoute("route-a", "gw-a", "web"), testHTTPRoute("route-b", "gw-b", "api"))
+
+	resourcesByNode, err := translateNodes(context.Background(), tl, []*gatewayv1.Gateway{gwA, gwB}, "envoy", true)
+	if err != nil {
+		t.Fatalf("translateNodes() error = %v", err)
+	}
+	snapshots, err := generateNodeSnapshots(resourcesByNode, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotCache, err := newSnapshotCache(context.Background(), snapshots)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for nodeID, want := range map[string]struct{ listener, cluster string }{
+		"default/gw-a": {listener: "listener-80", cluster: "default_web_core_Service_80"},
+		"default/gw-b": {listener: "listener-8080", cluster: "default_api_core_Service_80"},
+	} {
+		snapshot, err := snapshotCache.GetSnapshot(nodeID)
+		if err != nil {
+			t.Errorf("GetSnapshot(%q) error = %v", nodeID, err)
+			continue
+		}
+		if listeners := slices.Collect(maps.Keys(snapshot.GetResources(resourcev3.ListenerType))); !slices.Equal(listeners, []string{want.listener}) {
+			t.Errorf("listeners of node %s = %v, want %s", nodeID, listeners, want.listener)
+		}
+		if clusters := slices.Collect(maps.Keys(snapshot.GetResources(resourcev3.ClusterType))); !slices.Equal(clusters, []string{want.cluster}) {
+			t.Errorf("clusters of node %s = %v, want %s", nodeID, clusters, want.cluster)
+		}
+	}
+	// The merged node ID is not served in per-Gateway mode.
+	if _, err := snapshotCache.GetSnapshot("envoy"); err == nil {
+		t.Error("GetSnapshot(\"envoy\") error = nil, want no snapshot")
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputFormatJSON = "json"
+	outputFormatYAML = "yaml"
+)
+
+// yamlResourceKeys maps the xDS types to the top-level keys used in the YAML output.
+var yamlResourceKeys = map[resourcev3.Type]string{
+	resourcev3.ListenerType: "listeners",
+	resourcev3.ClusterType:  "clusters",
+	resourcev3.RouteType:    "routes",
+	resourcev3.EndpointType: "endpoints",
+	resourcev3.SecretType:   "secrets",
+}
+
+// stdoutOutput is the --output that writes to standard output instead of a file.
+const stdoutOutput = "-"
+
+// writeOutput writes data to the output file, or to standard output if output is
+// stdoutOutput. Log messages go to standard error, so they never mix with the output. If
+// compress is set, the data is gzip-compressed.
+func writeOutput(output string, data []byte, compress bool) error {
+	if compress {
+		var buf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buf)
+		if _, err := gzipWriter.Write(data); err != nil {
+			return err
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	if output == stdoutOutput {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0644)
+}
+
+// gzipOutputPath returns the name of the gzip-compressed output file, which gets a .gz
+// suffix unless it already has one.
+func gzipOutputPath(output string) string {
+	if output == stdoutOutput || strings.HasSuffix(output, ".gz") {
+		return output
+	}
+	return output + ".gz"
+}
+
+// marshalSnapshot serializes the snapshot in the given output format.
+func marshalSnapshot(snapshot *cache.Snapshot, format string) ([]byte, error) {
+	switch format {
+	case outputFormatJSON:
+		return json.MarshalIndent(snapshot, "", "  ")
+	case outputFormatYAML:
+		return marshalSnapshotYAML(snapshot)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// marshalSnapshotYAML serializes the snapshot resources as YAML, grouped by xDS type.
+// Resources are converted with protojson first so that Any-typed fields are rendered
+// as their typed contents.
+func marshalSnapshotYAML(snapshot *cache.Snapshot) ([]byte, error) {
+	out := make(map[string][]interface{})
+	for typeURL, key := range yamlResourceKeys {
+		resources := snapshot.GetResources(typeURL)
+		if len(resources) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(resources))
+		for name := range resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			resourceJSON, err := protojson.Marshal(resources[name].(proto.Message))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s %s: %w", key, name, err)
+			}
+			var resource interface{}
+			if err := json.Unmarshal(resourceJSON, &resource); err != nil {
+				return nil, err
+			}
+			out[key] = append(out[key], resource)
+		}
+	}
+	return yaml.Marshal(out)
+}
+
+// resourceFileNames maps the xDS types to the names of the files written with --output-dir,
+// without their extension.
+var resourceFileNames = map[resourcev3.Type]string{
+	resourcev3.ListenerType: "lds",
+	resourcev3.ClusterType:  "cds",
+	resourcev3.RouteType:    "rds",
+	resourcev3.EndpointType: "eds",
+	resourcev3.SecretType:   "sds",
+}
+
+// writeSnapshotFiles writes the resources of each xDS type of the snapshot to a file of its
+// own in dir, e.g. lds.json for the listeners, which is created if needed. Every type gets a
+// file, even if it has no resources. The config sources of the resources are rewritten to
+// point at the sibling files instead of ADS, so that Envoy can load the whole configuration
+// from the filesystem.
+func writeSnapshotFiles(snapshot *cache.Snapshot, dir, format string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// Envoy resolves relative paths against its own working directory.
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	configSources := fileConfigSources(absDir, format)
+	for typeURL, name := range resourceFileNames {
+		data, err := marshalDiscoveryResponse(snapshot, typeURL, format, configSources)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+"."+format), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalDiscoveryResponse serializes the resources of a type of the snapshot as a
+// DiscoveryResponse, which is the format of the files Envoy subscribes to with a
+// filesystem-based config source. The config sources of the resources are replaced with
+// configSources. Resources are sorted by name, so the output is stable.
+func marshalDiscoveryResponse(snapshot *cache.Snapshot, typeURL resourcev3.Type, format string, configSources map[resourcev3.Type]*corev3.ConfigSource) ([]byte, error) {
+	resources := snapshot.GetResources(typeURL)
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := &discoveryv3.DiscoveryResponse{
+		VersionInfo: snapshot.GetVersion(typeURL),
+		TypeUrl:     typeURL,
+	}
+	for _, name := range names {
+		resource, err := useConfigSources(resources[name].(proto.Message), configSources)
+		if err != nil {
+			return nil, err
+		}
+		resourceAny, err := anypb.New(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		response.Resources = append(response.Resources, resourceAny)
+	}
+
+	responseJSON, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case outputFormatJSON:
+		return responseJSON, nil
+	case outputFormatYAML:
+		return yaml.JSONToYAML(responseJSON)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// fileConfigSources returns the config sources of the files written to dir by
+// writeSnapshotFiles, by xDS type.
+func fileConfigSources(dir, format string) map[resourcev3.Type]*corev3.ConfigSource {
+	configSources := make(map[resourcev3.Type]*corev3.ConfigSource)
+	for typeURL, name := range resourceFileNames {
+		configSources[typeURL] = &corev3.ConfigSource{
+			ResourceApiVersion: corev3.ApiVersion_V3,
+			ConfigSourceSpecifier: &corev3.ConfigSource_PathConfigSource{
+				PathConfigSource: &corev3.PathConfigSource{
+					Path: filepath.Join(dir, name+"."+format),
+				},
+			},
+		}
+	}
+	return configSources
+}
+
+// useConfigSources returns a copy of a listener or cluster whose RDS, EDS and SDS config
+// sources are replaced with the config source of the matching xDS type. Other resources are
+// returned unchanged.
+func useConfigSources(resource proto.Message, configSources map[resourcev3.Type]*corev3.ConfigSource) (proto.Message, error) {
+	switch resource := resource.(type) {
+	case *listenerv3.Listener:
+		return useListenerConfigSources(resource, configSources)
+	case *clusterv3.Cluster:
+		if resource.GetEdsClusterConfig() == nil {
+			return resource, nil
+		}
+		resource = proto.Clone(resource).(*clusterv3.Cluster)
+		resource.EdsClusterConfig.EdsConfig = configSources[resourcev3.EndpointType]
+		return resource, nil
+	default:
+		return resource, nil
+	}
+}
+
+// useListenerConfigSources returns a copy of the listener whose HTTP connection managers
+// fetch their route configuration, and whose downstream TLS contexts fetch their secrets,
+// from the given config sources.
+func useListenerConfigSources(envoyListener *listenerv3.Listener, configSources map[resourcev3.Type]*corev3.ConfigSource) (*listenerv3.Listener, error) {
+	envoyListener = proto.Clone(envoyListener).(*listenerv3.Listener)
+	filterChains := envoyListener.FilterChains
+	if envoyListener.DefaultFilterChain != nil {
+		filterChains = append(filterChains, envoyListener.DefaultFilterChain)
+	}
+	for _, filterChain := range filterChains {
+		for _, filter := range filterChain.Filters {
+			if filter.Name != wellknown.HTTPConnectionManager {
+				continue
+			}
+			hcmConfig := &hcm.HttpConnectionManager{}
+			if err := filter.GetTypedConfig().UnmarshalTo(hcmConfig); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal HTTP connection manager of listener %s: %w", envoyListener.Name, err)
+			}
+			rds := hcmConfig.GetRds()
+			if rds == nil {
+				continue
+			}
+			rds.ConfigSource = configSources[resourcev3.RouteType]
+			hcmAny, err := anypb.New(hcmConfig)
+			if err != nil {
+				return nil, err
+			}
+			filter.ConfigType = &listenerv3.Filter_TypedConfig{TypedConfig: hcmAny}
+		}
+
+		transportSocket := filterChain.GetTransportSocket()
+		if transportSocket == nil || transportSocket.GetTypedConfig() == nil {
+			continue
+		}
+		tlsContext := &tlsv3.DownstreamTlsContext{}
+		if err := transportSocket.GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal TLS context of listener %s: %w", envoyListener.Name, err)
+		}
+		for _, sdsConfig := range tlsContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs() {
+			sdsConfig.SdsConfig = configSources[resourcev3.SecretType]
+		}
+		if sdsConfig := tlsContext.GetCommonTlsContext().GetValidationContextSdsSecretConfig(); sdsConfig != nil {
+			sdsConfig.SdsConfig = configSources[resourcev3.SecretType]
+		}
+		tlsContextAny, err := anypb.New(tlsContext)
+		if err != nil {
+			return nil, err
+		}
+		transportSocket.ConfigType = &corev3.TransportSocket_TypedConfig{TypedConfig: tlsContextAny}
+	}
+	return envoyListener, nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+// readDiscoveryResponse reads a file written by writeSnapshotFiles.
+func readDiscoveryResponse(t *testing.T, path string) *discoveryv3.DiscoveryResponse {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := &discoveryv3.DiscoveryResponse{}
+	if err := protojson.Unmarshal(data, response); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", path, err)
+	}
+	return response
+}
+
+func TestWriteSnapshotFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "xds")
+	if err := writeSnapshotFiles(testSnapshot(t), dir, outputFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	for typeURL, name := range resourceFileNames {
+		path := filepath.Join(dir, name+".json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), `"ads"`) {
+			t.Errorf("%s still has an ADS config source:\n%s", path, data)
+		}
+		response := readDiscoveryResponse(t, path)
+		if response.TypeUrl != typeURL {
+			t.Errorf("%s has type %s, want %s", path, response.TypeUrl, typeURL)
+		}
+		if len(response.Resources) != 1 {
+			t.Errorf("%s has %d resources, want 1", path, len(response.Resources))
+		}
+	}
+
+	envoyListener := &listenerv3.Listener{}
+	if err := readDiscoveryResponse(t, filepath.Join(dir, "lds.json")).Resources[0].UnmarshalTo(envoyListener); err != nil {
+		t.Fatal(err)
+	}
+	filterChain := envoyListener.FilterChains[0]
+	hcmConfig := &hcm.HttpConnectionManager{}
+	if err := filterChain.Filters[0].GetTypedConfig().UnmarshalTo(hcmConfig); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hcmConfig.GetRds().GetConfigSource().GetPathConfigSource().GetPath(), filepath.Join(dir, "rds.json"); got != want {
+		t.Errorf("RDS config source path = %q, want %q", got, want)
+	}
+	tlsContext := &tlsv3.DownstreamTlsContext{}
+	if err := filterChain.GetTransportSocket().GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tlsContext.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs()[0].GetSdsConfig().GetPathConfigSource().GetPath(), filepath.Join(dir, "sds.json"); got != want {
+		t.Errorf("SDS config source path = %q, want %q", got, want)
+	}
+
+	cluster := &clusterv3.Cluster{}
+	if err := readDiscoveryResponse(t, filepath.Join(dir, "cds.json")).Resources[0].UnmarshalTo(cluster); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cluster.GetEdsClusterConfig().GetEdsConfig().GetPathConfigSource().GetPath(), filepath.Join(dir, "eds.json"); got != want {
+		t.Errorf("EDS config source path = %q, want %q", got, want)
+	}
+}
+
+func TestWriteSnapshotFilesYAML(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeSnapshotFiles(testSnapshot(t), dir, outputFormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "lds.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), filepath.Join(dir, "rds.yaml")) {
+		t.Errorf("lds.yaml doesn't reference rds.yaml:\n%s", data)
+	}
+	if strings.Contains(string(data), "ads:") {
+		t.Errorf("lds.yaml still has an ADS config source:\n%s", data)
+	}
+}
+
+// The snapshot itself is served over ADS, so writing the files must not change it.
+func TestWriteSnapshotFilesLeavesSnapshot(t *testing.T) {
+	snapshot := testSnapshot(t)
+	if err := writeSnapshotFiles(snapshot, t.TempDir(), outputFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range snapshot.GetResources(resourcev3.ClusterType) {
+		if res.(*clusterv3.Cluster).GetEdsClusterConfig().GetEdsConfig().GetAds() == nil {
+			t.Errorf("cluster %s no longer uses ADS", res.(*clusterv3.Cluster).Name)
+		}
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "Update the golden files in testdata")
+
+func TestMarshalSnapshotYAML(t *testing.T) {
+	gw := testGateway("gw", 80)
+	tr, _ := newTestTranslator(t, translator.Options{}, testGatewayClass(), gw,
+		testHTTPRoute("web", "gw", "web"), testService("web"), testEndpointSlice("web-abc", "web", "10.0.0.1"))
+	resources, err := tr.TranslateGatewayToXDS(context.Background(), gw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := generateXDS(resources, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := marshalSnapshot(snapshot, outputFormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("testdata", t.Name()+".golden.yaml")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run the test with -update to create it: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("YAML output differs from %s, run the test with -update if the change is expected:\n%s", path, got)
+	}
+}
+
+// translateTestGatewayJSON translates a minimal Gateway and returns its snapshot as JSON.
+func translateTestGatewayJSON(t *testing.T) []byte {
+	t.Helper()
+	gw := testGateway("gw", 80)
+	tr, _ := newTestTranslator(t, translator.Options{}, testGatewayClass(), gw,
+		testHTTPRoute("web", "gw", "web"), testService("web"), testEndpointSlice("web-abc", "web", "10.0.0.1"))
+	resources, err := tr.TranslateGatewayToXDS(context.Background(), gw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := generateXDS(resources, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := marshalSnapshot(snapshot, outputFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// captureStdout returns what f writes to standard output.
+func captureStdout(t *testing.T, f func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	f()
+	w.Close()
+	return <-done
+}
+
+func TestWriteOutputStdout(t *testing.T) {
+	data := translateTestGatewayJSON(t)
+	got := captureStdout(t, func() {
+		if err := writeOutput(stdoutOutput, data, false); err != nil {
+			t.Errorf("writeOutput() error = %v", err)
+		}
+	})
+	if !bytes.Equal(got, data) {
+		t.Errorf("standard output = %s, want the snapshot %s", got, data)
+	}
+	if !json.Valid(got) || !strings.Contains(string(got), `"listener-80"`) {
+		t.Errorf("standard output isn't the JSON snapshot of the Gateway:\n%s", got)
+	}
+}
+
+func TestWriteOutputGzip(t *testing.T) {
+	data := translateTestGatewayJSON(t)
+	path := gzipOutputPath(filepath.Join(t.TempDir(), "xds.json"))
+	if !strings.HasSuffix(path, ".json.gz") {
+		t.Fatalf("gzipOutputPath() = %q, want a .gz suffix", path)
+	}
+	if err := writeOutput(path, data, true); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("output file isn't gzip-compressed: %v", err)
+	}
+	got, err := io.ReadAll(gzipReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(got) {
+		t.Fatalf("gunzipped output isn't valid JSON:\n%s", got)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("gunzipped output = %s, want %s", got, data)
+	}
+}
+
+func TestGzipOutputPath(t *testing.T) {
+	for output, want := range map[string]string{
+		"xds.json":    "xds.json.gz",
+		"xds.json.gz": "xds.json.gz",
+		stdoutOutput:  stdoutOutput,
+	} {
+		if got := gzipOutputPath(output); got != want {
+			t.Errorf("gzipOutputPath(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	filev3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// The access log formats supported by Options.AccessLogFormat.
+const (
+	AccessLogFormatJSON = "json"
+	AccessLogFormatText = "text"
+)
+
+const accessLogPath = "/dev/stdout"
+
+// defaultJSONAccessLogFields are the fields of a JSON access log entry if no format
+// string is configured.
+var defaultJSONAccessLogFields = map[string]interface{}{
+	"start_time":       "%START_TIME%",
+	"method":           "%REQ(:METHOD)%",
+	"path":             "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%",
+	"protocol":         "%PROTOCOL%",
+	"response_code":    "%RESPONSE_CODE%",
+	"response_flags":   "%RESPONSE_FLAGS%",
+	"duration":         "%DURATION%",
+	"upstream_cluster": "%UPSTREAM_CLUSTER%",
+	"upstream_host":    "%UPSTREAM_HOST%",
+}
+
+// buildAccessLogs returns the access logs of the HTTP connection managers, or nil if
+// access logging is disabled. Entries are written to stdout in the configured format.
+// For the JSON format, a format string is a JSON object mapping the fields of an entry
+// to Envoy command operators. For the text format, it is an Envoy format string, and
+// Envoy's default format is used if it is empty.
+func buildAccessLogs(options Options) ([]*accesslogv3.AccessLog, error) {
+	if !options.AccessLog {
+		return nil, nil
+	}
+
+	fileAccessLog := &filev3.FileAccessLog{Path: accessLogPath}
+	switch options.AccessLogFormat {
+	case AccessLogFormatJSON, "":
+		fields := defaultJSONAccessLogFields
+		if options.AccessLogFormatString != "" {
+			fields = nil
+			if err := json.Unmarshal([]byte(options.AccessLogFormatString), &fields); err != nil {
+				return nil, fmt.Errorf("invalid JSON access log format: %w", err)
+			}
+		}
+		jsonFormat, err := structpb.NewStruct(fields)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON access log format: %w", err)
+		}
+		fileAccessLog.AccessLogFormat = &filev3.FileAccessLog_LogFormat{
+			LogFormat: &corev3.SubstitutionFormatString{
+				Format: &corev3.SubstitutionFormatString_JsonFormat{JsonFormat: jsonFormat},
+			},
+		}
+	case AccessLogFormatText:
+		if options.AccessLogFormatString != "" {
+			fileAccessLog.AccessLogFormat = &filev3.FileAccessLog_LogFormat{
+				LogFormat: &corev3.SubstitutionFormatString{
+					Format: &corev3.SubstitutionFormatString_TextFormatSource{
+						TextFormatSource: &corev3.DataSource{
+							Specifier: &corev3.DataSource_InlineString{InlineString: options.AccessLogFormatString},
+						},
+					},
+				},
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported access log format %q", options.AccessLogFormat)
+	}
+
+	fileAccessLogAny, err := anypb.New(fileAccessLog)
+	if err != nil {
+		return nil, err
+	}
+	return []*accesslogv3.AccessLog{{
+		Name: wellknown.FileAccessLog,
+		ConfigType: &accesslogv3.AccessLog_TypedConfig{
+			TypedConfig: fileAccessLogAny,
+		},
+	}}, nil
+}
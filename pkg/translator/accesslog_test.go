@@ -0,0 +1,87 @@
+package translator
+
+import (
+	"testing"
+
+	filev3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+)
+
+func TestTranslateAccessLog(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		options    Options
+		wantFields []string
+		wantText   string
+	}{
+		{name: "disabled"},
+		{
+			name:       "default JSON format",
+			options:    Options{AccessLog: true},
+			wantFields: []string{"method", "path", "response_code", "duration", "upstream_cluster"},
+		},
+		{
+			name:       "JSON format string",
+			options:    Options{AccessLog: true, AccessLogFormatString: `{"status":"%RESPONSE_CODE%"}`},
+			wantFields: []string{"status"},
+		},
+		{
+			name:     "text format string",
+			options:  Options{AccessLog: true, AccessLogFormat: AccessLogFormatText, AccessLogFormatString: "%REQ(:METHOD)% %RESPONSE_CODE%\n"},
+			wantText: "%REQ(:METHOD)% %RESPONSE_CODE%\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			tl := newTestTranslator(t, tc.options, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+			resources := translateGateway(t, tl, gw)
+
+			accessLogs := listenerHCM(t, findListener(t, resources, "listener-80")).AccessLog
+			if !tc.options.AccessLog {
+				if len(accessLogs) != 0 {
+					t.Errorf("HCM access logs = %v, want none", accessLogs)
+				}
+				return
+			}
+			if len(accessLogs) != 1 || accessLogs[0].Name != wellknown.FileAccessLog {
+				t.Fatalf("HCM access logs = %v, want a single file access log", accessLogs)
+			}
+			fileAccessLog := &filev3.FileAccessLog{}
+			if err := accessLogs[0].GetTypedConfig().UnmarshalTo(fileAccessLog); err != nil {
+				t.Fatal(err)
+			}
+			if fileAccessLog.Path != "/dev/stdout" {
+				t.Errorf("access log path = %q, want /dev/stdout", fileAccessLog.Path)
+			}
+			logFormat := fileAccessLog.GetLogFormat()
+			if tc.wantText != "" {
+				if got := logFormat.GetTextFormatSource().GetInlineString(); got != tc.wantText {
+					t.Errorf("access log text format = %q, want %q", got, tc.wantText)
+				}
+				return
+			}
+			fields := logFormat.GetJsonFormat().GetFields()
+			for _, field := range tc.wantFields {
+				if _, ok := fields[field]; !ok {
+					t.Errorf("access log JSON format lacks field %s: %v", field, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAccessLogOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		options Options
+	}{
+		{name: "unsupported format", options: Options{AccessLog: true, AccessLogFormat: "xml"}},
+		{name: "invalid JSON format string", options: Options{AccessLog: true, AccessLogFormatString: "%RESPONSE_CODE%"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.options.Validate(); err == nil {
+				t.Errorf("Validate() = nil, want an error")
+			}
+		})
+	}
+}
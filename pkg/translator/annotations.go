@@ -0,0 +1,41 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// uint32Annotation parses an annotation of the Service as an unsigned 32-bit integer.
+// It reports whether the annotation is set.
+func uint32Annotation(service *corev1.Service, annotation string) (uint32, bool, error) {
+	value, ok := service.Annotations[annotation]
+	if !ok {
+		return 0, false, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("annotation %s of service %s/%s: %w", annotation, service.Namespace, service.Name, err)
+	}
+	return uint32(parsed), true, nil
+}
+
+// durationAnnotation parses an annotation of the Service as a positive duration, e.g. "5s".
+// It reports whether the annotation is set.
+func durationAnnotation(service *corev1.Service, annotation string) (*durationpb.Duration, bool, error) {
+	value, ok := service.Annotations[annotation]
+	if !ok {
+		return nil, false, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, false, fmt.Errorf("annotation %s of service %s/%s: %w", annotation, service.Namespace, service.Name, err)
+	}
+	if d <= 0 {
+		return nil, false, fmt.Errorf("annotation %s of service %s/%s: duration %q must be positive", annotation, service.Namespace, service.Name, value)
+	}
+	return durationpb.New(d), true, nil
+}
@@ -0,0 +1,27 @@
+package translator
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The appProtocols of Service ports that speak cleartext HTTP/2. The first one is the
+// standard Kubernetes name, the second the name used before it was standardized.
+const (
+	appProtocolH2C       = "kubernetes.io/h2c"
+	appProtocolH2CLegacy = "h2c"
+)
+
+// usesH2C reports whether the given port of the Service speaks cleartext HTTP/2 according to
+// its appProtocol.
+func usesH2C(service *corev1.Service, port int32) bool {
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port != port || servicePort.AppProtocol == nil {
+			continue
+		}
+		switch *servicePort.AppProtocol {
+		case appProtocolH2C, appProtocolH2CLegacy:
+			return true
+		}
+	}
+	return false
+}
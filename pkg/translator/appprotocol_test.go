@@ -0,0 +1,49 @@
+package translator
+
+import (
+	"testing"
+
+	httpv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+)
+
+func TestTranslateBackendRefToClusterAppProtocol(t *testing.T) {
+	tests := []struct {
+		name        string
+		appProtocol *string
+		wantHTTP2   bool
+	}{
+		{name: "kubernetes h2c", appProtocol: ptr(appProtocolH2C), wantHTTP2: true},
+		{name: "legacy h2c", appProtocol: ptr(appProtocolH2CLegacy), wantHTTP2: true},
+		{name: "http", appProtocol: ptr("http")},
+		{name: "no appProtocol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Spec.Ports[0].AppProtocol = tt.appProtocol
+			tl := newTestTranslator(t, Options{}, service)
+
+			cluster, _, err := tl.translateBackendRefToCluster(testNamespace, testBackendRef("backend", 80), clusterVariant{})
+			if err != nil {
+				t.Fatalf("translateBackendRefToCluster() error = %v", err)
+			}
+			protocolOptions, ok := cluster.TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"]
+			if !tt.wantHTTP2 {
+				if ok {
+					t.Errorf("cluster has HTTP protocol options %v, want none", protocolOptions)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("cluster has no HTTP protocol options")
+			}
+			options := &httpv3.HttpProtocolOptions{}
+			if err := protocolOptions.UnmarshalTo(options); err != nil {
+				t.Fatal(err)
+			}
+			if options.GetExplicitHttpConfig().GetHttp2ProtocolOptions() == nil {
+				t.Errorf("cluster protocol options = %v, want explicit HTTP/2", options)
+			}
+		})
+	}
+}
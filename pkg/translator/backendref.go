@@ -3,6 +3,7 @@ package translator
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -47,3 +48,48 @@ func backendRefToClusterName(defaultNamespace string, backendRef gatewayv1.Backe
 
 	return clusterName, nil
 }
+
+// clusterVariant holds the settings of a backend cluster that depend on the routes that use
+// it rather than on its Service. Routes with different settings forward to different clusters
+// of the same Service port, so that they never change the cluster of another route.
+type clusterVariant struct {
+	// http2 makes the cluster speak HTTP/2 to the backend, as gRPC requires.
+	http2 bool
+	// sessionAffinity makes the cluster balance the load by consistent hashing, so that the
+	// cookie hash policy of the routes pins clients to an endpoint.
+	sessionAffinity bool
+}
+
+// clusterName returns the name of the variant of the cluster named name.
+func (v clusterVariant) clusterName(name string) string {
+	if v.http2 {
+		name += "_http2"
+	}
+	if v.sessionAffinity {
+		name += "_session_affinity"
+	}
+	return name
+}
+
+// httpRouteClusterVariant returns the variant of the clusters that the HTTPRoute forwards to.
+func httpRouteClusterVariant(httpRoute *gatewayv1.HTTPRoute) clusterVariant {
+	return clusterVariant{
+		// gRPC-Web requests are forwarded to the gRPC backends over HTTP/2.
+		http2:           hasGRPCWeb(httpRoute),
+		sessionAffinity: hasSessionAffinity(httpRoute),
+	}
+}
+
+// serviceHasPort reports whether the Service exposes port. ExternalName Services accept any
+// port, as it is only used to connect to their external hostname.
+func serviceHasPort(service *corev1.Service, port int32) bool {
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return true
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package translator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// routeReasonInvalidBackendTLSPolicy is the reason of the ResolvedRefs condition of a route
+// with a backend whose BackendTLSPolicy cannot be applied. The route does not forward to the
+// backend, as its connections would not be encrypted as the policy requires.
+const routeReasonInvalidBackendTLSPolicy gatewayv1.RouteConditionReason = "InvalidBackendTLSPolicy"
+
+// caCertificateKey is the ConfigMap key holding the PEM-encoded CA bundle of a BackendTLSPolicy.
+const caCertificateKey = "ca.crt"
+
+// findBackendTLSPolicy returns the BackendTLSPolicy that applies to the given port of the
+// Service, or nil if none does. A policy targeting the port by section name takes precedence
+// over one targeting the whole Service; among equally specific policies the oldest one wins.
+func (t *Translator) findBackendTLSPolicy(service *corev1.Service, port int32) (*gatewayv1.BackendTLSPolicy, error) {
+	policies, err := t.backendTLSPolicyLister.BackendTLSPolicies(service.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list BackendTLSPolicies in namespace %s: %w", service.Namespace, err)
+	}
+
+	var portName string
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port == port {
+			portName = servicePort.Name
+			break
+		}
+	}
+
+	var sectionMatches, serviceMatches []*gatewayv1.BackendTLSPolicy
+	for _, policy := range policies {
+		for _, targetRef := range policy.Spec.TargetRefs {
+			if targetRef.Group != "" || targetRef.Kind != "Service" || string(targetRef.Name) != service.Name {
+				continue
+			}
+			if targetRef.SectionName == nil {
+				serviceMatches = append(serviceMatches, policy)
+				break
+			}
+			if portName != "" && string(*targetRef.SectionName) == portName {
+				sectionMatches = append(sectionMatches, policy)
+				break
+			}
+		}
+	}
+
+	for _, matches := range [][]*gatewayv1.BackendTLSPolicy{sectionMatches, serviceMatches} {
+		if len(matches) == 0 {
+			continue
+		}
+		sort.Slice(matches, func(i, j int) bool {
+			if !matches[i].CreationTimestamp.Equal(&matches[j].CreationTimestamp) {
+				return matches[i].CreationTimestamp.Before(&matches[j].CreationTimestamp)
+			}
+			return matches[i].Name < matches[j].Name
+		})
+		return matches[0], nil
+	}
+	return nil, nil
+}
+
+// buildUpstreamTLSTransportSocket builds the transport socket that makes a cluster originate
+// TLS to its upstream hosts as specified by the BackendTLSPolicy. The server certificate is
+// validated against the policy's CA bundle and hostname, which is also used as the SNI.
+func (t *Translator) buildUpstreamTLSTransportSocket(policy *gatewayv1.BackendTLSPolicy) (*corev3.TransportSocket, error) {
+	validation := policy.Spec.Validation
+	validationContext := &tlsv3.CertificateValidationContext{}
+
+	switch {
+	case len(validation.CACertificateRefs) > 0:
+		caBundle, err := t.resolveCACertificates(policy.Namespace, validation.CACertificateRefs)
+		if err != nil {
+			return nil, err
+		}
+		validationContext.TrustedCa = &corev3.DataSource{
+			Specifier: &corev3.DataSource_InlineString{InlineString: caBundle},
+		}
+	case validation.WellKnownCACertificates != nil && *validation.WellKnownCACertificates == gatewayv1.WellKnownCACertificatesSystem:
+		validationContext.SystemRootCerts = &tlsv3.CertificateValidationContext_SystemRootCerts{}
+	default:
+		return nil, fmt.Errorf("BackendTLSPolicy %s/%s specifies no CA certificates", policy.Namespace, policy.Name)
+	}
+
+	// The certificate must be valid for the hostname unless subjectAltNames are specified.
+	if len(validation.SubjectAltNames) == 0 {
+		validationContext.MatchTypedSubjectAltNames = []*tlsv3.SubjectAltNameMatcher{
+			subjectAltNameMatcher(tlsv3.SubjectAltNameMatcher_DNS, string(validation.Hostname)),
+		}
+	}
+	for _, san := range validation.SubjectAltNames {
+		switch san.Type {
+		case gatewayv1.HostnameSubjectAltNameType:
+			validationContext.MatchTypedSubjectAltNames = append(validationContext.MatchTypedSubjectAltNames,
+				subjectAltNameMatcher(tlsv3.SubjectAltNameMatcher_DNS, string(san.Hostname)))
+		case gatewayv1.URISubjectAltNameType:
+			validationContext.MatchTypedSubjectAltNames = append(validationContext.MatchTypedSubjectAltNames,
+				subjectAltNameMatcher(tlsv3.SubjectAltNameMatcher_URI, string(san.URI)))
+		default:
+			return nil, fmt.Errorf("BackendTLSPolicy %s/%s: unsupported subjectAltName type %q", policy.Namespace, policy.Name, san.Type)
+		}
+	}
+
+	tlsContext := &tlsv3.UpstreamTlsContext{
+		Sni: string(validation.Hostname),
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			ValidationContextType: &tlsv3.CommonTlsContext_ValidationContext{
+				ValidationContext: validationContext,
+			},
+		},
+	}
+	tlsContextAny, err := anypb.New(tlsContext)
+	if err != nil {
+		return nil, err
+	}
+	return &corev3.TransportSocket{
+		Name: wellknown.TransportSocketTls,
+		ConfigType: &corev3.TransportSocket_TypedConfig{
+			TypedConfig: tlsContextAny,
+		},
+	}, nil
+}
+
+// resolveCACertificates concatenates the CA bundles of the ConfigMaps referenced by a
+// BackendTLSPolicy. ConfigMaps are the only supported kind of CA certificate reference.
+func (t *Translator) resolveCACertificates(namespace string, refs []gatewayv1.LocalObjectReference) (string, error) {
+	var bundles []string
+	for _, ref := range refs {
+		if ref.Group != "" || ref.Kind != "ConfigMap" {
+			return "", fmt.Errorf("unsupported CA certificate reference %s/%s %s", ref.Group, ref.Kind, ref.Name)
+		}
+		configMap, err := t.configMapLister.ConfigMaps(namespace).Get(string(ref.Name))
+		if err != nil {
+			return "", fmt.Errorf("could not find CA certificate ConfigMap %s/%s: %w", namespace, ref.Name, err)
+		}
+		bundle, ok := configMap.Data[caCertificateKey]
+		if !ok || strings.TrimSpace(bundle) == "" {
+			return "", fmt.Errorf("ConfigMap %s/%s has no %s key", namespace, ref.Name, caCertificateKey)
+		}
+		bundles = append(bundles, strings.TrimSpace(bundle))
+	}
+	return strings.Join(bundles, "\n") + "\n", nil
+}
+
+func subjectAltNameMatcher(sanType tlsv3.SubjectAltNameMatcher_SanType, value string) *tlsv3.SubjectAltNameMatcher {
+	return &tlsv3.SubjectAltNameMatcher{
+		SanType: sanType,
+		Matcher: &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_Exact{Exact: value},
+		},
+	}
+}
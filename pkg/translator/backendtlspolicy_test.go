@@ -0,0 +1,128 @@
+package translator
+
+import (
+	"testing"
+
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const testCACertificate = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"
+
+// testBackendTLSPolicy returns a BackendTLSPolicy for the Service that validates the server
+// certificate against the CA bundle of the ConfigMap.
+func testBackendTLSPolicy(name, service, caConfigMap string) *gatewayv1.BackendTLSPolicy {
+	return &gatewayv1.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: gatewayv1.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1.LocalPolicyTargetReference{Kind: "Service", Name: gatewayv1.ObjectName(service)},
+			}},
+			Validation: gatewayv1.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: gatewayv1.ObjectName(caConfigMap)}},
+				Hostname:          "backend.example.com",
+			},
+		},
+	}
+}
+
+func testCAConfigMap(name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data:       map[string]string{caCertificateKey: testCACertificate},
+	}
+}
+
+func TestTranslateBackendTLSPolicy(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)),
+		testBackendTLSPolicy("backend-tls", "backend", "ca"), testCAConfigMap("ca"))
+	resources := translateGateway(t, tl, gw)
+
+	transportSocket := findCluster(t, resources, clusterName("backend", 80)).TransportSocket
+	if transportSocket == nil {
+		t.Fatal("cluster has no transport socket")
+	}
+	tlsContext := &tlsv3.UpstreamTlsContext{}
+	if err := transportSocket.GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+		t.Fatal(err)
+	}
+	if tlsContext.Sni != "backend.example.com" {
+		t.Errorf("SNI = %q, want backend.example.com", tlsContext.Sni)
+	}
+	validationContext := tlsContext.GetCommonTlsContext().GetValidationContext()
+	if got := validationContext.GetTrustedCa().GetInlineString(); got != testCACertificate+"\n" {
+		t.Errorf("trusted CA = %q, want the CA bundle of the ConfigMap", got)
+	}
+	sans := validationContext.GetMatchTypedSubjectAltNames()
+	if len(sans) != 1 || sans[0].GetMatcher().GetExact() != "backend.example.com" {
+		t.Errorf("subject alt names = %v, want the hostname", sans)
+	}
+}
+
+// TestTranslateInvalidBackendTLSPolicy checks that a route does not forward to a backend whose
+// BackendTLSPolicy cannot be applied, and reports it in its ResolvedRefs condition.
+func TestTranslateInvalidBackendTLSPolicy(t *testing.T) {
+	weighted := testBackendRef("other", 80)
+	weighted.Weight = ptr(int32(1))
+	invalid := testBackendRef("backend", 80)
+	invalid.Weight = ptr(int32(1))
+
+	tests := []struct {
+		name        string
+		backendRefs []gatewayv1.BackendRef
+		// wantCluster is the only cluster the route forwards to, or empty if it answers with
+		// a 500.
+		wantCluster string
+	}{
+		{name: "single backend", backendRefs: []gatewayv1.BackendRef{testBackendRef("backend", 80)}},
+		{name: "weighted backends", backendRefs: []gatewayv1.BackendRef{invalid, weighted}, wantCluster: clusterName("other", 80)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			// The CA ConfigMap of the policy does not exist.
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), testService("other", 80),
+				testHTTPRoute("web", "gw", tt.backendRefs...), testBackendTLSPolicy("backend-tls", "backend", "missing"))
+			resources, _, routeStatuses, err := tl.buildEnvoyResourcesForGateway(gw)
+			if err == nil {
+				t.Error("buildEnvoyResourcesForGateway() error = nil, want the error of the policy")
+			}
+
+			if findResource(resources, resourcev3.ClusterType, clusterName("backend", 80)) != nil {
+				t.Error("cluster of the backend with the invalid policy exists")
+			}
+			vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+			route := findRoute(t, vh, "default-web-rule0-match0")
+			if tt.wantCluster == "" {
+				if got := route.GetDirectResponse().GetStatus(); got != 500 {
+					t.Errorf("route action = %v, want a 500 direct response", route.Action)
+				}
+			} else {
+				clusters := route.GetRoute().GetWeightedClusters().GetClusters()
+				if len(clusters) != 1 || clusters[0].Name != tt.wantCluster {
+					t.Errorf("weighted clusters = %v, want only %s", clusters, tt.wantCluster)
+				}
+			}
+
+			parentStatuses := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "web"}]
+			if len(parentStatuses) != 1 {
+				t.Fatalf("route has %d parent statuses, want 1", len(parentStatuses))
+			}
+			condition := metav1.Condition{}
+			for _, c := range parentStatuses[0].Conditions {
+				if c.Type == string(gatewayv1.RouteConditionResolvedRefs) {
+					condition = c
+				}
+			}
+			if condition.Status != metav1.ConditionFalse || condition.Reason != string(routeReasonInvalidBackendTLSPolicy) {
+				t.Errorf("ResolvedRefs condition = %s/%s, want False/%s", condition.Status, condition.Reason, routeReasonInvalidBackendTLSPolicy)
+			}
+		})
+	}
+}
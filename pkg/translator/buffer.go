@@ -0,0 +1,67 @@
+package translator
+
+import (
+	"fmt"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// bufferMaxRequestBytesAnnotation is the annotation of an HTTPRoute that buffers the requests
+// of its routes up to the given number of bytes before forwarding them.
+const bufferMaxRequestBytesAnnotation = "gateway.xds/buffer-max-request-bytes"
+
+// routeOnlyBufferMaxRequestBytes is the limit of the buffer filter if only HTTPRoutes enable
+// it. It never applies, as the routes that enable the filter set their own limit.
+const routeOnlyBufferMaxRequestBytes = 1 << 20
+
+// buildBufferFilter builds the buffer filter of an HTTP connection manager, which buffers
+// requests up to maxRequestBytes and answers larger ones with a 413. If maxRequestBytes is 0,
+// the filter is disabled by default, so that it only buffers the requests of the routes that
+// enable it.
+func buildBufferFilter(maxRequestBytes uint32) (*hcm.HttpFilter, error) {
+	disabled := maxRequestBytes == 0
+	if disabled {
+		maxRequestBytes = routeOnlyBufferMaxRequestBytes
+	}
+	bufferAny, err := anypb.New(&bufferv3.Buffer{
+		MaxRequestBytes: wrapperspb.UInt32(maxRequestBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hcm.HttpFilter{
+		Name:     wellknown.Buffer,
+		Disabled: disabled,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: bufferAny,
+		},
+	}, nil
+}
+
+// parseBufferAnnotations returns the per-route config that enables the buffer filter with
+// the limit of an HTTPRoute, or nil if the routes use the default of the filter.
+func parseBufferAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	value, ok := annotations[bufferMaxRequestBytesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	maxRequestBytes, err := parsePositiveUint32(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", bufferMaxRequestBytesAnnotation, err)
+	}
+	bufferAny, err := anypb.New(&bufferv3.BufferPerRoute{
+		Override: &bufferv3.BufferPerRoute_Buffer{
+			Buffer: &bufferv3.Buffer{MaxRequestBytes: wrapperspb.UInt32(maxRequestBytes)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The config is wrapped, so that it also enables the filter if it is disabled by default.
+	return anypb.New(&routev3.FilterConfig{Config: bufferAny})
+}
@@ -0,0 +1,93 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// translateBuffer translates a Gateway with an HTTPRoute that buffers requests up to 4096
+// bytes and one that doesn't override the buffer filter, and returns the buffer filter of
+// the connection manager and the resources.
+func translateBuffer(t *testing.T, options Options) (*hcm.HttpFilter, map[resourcev3.Type][]envoyproxytypes.Resource) {
+	t.Helper()
+	gw := testGateway("gw", httpListener("http", 80))
+	upload := testHTTPRoute("upload", "gw", testBackendRef("backend", 80))
+	upload.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/upload")}
+	upload.Annotations = map[string]string{bufferMaxRequestBytesAnnotation: "4096"}
+	tl := newTestTranslator(t, options, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)), upload)
+	resources := translateGateway(t, tl, gw)
+
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	filters := httpFilterNames(manager)
+	i := slices.Index(filters, wellknown.Buffer)
+	if i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Fatalf("HTTP filters = %v, want %s before the router", filters, wellknown.Buffer)
+	}
+	return manager.HttpFilters[i], resources
+}
+
+// bufferMaxRequestBytes returns the request limit of the buffer filter config.
+func bufferMaxRequestBytes(t *testing.T, filter *hcm.HttpFilter) uint32 {
+	t.Helper()
+	buffer := &bufferv3.Buffer{}
+	if err := filter.GetTypedConfig().UnmarshalTo(buffer); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.GetMaxRequestBytes().GetValue()
+}
+
+func TestTranslateBuffer(t *testing.T) {
+	filter, resources := translateBuffer(t, Options{BufferMaxRequestBytes: 1 << 16})
+	if filter.Disabled {
+		t.Error("buffer filter is disabled, want it to buffer all requests")
+	}
+	if got := bufferMaxRequestBytes(t, filter); got != 1<<16 {
+		t.Errorf("max request bytes = %d, want %d", got, 1<<16)
+	}
+
+	// The route's limit overrides the limit of the filter.
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	perRouteConfig, ok := findRoute(t, vh, "default-upload-rule0-match0").TypedPerFilterConfig[wellknown.Buffer]
+	if !ok {
+		t.Fatalf("route default-upload-rule0-match0 has no %s override", wellknown.Buffer)
+	}
+	filterConfig := &routev3.FilterConfig{}
+	if err := perRouteConfig.UnmarshalTo(filterConfig); err != nil {
+		t.Fatal(err)
+	}
+	bufferPerRoute := &bufferv3.BufferPerRoute{}
+	if err := filterConfig.GetConfig().UnmarshalTo(bufferPerRoute); err != nil {
+		t.Fatal(err)
+	}
+	if got := bufferPerRoute.GetBuffer().GetMaxRequestBytes().GetValue(); got != 4096 {
+		t.Errorf("route max request bytes = %d, want 4096", got)
+	}
+	if _, ok := findRoute(t, vh, "default-web-rule0-match0").TypedPerFilterConfig[wellknown.Buffer]; ok {
+		t.Errorf("route default-web-rule0-match0 has a %s override, want none", wellknown.Buffer)
+	}
+}
+
+func TestTranslateRouteOnlyBuffer(t *testing.T) {
+	// Without a global limit, the filter only buffers the requests of the routes enabling it.
+	filter, _ := translateBuffer(t, Options{})
+	if !filter.Disabled {
+		t.Error("buffer filter is enabled, want it disabled by default")
+	}
+}
+
+func TestParseBufferAnnotationsInvalid(t *testing.T) {
+	for _, value := range []string{"0", "-1", "1MiB"} {
+		if config, err := parseBufferAnnotations(map[string]string{bufferMaxRequestBytesAnnotation: value}); err == nil {
+			t.Errorf("parseBufferAnnotations() of %q = %v, want an error", value, config)
+		}
+	}
+}
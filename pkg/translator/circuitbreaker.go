@@ -0,0 +1,68 @@
+package translator
+
+import (
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The annotations of a Service that override the circuit breaking thresholds of its clusters.
+const (
+	maxConnectionsAnnotation     = "gateway.xds/max-connections"
+	maxPendingRequestsAnnotation = "gateway.xds/max-pending-requests"
+	maxRequestsAnnotation        = "gateway.xds/max-requests"
+	maxRetriesAnnotation         = "gateway.xds/max-retries"
+)
+
+// CircuitBreakers are the circuit breaking thresholds of a cluster. A zero threshold
+// keeps Envoy's default.
+type CircuitBreakers struct {
+	MaxConnections     uint32
+	MaxPendingRequests uint32
+	MaxRequests        uint32
+	MaxRetries         uint32
+}
+
+// buildCircuitBreakers builds the circuit breakers of a cluster for the Service from the
+// defaults, overridden by the annotations of the Service. It returns nil if no threshold
+// is set, so that Envoy's defaults apply.
+func buildCircuitBreakers(defaults CircuitBreakers, service *corev1.Service) (*clusterv3.CircuitBreakers, error) {
+	thresholds := defaults
+	for annotation, threshold := range map[string]*uint32{
+		maxConnectionsAnnotation:     &thresholds.MaxConnections,
+		maxPendingRequestsAnnotation: &thresholds.MaxPendingRequests,
+		maxRequestsAnnotation:        &thresholds.MaxRequests,
+		maxRetriesAnnotation:         &thresholds.MaxRetries,
+	} {
+		value, ok, err := uint32Annotation(service, annotation)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			*threshold = value
+		}
+	}
+	if thresholds == (CircuitBreakers{}) {
+		return nil, nil
+	}
+
+	defaultThresholds := &clusterv3.CircuitBreakers_Thresholds{
+		Priority: corev3.RoutingPriority_DEFAULT,
+	}
+	if thresholds.MaxConnections != 0 {
+		defaultThresholds.MaxConnections = wrapperspb.UInt32(thresholds.MaxConnections)
+	}
+	if thresholds.MaxPendingRequests != 0 {
+		defaultThresholds.MaxPendingRequests = wrapperspb.UInt32(thresholds.MaxPendingRequests)
+	}
+	if thresholds.MaxRequests != 0 {
+		defaultThresholds.MaxRequests = wrapperspb.UInt32(thresholds.MaxRequests)
+	}
+	if thresholds.MaxRetries != 0 {
+		defaultThresholds.MaxRetries = wrapperspb.UInt32(thresholds.MaxRetries)
+	}
+	return &clusterv3.CircuitBreakers{
+		Thresholds: []*clusterv3.CircuitBreakers_Thresholds{defaultThresholds},
+	}, nil
+}
@@ -0,0 +1,72 @@
+package translator
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTranslateCircuitBreakers(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		defaults    CircuitBreakers
+		annotations map[string]string
+		want        *clusterv3.CircuitBreakers_Thresholds
+	}{
+		{name: "unset"},
+		{
+			name: "annotated Service",
+			annotations: map[string]string{
+				maxConnectionsAnnotation:     "100",
+				maxPendingRequestsAnnotation: "50",
+				maxRequestsAnnotation:        "200",
+				maxRetriesAnnotation:         "5",
+			},
+			want: &clusterv3.CircuitBreakers_Thresholds{
+				Priority:           corev3.RoutingPriority_DEFAULT,
+				MaxConnections:     wrapperspb.UInt32(100),
+				MaxPendingRequests: wrapperspb.UInt32(50),
+				MaxRequests:        wrapperspb.UInt32(200),
+				MaxRetries:         wrapperspb.UInt32(5),
+			},
+		},
+		{
+			name:        "annotation overrides default",
+			defaults:    CircuitBreakers{MaxConnections: 1000, MaxRequests: 1000},
+			annotations: map[string]string{maxRequestsAnnotation: "10"},
+			want: &clusterv3.CircuitBreakers_Thresholds{
+				Priority:       corev3.RoutingPriority_DEFAULT,
+				MaxConnections: wrapperspb.UInt32(1000),
+				MaxRequests:    wrapperspb.UInt32(10),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, Options{CircuitBreakers: tc.defaults}, service)
+
+			if tc.want == nil {
+				if cluster.CircuitBreakers != nil {
+					t.Errorf("circuit breakers = %v, want Envoy's defaults", cluster.CircuitBreakers)
+				}
+				return
+			}
+			thresholds := cluster.GetCircuitBreakers().GetThresholds()
+			if len(thresholds) != 1 || !proto.Equal(thresholds[0], tc.want) {
+				t.Errorf("circuit breaker thresholds = %v, want %v", thresholds, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildCircuitBreakersInvalidAnnotation(t *testing.T) {
+	service := testService("backend", 80)
+	service.Annotations = map[string]string{maxConnectionsAnnotation: "many"}
+	if circuitBreakers, err := buildCircuitBreakers(CircuitBreakers{}, service); err == nil {
+		t.Errorf("buildCircuitBreakers() = %v, want an error", circuitBreakers)
+	}
+}
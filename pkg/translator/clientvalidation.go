@@ -0,0 +1,65 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The TLS options of a listener that require clients to present a certificate signed by the
+// CA bundle under the ca.crt key of a ConfigMap or Secret in the Gateway's namespace.
+const (
+	clientCAConfigMapOption = "gateway.xds/client-ca-configmap"
+	clientCASecretOption    = "gateway.xds/client-ca-secret"
+)
+
+// buildClientValidationSecret builds the SDS secret with the validation context of the client
+// certificates of a listener, or returns nil if the listener doesn't require client certificates.
+func (t *Translator) buildClientValidationSecret(gateway *gatewayv1.Gateway, lis gatewayv1.Listener) (*tlsv3.Secret, error) {
+	configMapName, fromConfigMap := lis.TLS.Options[clientCAConfigMapOption]
+	secretName, fromSecret := lis.TLS.Options[clientCASecretOption]
+	if fromConfigMap && fromSecret {
+		return nil, fmt.Errorf("TLS options %s and %s are mutually exclusive", clientCAConfigMapOption, clientCASecretOption)
+	}
+
+	var kind, name, caBundle string
+	switch {
+	case fromConfigMap:
+		kind, name = "ConfigMap", string(configMapName)
+		configMap, err := t.configMapLister.ConfigMaps(gateway.Namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not find client CA ConfigMap %s/%s: %w", gateway.Namespace, name, err)
+		}
+		caBundle = configMap.Data[caCertificateKey]
+	case fromSecret:
+		kind, name = "Secret", string(secretName)
+		secret, err := t.secretLister.Secrets(gateway.Namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not find client CA Secret %s/%s: %w", gateway.Namespace, name, err)
+		}
+		caBundle = string(secret.Data[caCertificateKey])
+	default:
+		return nil, nil
+	}
+	if name == "" {
+		return nil, errors.New("no client CA name specified")
+	}
+	if strings.TrimSpace(caBundle) == "" {
+		return nil, fmt.Errorf("%s %s/%s has no %s key", kind, gateway.Namespace, name, caCertificateKey)
+	}
+
+	return &tlsv3.Secret{
+		Name: fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), gateway.Namespace, name),
+		Type: &tlsv3.Secret_ValidationContext{
+			ValidationContext: &tlsv3.CertificateValidationContext{
+				TrustedCa: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{InlineString: caBundle},
+				},
+			},
+		},
+	}, nil
+}
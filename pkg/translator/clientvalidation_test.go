@@ -0,0 +1,70 @@
+package translator
+
+import (
+	"testing"
+
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const testCABundle = "-----BEGIN CERTIFICATE-----\nca\n-----END CERTIFICATE-----\n"
+
+func TestTranslateClientValidation(t *testing.T) {
+	listener := httpsListener("https", 443, "web-cert")
+	listener.TLS.Options = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{clientCAConfigMapOption: "client-ca"}
+	gw := testGateway("gw", listener)
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-ca", Namespace: "default"},
+		Data:       map[string]string{caCertificateKey: testCABundle},
+	}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testTLSSecret("web-cert"), caConfigMap,
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	tlsContext := filterChainTLSContext(t, findListener(t, resources, "listener-443").FilterChains[0])
+	if !tlsContext.GetRequireClientCertificate().GetValue() {
+		t.Error("TLS context doesn't require a client certificate")
+	}
+	validation := tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig()
+	if validation == nil {
+		t.Fatal("TLS context has no validation context SDS config")
+	}
+	if validation.Name != "configmap/default/client-ca" || validation.SdsConfig.GetAds() == nil {
+		t.Errorf("validation context SDS config = %v, want configmap/default/client-ca over ADS", validation)
+	}
+
+	secret, ok := findResource(resources, resourcev3.SecretType, "configmap/default/client-ca").(*tlsv3.Secret)
+	if !ok {
+		t.Fatal("no validation context secret configmap/default/client-ca")
+	}
+	if got := secret.GetValidationContext().GetTrustedCa().GetInlineString(); got != testCABundle {
+		t.Errorf("trusted CA = %q, want %q", got, testCABundle)
+	}
+}
+
+func TestTranslateWithoutClientValidation(t *testing.T) {
+	tlsContext := translateTLSContext(t, Options{}, httpsListener("https", 443, "web-cert"))
+	if tlsContext.RequireClientCertificate != nil || tlsContext.CommonTlsContext.ValidationContextType != nil {
+		t.Errorf("TLS context = %v, want no client certificate validation", tlsContext)
+	}
+}
+
+func TestBuildClientValidationSecretInvalid(t *testing.T) {
+	emptySecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "empty-ca", Namespace: "default"}}
+	tl := newTestTranslator(t, Options{}, emptySecret)
+	gw := testGateway("gw")
+	for _, options := range []map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+		{clientCAConfigMapOption: "client-ca", clientCASecretOption: "client-ca"},
+		{clientCAConfigMapOption: "missing"},
+		{clientCASecretOption: "empty-ca"},
+	} {
+		listener := httpsListener("https", 443, "web-cert")
+		listener.TLS.Options = options
+		if secret, err := tl.buildClientValidationSecret(gw, listener); err == nil {
+			t.Errorf("buildClientValidationSecret() with options %v = %v, want an error", options, secret)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package translator
+
+import (
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// clusterCacheKey identifies the cluster of a variant of the Service port of a backend.
+type clusterCacheKey struct {
+	service types.NamespacedName
+	port    int32
+	variant clusterVariant
+}
+
+// clusterCacheEntry is the result of building the cluster of a backend.
+type clusterCacheEntry struct {
+	cluster *clusterv3.Cluster
+	cla     *endpointv3.ClusterLoadAssignment
+	err     error
+}
+
+// clusterCache memoizes the clusters and endpoints of the backends of a Gateway's routes
+// during a single translation, so that a Service port targeted by many routes is only
+// resolved once. It must not outlive the translation, as it does not notice changes.
+type clusterCache struct {
+	translator *Translator
+	entries    map[clusterCacheKey]clusterCacheEntry
+}
+
+func newClusterCache(t *Translator) *clusterCache {
+	return &clusterCache{
+		translator: t,
+		entries:    make(map[clusterCacheKey]clusterCacheEntry),
+	}
+}
+
+// get returns the cluster of the backend like translateBackendRefToCluster, only building it
+// on the first lookup of its Service port and variant. Backends that do not reference a
+// Service port are not memoized.
+func (c *clusterCache) get(defaultNamespace string, backendRef gatewayv1.BackendRef, variant clusterVariant) (*clusterv3.Cluster, *endpointv3.ClusterLoadAssignment, error) {
+	if backendRef.Port == nil || (backendRef.Kind != nil && *backendRef.Kind != "Service") ||
+		(backendRef.Group != nil && *backendRef.Group != "") {
+		return c.translator.translateBackendRefToCluster(defaultNamespace, backendRef, variant)
+	}
+
+	namespace := defaultNamespace
+	if backendRef.Namespace != nil {
+		namespace = string(*backendRef.Namespace)
+	}
+	key := clusterCacheKey{
+		service: types.NamespacedName{Namespace: namespace, Name: string(backendRef.Name)},
+		port:    int32(*backendRef.Port),
+		variant: variant,
+	}
+	if entry, ok := c.entries[key]; ok {
+		return entry.cluster, entry.cla, entry.err
+	}
+	cluster, cla, err := c.translator.translateBackendRefToCluster(defaultNamespace, backendRef, variant)
+	c.entries[key] = clusterCacheEntry{cluster: cluster, cla: cla, err: err}
+	return cluster, cla, err
+}
@@ -0,0 +1,91 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestClusterCacheGet(t *testing.T) {
+	tl := newTestTranslator(t, Options{}, testService("backend", 80), testEndpointSlice("backend-1", "backend", 8080, "10.1.0.1"))
+	cache := newClusterCache(tl)
+	backendRef := testBackendRef("backend", 80)
+
+	cluster, cla, err := cache.get(testNamespace, backendRef, clusterVariant{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, againCLA, err := cache.get(testNamespace, backendRef, clusterVariant{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != cluster || againCLA != cla {
+		t.Error("repeated lookups of the backend returned different objects")
+	}
+
+	http2, _, err := cache.get(testNamespace, backendRef, clusterVariant{http2: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if http2 == cluster || http2.Name == cluster.Name {
+		t.Errorf("the HTTP/2 variant of the backend shares cluster %s", cluster.Name)
+	}
+
+	// The cache only saves work, it returns what an uncached lookup builds.
+	uncached, uncachedCLA, err := tl.translateBackendRefToCluster(testNamespace, backendRef, clusterVariant{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(cluster, uncached) || !proto.Equal(cla, uncachedCLA) {
+		t.Errorf("cached cluster %v and endpoints %v, want %v and %v", cluster, cla, uncached, uncachedCLA)
+	}
+}
+
+// The errors of a backend are memoized along with its clusters.
+func TestClusterCacheGetError(t *testing.T) {
+	cache := newClusterCache(newTestTranslator(t, Options{}))
+	for range 2 {
+		if _, _, err := cache.get(testNamespace, testBackendRef("missing", 80), clusterVariant{}); err == nil {
+			t.Error("get() of a missing Service succeeded")
+		}
+	}
+}
+
+// TestTranslateClustersMatchUncached checks that the clusters and endpoints of a Gateway
+// whose routes share backends are the ones built for each backend without the cache.
+func TestTranslateClustersMatchUncached(t *testing.T) {
+	tl, gw := newLargeTestGateway(t, Options{}, 20, 4)
+	resources := translateGateway(t, tl, gw)
+	if got := len(resources[resourcev3.ClusterType]); got != 4 {
+		t.Fatalf("got %d clusters, want 4", got)
+	}
+	for i := range 4 {
+		service := fmt.Sprintf("backend-%d", i)
+		cluster := findCluster(t, resources, clusterName(service, 80))
+		cla, ok := findResource(resources, resourcev3.EndpointType, cluster.Name).(*endpointv3.ClusterLoadAssignment)
+		if !ok {
+			t.Fatalf("cluster %s has no endpoints", cluster.Name)
+		}
+		uncached, uncachedCLA, err := tl.translateBackendRefToCluster(testNamespace, testBackendRef(service, 80), clusterVariant{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(cluster, uncached) || !proto.Equal(cla, uncachedCLA) {
+			t.Errorf("cluster %s differs from the uncached cluster %v", cluster.Name, uncached)
+		}
+	}
+}
+
+func BenchmarkTranslateGatewayToXDS(b *testing.B) {
+	tl, gw := newLargeTestGateway(b, Options{}, 100, 5)
+	b.ResetTimer()
+	for range b.N {
+		if _, err := tl.TranslateGatewayToXDS(context.Background(), gw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
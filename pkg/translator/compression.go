@@ -0,0 +1,111 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	brotliv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/brotli/compressor/v3"
+	gzipv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
+	compressorv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The response compression algorithms supported by Options.Compression.
+const (
+	CompressionGzip   = "gzip"
+	CompressionBrotli = "brotli"
+)
+
+const compressorFilterName = "envoy.filters.http.compressor"
+
+// compressionDisabledAnnotation is the annotation of an HTTPRoute that exempts the responses
+// of its routes from compression.
+const compressionDisabledAnnotation = "gateway.xds/compression-disabled"
+
+// compressionMinContentLength is the minimum length of the responses that are compressed.
+// Compressing smaller responses hardly saves any bandwidth.
+const compressionMinContentLength = 1024
+
+// compressionContentTypes are the content types of the responses that are compressed.
+var compressionContentTypes = []string{
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"image/svg+xml",
+	"text/css",
+	"text/html",
+	"text/javascript",
+	"text/plain",
+	"text/xml",
+}
+
+// validateCompression reports whether the compression algorithm is supported.
+func validateCompression(compression string) error {
+	switch compression {
+	case "", CompressionGzip, CompressionBrotli:
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm %q, must be %s or %s", compression, CompressionGzip, CompressionBrotli)
+}
+
+// buildCompressorFilter builds the compressor filter of an HTTP connection manager, which
+// compresses the responses of the allowed content types with the given algorithm.
+func buildCompressorFilter(compression string) (*hcm.HttpFilter, error) {
+	var library proto.Message
+	switch compression {
+	case CompressionGzip:
+		library = &gzipv3.Gzip{}
+	case CompressionBrotli:
+		library = &brotliv3.Brotli{}
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm %q", compression)
+	}
+	libraryAny, err := anypb.New(library)
+	if err != nil {
+		return nil, err
+	}
+	compressorAny, err := anypb.New(&compressorv3.Compressor{
+		CompressorLibrary: &corev3.TypedExtensionConfig{
+			Name:        "envoy.compression." + compression + ".compressor",
+			TypedConfig: libraryAny,
+		},
+		ResponseDirectionConfig: &compressorv3.Compressor_ResponseDirectionConfig{
+			CommonConfig: &compressorv3.Compressor_CommonDirectionConfig{
+				MinContentLength: wrapperspb.UInt32(compressionMinContentLength),
+				ContentType:      compressionContentTypes,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hcm.HttpFilter{
+		Name: compressorFilterName,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: compressorAny,
+		},
+	}, nil
+}
+
+// parseCompressionAnnotations returns the per-route config of the compressor filter that
+// disables it for the routes of an HTTPRoute, or nil if their responses are compressed.
+func parseCompressionAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	value, ok := annotations[compressionDisabledAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", compressionDisabledAnnotation, err)
+	}
+	if !disabled {
+		return nil, nil
+	}
+	return anypb.New(&compressorv3.CompressorPerRoute{
+		Override: &compressorv3.CompressorPerRoute_Disabled{Disabled: true},
+	})
+}
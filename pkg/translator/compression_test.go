@@ -0,0 +1,93 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	brotliv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/brotli/compressor/v3"
+	gzipv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
+	compressorv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateCompression(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	uncompressed := testHTTPRoute("uncompressed", "gw", testBackendRef("backend", 80))
+	uncompressed.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/download")}
+	uncompressed.Annotations = map[string]string{compressionDisabledAnnotation: "true"}
+	tl := newTestTranslator(t, Options{Compression: CompressionGzip}, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)), uncompressed)
+	resources := translateGateway(t, tl, gw)
+
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	filters := httpFilterNames(manager)
+	i := slices.Index(filters, compressorFilterName)
+	if i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Fatalf("HTTP filters = %v, want %s before the router", filters, compressorFilterName)
+	}
+	compressor := &compressorv3.Compressor{}
+	if err := manager.HttpFilters[i].GetTypedConfig().UnmarshalTo(compressor); err != nil {
+		t.Fatal(err)
+	}
+	if !compressor.GetCompressorLibrary().GetTypedConfig().MessageIs(&gzipv3.Gzip{}) {
+		t.Errorf("compressor library = %v, want gzip", compressor.GetCompressorLibrary())
+	}
+	commonConfig := compressor.GetResponseDirectionConfig().GetCommonConfig()
+	if commonConfig.GetMinContentLength().GetValue() != compressionMinContentLength {
+		t.Errorf("min content length = %v, want %d", commonConfig.GetMinContentLength(), compressionMinContentLength)
+	}
+	if !slices.Contains(commonConfig.GetContentType(), "application/json") {
+		t.Errorf("content types = %v, want application/json", commonConfig.GetContentType())
+	}
+
+	// Routes opt out of compression with a per-route override.
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	perRouteConfig, ok := findRoute(t, vh, "default-uncompressed-rule0-match0").TypedPerFilterConfig[compressorFilterName]
+	if !ok {
+		t.Fatalf("route default-uncompressed-rule0-match0 has no %s override", compressorFilterName)
+	}
+	compressorPerRoute := &compressorv3.CompressorPerRoute{}
+	if err := perRouteConfig.UnmarshalTo(compressorPerRoute); err != nil {
+		t.Fatal(err)
+	}
+	if !compressorPerRoute.GetDisabled() {
+		t.Errorf("route default-uncompressed-rule0-match0 %s override = %v, want disabled", compressorFilterName, compressorPerRoute)
+	}
+	if _, ok := findRoute(t, vh, "default-web-rule0-match0").TypedPerFilterConfig[compressorFilterName]; ok {
+		t.Errorf("route default-web-rule0-match0 has a %s override, want none", compressorFilterName)
+	}
+}
+
+func TestBuildCompressorFilterBrotli(t *testing.T) {
+	filter, err := buildCompressorFilter(CompressionBrotli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressor := &compressorv3.Compressor{}
+	if err := filter.GetTypedConfig().UnmarshalTo(compressor); err != nil {
+		t.Fatal(err)
+	}
+	if !compressor.GetCompressorLibrary().GetTypedConfig().MessageIs(&brotliv3.Brotli{}) {
+		t.Errorf("compressor library = %v, want brotli", compressor.GetCompressorLibrary())
+	}
+}
+
+func TestTranslateWithoutCompression(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	if filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80"))); slices.Contains(filters, compressorFilterName) {
+		t.Errorf("HTTP filters = %v, want no %s", filters, compressorFilterName)
+	}
+}
+
+func TestValidateCompressionInvalid(t *testing.T) {
+	if err := (Options{Compression: "zstd"}).Validate(); err == nil {
+		t.Error("Validate() of compression zstd = nil, want an error")
+	}
+	if config, err := parseCompressionAnnotations(map[string]string{compressionDisabledAnnotation: "maybe"}); err == nil {
+		t.Errorf("parseCompressionAnnotations() = %v, want an error", config)
+	}
+}
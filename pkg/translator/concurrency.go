@@ -0,0 +1,79 @@
+package translator
+
+import (
+	"fmt"
+	"sync"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// routeConcurrency returns the number of HTTPRoutes translated in parallel.
+func (o Options) routeConcurrency() int {
+	if o.RouteConcurrency < 1 {
+		return 1
+	}
+	return o.RouteConcurrency
+}
+
+// validateRouteConcurrency reports whether the route concurrency is valid.
+func validateRouteConcurrency(concurrency int) error {
+	if concurrency < 0 {
+		return fmt.Errorf("%d must not be negative", concurrency)
+	}
+	return nil
+}
+
+// httpRouteTranslation is the result of translateHTTPRouteToEnvoyRoutes for an HTTPRoute.
+type httpRouteTranslation struct {
+	routes                []*routev3.Route
+	validBackendRefs      []gatewayv1.BackendRef
+	resolvedRefsCondition metav1.Condition
+	regexErr              error
+}
+
+// translateHTTPRoutes translates the HTTPRoutes with up to RouteConcurrency workers. The
+// translations are returned in the order of the routes, so the output of the Gateway does
+// not depend on the concurrency. Only the translation of the routes themselves runs in
+// parallel: their clusters and virtual hosts are built by the caller, in order.
+func (t *Translator) translateHTTPRoutes(httpRoutes []*gatewayv1.HTTPRoute) []httpRouteTranslation {
+	translations := make([]httpRouteTranslation, len(httpRoutes))
+	translate := func(i int) {
+		routes, validBackendRefs, resolvedRefsCondition, regexErr := translateHTTPRouteToEnvoyRoutes(httpRoutes[i], t.serviceLister, t.referenceGrantLister)
+		translations[i] = httpRouteTranslation{
+			routes:                routes,
+			validBackendRefs:      validBackendRefs,
+			resolvedRefsCondition: resolvedRefsCondition,
+			regexErr:              regexErr,
+		}
+	}
+
+	workers := min(t.options.routeConcurrency(), len(httpRoutes))
+	if workers <= 1 {
+		for i := range httpRoutes {
+			translate(i)
+		}
+		return translations
+	}
+
+	// Every worker writes to the translations of the indexes it receives, so they need no
+	// further synchronization.
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				translate(i)
+			}
+		}()
+	}
+	for i := range httpRoutes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return translations
+}
@@ -0,0 +1,64 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestTranslateHTTPRoutesParallel checks that translating the HTTPRoutes in parallel produces
+// the same resources as translating them one at a time.
+func TestTranslateHTTPRoutesParallel(t *testing.T) {
+	serial, gw := newLargeTestGateway(t, Options{}, 50, 5)
+	want := translateGateway(t, serial, gw)
+	for _, concurrency := range []int{2, 8, 64} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			parallel, gw := newLargeTestGateway(t, Options{RouteConcurrency: concurrency}, 50, 5)
+			// Repeated translations catch orderings that only show up occasionally.
+			for range 5 {
+				assertResourcesEqual(t, translateGateway(t, parallel, gw), want)
+			}
+		})
+	}
+}
+
+// assertResourcesEqual checks that the resources are equal, in the same order, except for
+// the clusters, endpoints and secrets, whose order is not significant.
+func assertResourcesEqual(t *testing.T, got, want map[resourcev3.Type][]envoyproxytypes.Resource) {
+	t.Helper()
+	for typeURL, wantResources := range want {
+		gotResources := got[typeURL]
+		if len(gotResources) != len(wantResources) {
+			t.Errorf("got %d %s resources, want %d", len(gotResources), typeURL, len(wantResources))
+			continue
+		}
+		for i, wantResource := range wantResources {
+			gotResource := gotResources[i]
+			switch typeURL {
+			case resourcev3.ClusterType, resourcev3.EndpointType, resourcev3.SecretType:
+				gotResource = findResource(got, typeURL, resourceNames(want, typeURL)[i])
+			}
+			if !proto.Equal(gotResource, wantResource) {
+				t.Errorf("%s resource %d = %v, want %v", typeURL, i, gotResource, wantResource)
+			}
+		}
+	}
+}
+
+func BenchmarkTranslateHTTPRoutesParallel(b *testing.B) {
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency %d", concurrency), func(b *testing.B) {
+			tl, gw := newLargeTestGateway(b, Options{RouteConcurrency: concurrency}, 500, 10)
+			b.ResetTimer()
+			for range b.N {
+				if _, err := tl.TranslateGatewayToXDS(context.Background(), gw); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package translator
+
+import (
+	"fmt"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The annotations of a Service that override the connection settings of its clusters.
+const (
+	connectTimeoutAnnotation       = "gateway.xds/connect-timeout"
+	tcpKeepaliveTimeAnnotation     = "gateway.xds/tcp-keepalive-time"
+	tcpKeepaliveIntervalAnnotation = "gateway.xds/tcp-keepalive-interval"
+	tcpKeepaliveProbesAnnotation   = "gateway.xds/tcp-keepalive-probes"
+)
+
+// DefaultConnectTimeout is the default timeout of new connections to the upstream hosts
+// of a cluster.
+const DefaultConnectTimeout = 5 * time.Second
+
+// TCPKeepalive are the TCP keepalive settings of the connections to the upstream hosts of
+// a cluster. Keepalive is only enabled if a setting is non-zero; the zero settings keep the
+// operating system's defaults. Time and Interval have a resolution of one second.
+type TCPKeepalive struct {
+	// Time is the idle time of a connection before keepalive probes are sent.
+	Time time.Duration
+	// Interval is the time between keepalive probes.
+	Interval time.Duration
+	// Probes is the number of unanswered probes before the connection is dropped.
+	Probes uint32
+}
+
+// validate reports whether the keepalive durations are either zero or whole seconds.
+func (k TCPKeepalive) validate() error {
+	for name, d := range map[string]time.Duration{"time": k.Time, "interval": k.Interval} {
+		if d < 0 || d%time.Second != 0 {
+			return fmt.Errorf("TCP keepalive %s %s must be a positive whole number of seconds", name, d)
+		}
+	}
+	return nil
+}
+
+// connectTimeout returns the default connect timeout of the clusters.
+func (o Options) connectTimeout() time.Duration {
+	if o.ConnectTimeout == 0 {
+		return DefaultConnectTimeout
+	}
+	return o.ConnectTimeout
+}
+
+// buildConnectTimeout returns the connect timeout of a cluster for the Service, the default
+// unless the Service overrides it with an annotation.
+func buildConnectTimeout(defaultTimeout time.Duration, service *corev1.Service) (*durationpb.Duration, error) {
+	timeout, ok, err := durationAnnotation(service, connectTimeoutAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return timeout, nil
+	}
+	return durationpb.New(defaultTimeout), nil
+}
+
+// buildUpstreamConnectionOptions builds the upstream connection options of a cluster for
+// the Service from the default TCP keepalive settings, overridden by the annotations of
+// the Service. It returns nil if keepalive is not enabled.
+func buildUpstreamConnectionOptions(defaults TCPKeepalive, service *corev1.Service) (*clusterv3.UpstreamConnectionOptions, error) {
+	keepalive := defaults
+	for annotation, setting := range map[string]*time.Duration{
+		tcpKeepaliveTimeAnnotation:     &keepalive.Time,
+		tcpKeepaliveIntervalAnnotation: &keepalive.Interval,
+	} {
+		value, ok, err := durationAnnotation(service, annotation)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			*setting = value.AsDuration()
+		}
+	}
+	probes, ok, err := uint32Annotation(service, tcpKeepaliveProbesAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		keepalive.Probes = probes
+	}
+	if err := keepalive.validate(); err != nil {
+		return nil, fmt.Errorf("service %s/%s: %w", service.Namespace, service.Name, err)
+	}
+	if keepalive == (TCPKeepalive{}) {
+		return nil, nil
+	}
+
+	tcpKeepalive := &corev3.TcpKeepalive{}
+	if keepalive.Time != 0 {
+		tcpKeepalive.KeepaliveTime = wrapperspb.UInt32(uint32(keepalive.Time / time.Second))
+	}
+	if keepalive.Interval != 0 {
+		tcpKeepalive.KeepaliveInterval = wrapperspb.UInt32(uint32(keepalive.Interval / time.Second))
+	}
+	if keepalive.Probes != 0 {
+		tcpKeepalive.KeepaliveProbes = wrapperspb.UInt32(keepalive.Probes)
+	}
+	return &clusterv3.UpstreamConnectionOptions{TcpKeepalive: tcpKeepalive}, nil
+}
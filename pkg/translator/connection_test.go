@@ -0,0 +1,107 @@
+package translator
+
+import (
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTranslateConnectTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		options     Options
+		annotations map[string]string
+		want        time.Duration
+	}{
+		{name: "default", want: DefaultConnectTimeout},
+		{name: "options", options: Options{ConnectTimeout: 2 * time.Second}, want: 2 * time.Second},
+		{
+			name:        "annotated Service",
+			options:     Options{ConnectTimeout: 2 * time.Second},
+			annotations: map[string]string{connectTimeoutAnnotation: "250ms"},
+			want:        250 * time.Millisecond,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, tc.options, service)
+			if cluster.ConnectTimeout == nil {
+				t.Fatal("cluster has no connect timeout")
+			}
+			if got := cluster.ConnectTimeout.AsDuration(); got != tc.want {
+				t.Errorf("connect timeout = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildConnectTimeoutInvalid(t *testing.T) {
+	// A zero or unparsable timeout is rejected rather than passed on to Envoy.
+	for _, value := range []string{"0s", "-1s", "soon"} {
+		service := testService("backend", 80)
+		service.Annotations = map[string]string{connectTimeoutAnnotation: value}
+		if timeout, err := buildConnectTimeout(DefaultConnectTimeout, service); err == nil {
+			t.Errorf("buildConnectTimeout() of annotation %q = %v, want an error", value, timeout)
+		}
+	}
+	if err := (Options{ConnectTimeout: -time.Second}).Validate(); err == nil {
+		t.Error("Validate() of a negative connect timeout = nil, want an error")
+	}
+}
+
+func TestTranslateUpstreamTCPKeepalive(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		defaults    TCPKeepalive
+		annotations map[string]string
+		want        *corev3.TcpKeepalive
+	}{
+		{name: "unset"},
+		{
+			name:     "options",
+			defaults: TCPKeepalive{Time: time.Minute, Interval: 10 * time.Second, Probes: 3},
+			want: &corev3.TcpKeepalive{
+				KeepaliveTime:     wrapperspb.UInt32(60),
+				KeepaliveInterval: wrapperspb.UInt32(10),
+				KeepaliveProbes:   wrapperspb.UInt32(3),
+			},
+		},
+		{
+			name:        "annotation overrides default",
+			defaults:    TCPKeepalive{Time: time.Minute},
+			annotations: map[string]string{tcpKeepaliveTimeAnnotation: "30s", tcpKeepaliveProbesAnnotation: "5"},
+			want: &corev3.TcpKeepalive{
+				KeepaliveTime:   wrapperspb.UInt32(30),
+				KeepaliveProbes: wrapperspb.UInt32(5),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, Options{TCPKeepalive: tc.defaults}, service)
+
+			if tc.want == nil {
+				if cluster.UpstreamConnectionOptions != nil {
+					t.Errorf("upstream connection options = %v, want none", cluster.UpstreamConnectionOptions)
+				}
+				return
+			}
+			if got := cluster.GetUpstreamConnectionOptions().GetTcpKeepalive(); !proto.Equal(got, tc.want) {
+				t.Errorf("TCP keepalive = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildUpstreamConnectionOptionsInvalid(t *testing.T) {
+	service := testService("backend", 80)
+	service.Annotations = map[string]string{tcpKeepaliveIntervalAnnotation: "1500ms"}
+	if options, err := buildUpstreamConnectionOptions(TCPKeepalive{}, service); err == nil {
+		t.Errorf("buildUpstreamConnectionOptions() = %v, want an error", options)
+	}
+}
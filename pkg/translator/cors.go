@@ -0,0 +1,91 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The annotations of an HTTPRoute that configure the CORS policy of its routes. The lists
+// are comma-separated.
+const (
+	corsAllowOriginsAnnotation     = "gateway.xds/cors-allow-origins"
+	corsAllowMethodsAnnotation     = "gateway.xds/cors-allow-methods"
+	corsAllowHeadersAnnotation     = "gateway.xds/cors-allow-headers"
+	corsExposeHeadersAnnotation    = "gateway.xds/cors-expose-headers"
+	corsMaxAgeAnnotation           = "gateway.xds/cors-max-age"
+	corsAllowCredentialsAnnotation = "gateway.xds/cors-allow-credentials"
+)
+
+// parseCORSAnnotations reads the CORS policy from the annotations of an HTTPRoute and
+// returns it as the per-route config of the CORS filter, or nil if the HTTPRoute allows
+// no origins. An origin of "*" allows all origins.
+func parseCORSAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	origins, ok := annotations[corsAllowOriginsAnnotation]
+	if !ok {
+		for _, annotation := range []string{corsAllowMethodsAnnotation, corsAllowHeadersAnnotation, corsExposeHeadersAnnotation, corsMaxAgeAnnotation, corsAllowCredentialsAnnotation} {
+			if _, ok := annotations[annotation]; ok {
+				return nil, fmt.Errorf("annotation %s requires annotation %s", annotation, corsAllowOriginsAnnotation)
+			}
+		}
+		return nil, nil
+	}
+
+	corsPolicy := &corsv3.CorsPolicy{
+		AllowMethods:  joinList(annotations[corsAllowMethodsAnnotation]),
+		AllowHeaders:  joinList(annotations[corsAllowHeadersAnnotation]),
+		ExposeHeaders: joinList(annotations[corsExposeHeadersAnnotation]),
+	}
+	for _, origin := range splitList(origins) {
+		matcher := &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_Exact{Exact: origin},
+		}
+		if origin == "*" {
+			regexMatcher, err := newRegexMatcher(".*")
+			if err != nil {
+				return nil, err
+			}
+			matcher.MatchPattern = &matcherv3.StringMatcher_SafeRegex{SafeRegex: regexMatcher}
+		}
+		corsPolicy.AllowOriginStringMatch = append(corsPolicy.AllowOriginStringMatch, matcher)
+	}
+	if len(corsPolicy.AllowOriginStringMatch) == 0 {
+		return nil, fmt.Errorf("annotation %s must list at least one origin", corsAllowOriginsAnnotation)
+	}
+	if value, ok := annotations[corsMaxAgeAnnotation]; ok {
+		maxAge, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s must be a number of seconds: %w", corsMaxAgeAnnotation, err)
+		}
+		corsPolicy.MaxAge = strconv.FormatUint(maxAge, 10)
+	}
+	if value, ok := annotations[corsAllowCredentialsAnnotation]; ok {
+		allowCredentials, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", corsAllowCredentialsAnnotation, err)
+		}
+		corsPolicy.AllowCredentials = wrapperspb.Bool(allowCredentials)
+	}
+	return anypb.New(corsPolicy)
+}
+
+// splitList splits a comma-separated list, dropping empty elements.
+func splitList(value string) []string {
+	var elements []string
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// joinList normalizes a comma-separated list to the format expected by Envoy.
+func joinList(value string) string {
+	return strings.Join(splitList(value), ",")
+}
@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	corsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+)
+
+func TestTranslateHTTPRouteCORS(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Annotations = map[string]string{
+		corsAllowOriginsAnnotation: "https://a.example.com, https://b.example.com",
+		corsAllowMethodsAnnotation: "GET,POST",
+		corsMaxAgeAnnotation:       "600",
+	}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+	corsConfig, ok := envoyRoute.TypedPerFilterConfig[wellknown.CORS]
+	if !ok {
+		t.Fatalf("route has no CORS policy")
+	}
+	corsPolicy := &corsv3.CorsPolicy{}
+	if err := corsConfig.UnmarshalTo(corsPolicy); err != nil {
+		t.Fatal(err)
+	}
+	var origins []string
+	for _, matcher := range corsPolicy.AllowOriginStringMatch {
+		origins = append(origins, matcher.GetExact())
+	}
+	if want := []string{"https://a.example.com", "https://b.example.com"}; !slices.Equal(origins, want) {
+		t.Errorf("allowed origins = %v, want %v", origins, want)
+	}
+	if corsPolicy.AllowMethods != "GET,POST" {
+		t.Errorf("allowed methods = %q, want GET,POST", corsPolicy.AllowMethods)
+	}
+	if corsPolicy.MaxAge != "600" {
+		t.Errorf("max age = %q, want 600", corsPolicy.MaxAge)
+	}
+
+	// The per-route policies only take effect if the connection manager runs the CORS filter
+	// ahead of the router.
+	filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80")))
+	cors, router := slices.Index(filters, wellknown.CORS), slices.Index(filters, wellknown.Router)
+	if cors < 0 || cors > router {
+		t.Errorf("HTTP filters = %v, want %s ahead of %s", filters, wellknown.CORS, wellknown.Router)
+	}
+}
+
+func TestParseCORSAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		wantPolicy  bool
+		wantErr     bool
+	}{
+		{name: "no annotations"},
+		{name: "wildcard origin", annotations: map[string]string{corsAllowOriginsAnnotation: "*"}, wantPolicy: true},
+		{name: "methods without origins", annotations: map[string]string{corsAllowMethodsAnnotation: "GET"}, wantErr: true},
+		{name: "empty origins", annotations: map[string]string{corsAllowOriginsAnnotation: " , "}, wantErr: true},
+		{name: "invalid max age", annotations: map[string]string{corsAllowOriginsAnnotation: "*", corsMaxAgeAnnotation: "soon"}, wantErr: true},
+		{name: "invalid credentials", annotations: map[string]string{corsAllowOriginsAnnotation: "*", corsAllowCredentialsAnnotation: "maybe"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := parseCORSAnnotations(tc.annotations)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseCORSAnnotations() error = %v, want error %v", err, tc.wantErr)
+			}
+			if (policy != nil) != tc.wantPolicy {
+				t.Errorf("parseCORSAnnotations() = %v, want a policy %v", policy, tc.wantPolicy)
+			}
+		})
+	}
+}
+
+// The wildcard origin is matched by a regex that is validated like the other regexes.
+func TestParseCORSAnnotationsWildcardOrigin(t *testing.T) {
+	policyAny, err := parseCORSAnnotations(map[string]string{corsAllowOriginsAnnotation: "*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	corsPolicy := &corsv3.CorsPolicy{}
+	if err := policyAny.UnmarshalTo(corsPolicy); err != nil {
+		t.Fatal(err)
+	}
+	regex := corsPolicy.AllowOriginStringMatch[0].GetSafeRegex()
+	if regex.GetRegex() != ".*" || regex.GetGoogleRe2() == nil {
+		t.Errorf("origin matcher = %v, want the RE2 regex .*", regex)
+	}
+}
@@ -0,0 +1,47 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// parseDefaultBackend parses the namespace/name:port of the default backend Service into a
+// backendRef.
+func parseDefaultBackend(value string) (gatewayv1.BackendRef, error) {
+	namespace, nameAndPort, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" {
+		return gatewayv1.BackendRef{}, fmt.Errorf("%q must have the form namespace/name:port", value)
+	}
+	name, port, err := parseServiceAddress(nameAndPort)
+	if err != nil || name == "" {
+		return gatewayv1.BackendRef{}, fmt.Errorf("%q must have the form namespace/name:port", value)
+	}
+	ns := gatewayv1.Namespace(namespace)
+	portNumber := gatewayv1.PortNumber(port)
+	return gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name:      gatewayv1.ObjectName(name),
+			Namespace: &ns,
+			Port:      &portNumber,
+		},
+	}, nil
+}
+
+// buildDefaultBackendRoute builds the catch-all route of a virtual host that forwards the
+// requests that match none of its other routes to the cluster of the default backend.
+func buildDefaultBackendRoute(vh *routev3.VirtualHost, clusterName string) *routev3.Route {
+	return &routev3.Route{
+		Name: vh.Name + "-default-backend",
+		Match: &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+		},
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: clusterName},
+			},
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// TestTranslateDefaultBackend checks that the requests matching no route of any virtual host
+// are forwarded to the cluster of the default backend, which lives in its own namespace.
+func TestTranslateDefaultBackend(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	web := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	web.Spec.Hostnames = []gatewayv1.Hostname{"web.example.com"}
+	api := testHTTPRoute("api", "gw", testBackendRef("backend", 80))
+	api.Spec.Hostnames = []gatewayv1.Hostname{"api.example.com"}
+	fallback := testService("default-backend", 8080)
+	fallback.Namespace = "fallback"
+	tl := newTestTranslator(t, Options{DefaultBackend: "fallback/default-backend:8080"}, testGatewayClass(), gw,
+		testService("backend", 80), fallback, web, api)
+	resources := translateGateway(t, tl, gw)
+
+	const fallbackCluster = "fallback_default-backend_core_Service_8080"
+	findCluster(t, resources, fallbackCluster)
+	routeConfiguration := findRouteConfiguration(t, resources, "route-80")
+	if len(routeConfiguration.VirtualHosts) != 2 {
+		t.Fatalf("got %d virtual hosts, want 2", len(routeConfiguration.VirtualHosts))
+	}
+	for _, vh := range routeConfiguration.VirtualHosts {
+		// The catch-all route comes last, so it only gets the requests matching no other route.
+		catchAll := vh.Routes[len(vh.Routes)-1]
+		if catchAll.Name != vh.Name+"-default-backend" {
+			t.Fatalf("virtual host %s ends with route %s, want the default backend", vh.Name, catchAll.Name)
+		}
+		if got := catchAll.GetMatch().GetPrefix(); got != "/" {
+			t.Errorf("virtual host %s catch-all prefix = %q, want /", vh.Name, got)
+		}
+		if got := catchAll.GetRoute().GetCluster(); got != fallbackCluster {
+			t.Errorf("virtual host %s catch-all cluster = %q, want %q", vh.Name, got, fallbackCluster)
+		}
+	}
+}
+
+func TestTranslateWithoutDefaultBackend(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	catchAll := vh.Routes[len(vh.Routes)-1]
+	if got := catchAll.GetDirectResponse().GetStatus(); got != DefaultNotFoundStatus {
+		t.Errorf("catch-all route = %v, want a %d direct response", catchAll, DefaultNotFoundStatus)
+	}
+}
+
+func TestTranslateMissingDefaultBackend(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{DefaultBackend: "fallback/default-backend:8080"}, testGatewayClass(), gw,
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources, _, _, err := tl.buildEnvoyResourcesForGateway(gw)
+	if err == nil || !strings.Contains(err.Error(), "default backend") {
+		t.Fatalf("buildEnvoyResourcesForGateway() error = %v, want a default backend error", err)
+	}
+	// The requests matching no route are answered as not found instead.
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	if catchAll := vh.Routes[len(vh.Routes)-1]; catchAll.GetDirectResponse() == nil {
+		t.Errorf("catch-all route = %v, want a direct response", catchAll)
+	}
+}
+
+func TestParseDefaultBackendInvalid(t *testing.T) {
+	for _, value := range []string{"default-backend:80", "/default-backend:80", "fallback/default-backend", "fallback/:80", "fallback/default-backend:http"} {
+		if backendRef, err := parseDefaultBackend(value); err == nil {
+			t.Errorf("parseDefaultBackend(%q) = %v, want an error", value, backendRef)
+		}
+	}
+}
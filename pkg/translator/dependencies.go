@@ -0,0 +1,99 @@
+package translator
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Dependencies are the Services and Secrets that the translation of a Gateway reads, so
+// that the Gateway only needs to be translated again when one of them changes.
+type Dependencies struct {
+	// Services are the Services referenced by the backends of the routes attached to the
+	// Gateway, and the default backend. Their EndpointSlices are dependencies too.
+	Services sets.Set[types.NamespacedName]
+	// Secrets are the certificates and client CA bundles of the Gateway's listeners.
+	Secrets sets.Set[types.NamespacedName]
+}
+
+// GatewayDependencies returns the Services and Secrets that the translation of the Gateway
+// reads. Other objects, such as ReferenceGrants or Namespaces, are not tracked per Gateway.
+func (t *Translator) GatewayDependencies(gw *gatewayv1.Gateway) Dependencies {
+	deps := Dependencies{
+		Services: sets.New[types.NamespacedName](),
+		Secrets:  sets.New[types.NamespacedName](),
+	}
+
+	for _, listener := range gw.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, certRef := range listener.TLS.CertificateRefs {
+			namespace := gw.Namespace
+			if certRef.Namespace != nil {
+				namespace = string(*certRef.Namespace)
+			}
+			deps.Secrets.Insert(types.NamespacedName{Namespace: namespace, Name: string(certRef.Name)})
+		}
+		if name, ok := listener.TLS.Options[clientCASecretOption]; ok {
+			deps.Secrets.Insert(types.NamespacedName{Namespace: gw.Namespace, Name: string(name)})
+		}
+	}
+
+	addBackends := func(namespace string, backendRefs ...gatewayv1.BackendObjectReference) {
+		for _, backendRef := range backendRefs {
+			if backendRef.Kind != nil && *backendRef.Kind != "Service" {
+				continue
+			}
+			ns := namespace
+			if backendRef.Namespace != nil {
+				ns = string(*backendRef.Namespace)
+			}
+			deps.Services.Insert(types.NamespacedName{Namespace: ns, Name: string(backendRef.Name)})
+		}
+	}
+	if defaultBackend, err := parseDefaultBackend(t.options.DefaultBackend); err == nil {
+		addBackends(gw.Namespace, defaultBackend.BackendObjectReference)
+	}
+	for _, route := range t.getHTTPRoutesForGateway(gw) {
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				addBackends(route.Namespace, backendRef.BackendObjectReference)
+			}
+			for _, filter := range rule.Filters {
+				if filter.RequestMirror != nil {
+					addBackends(route.Namespace, filter.RequestMirror.BackendRef)
+				}
+			}
+		}
+	}
+	for _, route := range t.getGRPCRoutesForGateway(gw) {
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				addBackends(route.Namespace, backendRef.BackendObjectReference)
+			}
+		}
+	}
+	for _, route := range t.getTCPRoutesForGateway(gw) {
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				addBackends(route.Namespace, backendRef.BackendObjectReference)
+			}
+		}
+	}
+	for _, route := range t.getTLSRoutesForGateway(gw) {
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				addBackends(route.Namespace, backendRef.BackendObjectReference)
+			}
+		}
+	}
+	for _, route := range t.getUDPRoutesForGateway(gw) {
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				addBackends(route.Namespace, backendRef.BackendObjectReference)
+			}
+		}
+	}
+	return deps
+}
@@ -0,0 +1,36 @@
+package translator
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGatewayDependencies(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80), httpsListener("https", 443, "web-cert"))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+			BackendRef: gatewayv1.BackendObjectReference{Name: "shadow", Port: ptr(gatewayv1.PortNumber(80))},
+		},
+	}}
+	otherRoute := testHTTPRoute("other", "other-gw", testBackendRef("other", 80))
+	tl := newTestTranslator(t, Options{DefaultBackend: "fallback/default-backend:80"}, testGatewayClass(), gw, route, otherRoute)
+
+	deps := tl.GatewayDependencies(gw)
+	wantServices := sets.New(
+		types.NamespacedName{Namespace: "default", Name: "backend"},
+		types.NamespacedName{Namespace: "default", Name: "shadow"},
+		types.NamespacedName{Namespace: "fallback", Name: "default-backend"},
+	)
+	if !deps.Services.Equal(wantServices) {
+		t.Errorf("Services = %v, want %v", deps.Services.UnsortedList(), wantServices.UnsortedList())
+	}
+	wantSecrets := sets.New(types.NamespacedName{Namespace: "default", Name: "web-cert"})
+	if !deps.Secrets.Equal(wantSecrets) {
+		t.Errorf("Secrets = %v, want %v", deps.Secrets.UnsortedList(), wantSecrets.UnsortedList())
+	}
+}
@@ -0,0 +1,134 @@
+package translator
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+)
+
+// buildClusterLoadAssignment builds the EDS ClusterLoadAssignment of a Service port from the
+// ready addresses of the Service's EndpointSlices. The endpoint port is taken from the
+// EndpointSlices, so named target ports are resolved the same way kube-proxy does. Endpoints
+// are grouped into one locality per zone, so that Envoy can prefer the endpoints of its own
+// zone.
+func buildClusterLoadAssignment(
+	clusterName string,
+	service *corev1.Service,
+	servicePort int32,
+	endpointSliceLister discoverylisters.EndpointSliceLister,
+) (*endpointv3.ClusterLoadAssignment, error) {
+	var portName string
+	found := false
+	for _, port := range service.Spec.Ports {
+		if port.Port == servicePort {
+			portName = port.Name
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("could not find port %d in service %s/%s", servicePort, service.Namespace, service.Name)
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service.Name})
+	endpointSlices, err := endpointSliceLister.EndpointSlices(service.Namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSlices for service %s/%s: %w", service.Namespace, service.Name, err)
+	}
+
+	// The same endpoint can be part of several slices while they are being updated.
+	seen := sets.New[string]()
+	lbEndpointsByZone := make(map[string][]*endpointv3.LbEndpoint)
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.AddressType != discoveryv1.AddressTypeIPv4 && endpointSlice.AddressType != discoveryv1.AddressTypeIPv6 {
+			continue
+		}
+		endpointPort, ok := endpointSlicePort(endpointSlice, portName)
+		if !ok {
+			continue
+		}
+		for _, endpoint := range endpointSlice.Endpoints {
+			// A nil Ready condition must be interpreted as ready.
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			zone := ""
+			if endpoint.Zone != nil {
+				zone = *endpoint.Zone
+			}
+			for _, address := range endpoint.Addresses {
+				// IPv6 addresses are bracketed in the key only, Envoy takes them as they are.
+				key := net.JoinHostPort(address, strconv.Itoa(int(endpointPort)))
+				if seen.Has(key) {
+					continue
+				}
+				seen.Insert(key)
+				lbEndpointsByZone[zone] = append(lbEndpointsByZone[zone], createLbEndpoint(address, uint32(endpointPort)))
+			}
+		}
+	}
+	cla := &endpointv3.ClusterLoadAssignment{
+		ClusterName: clusterName,
+	}
+	// Keep the output stable regardless of the order of the slices in the cache.
+	for _, zone := range sets.List(sets.KeySet(lbEndpointsByZone)) {
+		lbEndpoints := lbEndpointsByZone[zone]
+		sort.Slice(lbEndpoints, func(i, j int) bool {
+			addressI := lbEndpoints[i].GetEndpoint().GetAddress().GetSocketAddress()
+			addressJ := lbEndpoints[j].GetEndpoint().GetAddress().GetSocketAddress()
+			if addressI.GetAddress() != addressJ.GetAddress() {
+				return addressI.GetAddress() < addressJ.GetAddress()
+			}
+			return addressI.GetPortValue() < addressJ.GetPortValue()
+		})
+		localityLbEndpoints := &endpointv3.LocalityLbEndpoints{LbEndpoints: lbEndpoints}
+		// Endpoints without a zone keep the empty locality.
+		if zone != "" {
+			localityLbEndpoints.Locality = &corev3.Locality{Zone: zone}
+		}
+		cla.Endpoints = append(cla.Endpoints, localityLbEndpoints)
+	}
+	return cla, nil
+}
+
+// endpointSlicePort returns the port of the EndpointSlice with the given name.
+func endpointSlicePort(endpointSlice *discoveryv1.EndpointSlice, portName string) (int32, bool) {
+	for _, port := range endpointSlice.Ports {
+		name := ""
+		if port.Name != nil {
+			name = *port.Name
+		}
+		if name == portName && port.Port != nil {
+			return *port.Port, true
+		}
+	}
+	return 0, false
+}
+
+func createLbEndpoint(address string, port uint32) *endpointv3.LbEndpoint {
+	return &endpointv3.LbEndpoint{
+		HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+			Endpoint: &endpointv3.Endpoint{
+				Address: &corev3.Address{
+					Address: &corev3.Address_SocketAddress{
+						SocketAddress: &corev3.SocketAddress{
+							Address: address,
+							PortSpecifier: &corev3.SocketAddress_PortValue{
+								PortValue: port,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
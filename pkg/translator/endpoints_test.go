@@ -0,0 +1,130 @@
+package translator
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+func findClusterLoadAssignment(t testing.TB, resources map[resourcev3.Type][]envoyproxytypes.Resource, name string) *endpointv3.ClusterLoadAssignment {
+	t.Helper()
+	cla, ok := findResource(resources, resourcev3.EndpointType, name).(*endpointv3.ClusterLoadAssignment)
+	if !ok {
+		t.Fatalf("endpoints %s not found in %v", name, resourceNames(resources, resourcev3.EndpointType))
+	}
+	return cla
+}
+
+// lbEndpointAddresses returns the host:port addresses of the endpoints of the locality.
+func lbEndpointAddresses(localityLbEndpoints *endpointv3.LocalityLbEndpoints) []string {
+	var addresses []string
+	for _, lbEndpoint := range localityLbEndpoints.LbEndpoints {
+		socketAddress := lbEndpoint.GetEndpoint().GetAddress().GetSocketAddress()
+		addresses = append(addresses, net.JoinHostPort(socketAddress.GetAddress(), strconv.Itoa(int(socketAddress.GetPortValue()))))
+	}
+	return addresses
+}
+
+func TestTranslateEndpointSlices(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	endpointSlice := testEndpointSlice("backend-1", "backend", 8080, "10.1.0.1", "10.1.0.2", "10.1.0.3")
+	endpointSlice.Endpoints[1].Conditions.Ready = ptr(true)
+	endpointSlice.Endpoints[2].Conditions.Ready = ptr(false)
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend", 80), endpointSlice, testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	cluster := findCluster(t, resources, clusterName("backend", 80))
+	if cluster.GetType() != clusterv3.Cluster_EDS {
+		t.Errorf("cluster type = %v, want EDS", cluster.GetType())
+	}
+	if cluster.GetEdsClusterConfig().GetEdsConfig().GetAds() == nil {
+		t.Errorf("EDS config = %v, want ADS", cluster.GetEdsClusterConfig().GetEdsConfig())
+	}
+
+	// The endpoints listen on the port of the EndpointSlice, not on the Service port, and
+	// the endpoint that is not ready is left out.
+	cla := findClusterLoadAssignment(t, resources, clusterName("backend", 80))
+	if len(cla.Endpoints) != 1 {
+		t.Fatalf("got %d localities, want 1", len(cla.Endpoints))
+	}
+	if got, want := lbEndpointAddresses(cla.Endpoints[0]), []string{"10.1.0.1:8080", "10.1.0.2:8080"}; !slices.Equal(got, want) {
+		t.Errorf("lb endpoints = %v, want %v", got, want)
+	}
+}
+
+// TestTranslateEndpointSliceZones checks that endpoints are grouped into one locality per
+// zone, ordered by zone, and that endpoints without a zone keep the empty locality.
+func TestTranslateEndpointSliceZones(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	endpointSlice := testEndpointSlice("backend-1", "backend", 8080, "10.1.0.1", "10.1.0.2", "10.1.0.3", "10.1.0.4")
+	endpointSlice.Endpoints[0].Zone = ptr("zone-b")
+	endpointSlice.Endpoints[1].Zone = ptr("zone-a")
+	endpointSlice.Endpoints[2].Zone = ptr("zone-b")
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend", 80), endpointSlice, testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	cla := findClusterLoadAssignment(t, resources, clusterName("backend", 80))
+	want := []struct {
+		zone      string
+		addresses []string
+	}{
+		{zone: "", addresses: []string{"10.1.0.4:8080"}},
+		{zone: "zone-a", addresses: []string{"10.1.0.2:8080"}},
+		{zone: "zone-b", addresses: []string{"10.1.0.1:8080", "10.1.0.3:8080"}},
+	}
+	if len(cla.Endpoints) != len(want) {
+		t.Fatalf("got %d localities, want %d", len(cla.Endpoints), len(want))
+	}
+	for i, locality := range cla.Endpoints {
+		if got := locality.GetLocality().GetZone(); got != want[i].zone {
+			t.Errorf("locality %d zone = %q, want %q", i, got, want[i].zone)
+		}
+		if got := lbEndpointAddresses(locality); !slices.Equal(got, want[i].addresses) {
+			t.Errorf("locality %d lb endpoints = %v, want %v", i, got, want[i].addresses)
+		}
+		// EndpointSlice endpoints have no weight, so they keep Envoy's default weight.
+		for _, lbEndpoint := range locality.LbEndpoints {
+			if lbEndpoint.GetLoadBalancingWeight() != nil {
+				t.Errorf("lb endpoint %v has a load balancing weight, want the default", lbEndpoint)
+			}
+		}
+	}
+}
+
+// TestTranslateDualStackEndpointSlices checks that the endpoints of the IPv4 and IPv6
+// EndpointSlices of a dual-stack Service are all kept, with the IPv6 addresses unbracketed.
+func TestTranslateDualStackEndpointSlices(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	ipv6Slice := testEndpointSlice("backend-v6", "backend", 8080, "fd00::2", "fd00::1")
+	ipv6Slice.AddressType = discoveryv1.AddressTypeIPv6
+	// The same endpoint can be part of a second slice while the slices are being updated.
+	updatedIPv6Slice := testEndpointSlice("backend-v6-updated", "backend", 8080, "fd00::1")
+	updatedIPv6Slice.AddressType = discoveryv1.AddressTypeIPv6
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+		testEndpointSlice("backend-v4", "backend", 8080, "10.1.0.1"), ipv6Slice, updatedIPv6Slice,
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	cla := findClusterLoadAssignment(t, resources, clusterName("backend", 80))
+	if len(cla.Endpoints) != 1 {
+		t.Fatalf("got %d localities, want 1", len(cla.Endpoints))
+	}
+	if got, want := lbEndpointAddresses(cla.Endpoints[0]), []string{"10.1.0.1:8080", "[fd00::1]:8080", "[fd00::2]:8080"}; !slices.Equal(got, want) {
+		t.Errorf("lb endpoints = %v, want %v", got, want)
+	}
+	for _, lbEndpoint := range cla.Endpoints[0].LbEndpoints {
+		address := lbEndpoint.GetEndpoint().GetAddress().GetSocketAddress().GetAddress()
+		if net.ParseIP(address) == nil {
+			t.Errorf("endpoint address %q is not a plain IP address", address)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extauthzv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// extAuthzClusterName is the name of the cluster of the external authorization service.
+	extAuthzClusterName = "ext-authz-service"
+	// extAuthzTimeout is how long a request waits for the authorization decision.
+	extAuthzTimeout = time.Second
+)
+
+// extAuthzDisabledAnnotation is the annotation of an HTTPRoute that exempts its routes from
+// external authorization.
+const extAuthzDisabledAnnotation = "gateway.xds/ext-authz-disabled"
+
+// buildExtAuthzFilter builds the external authorization filter of an HTTP connection manager,
+// which asks the HTTP authorization service at the options' address whether a request is allowed.
+func buildExtAuthzFilter(options Options) (*anypb.Any, error) {
+	pathPrefix := options.ExtAuthzPathPrefix
+	if pathPrefix != "" && !strings.HasPrefix(pathPrefix, "/") {
+		pathPrefix = "/" + pathPrefix
+	}
+	return anypb.New(&extauthzv3.ExtAuthz{
+		Services: &extauthzv3.ExtAuthz_HttpService{
+			HttpService: &extauthzv3.HttpService{
+				ServerUri: &corev3.HttpUri{
+					Uri: "http://" + options.ExtAuthzService,
+					HttpUpstreamType: &corev3.HttpUri_Cluster{
+						Cluster: extAuthzClusterName,
+					},
+					Timeout: durationpb.New(extAuthzTimeout),
+				},
+				PathPrefix: pathPrefix,
+			},
+		},
+		TransportApiVersion: corev3.ApiVersion_V3,
+		FailureModeAllow:    options.ExtAuthzFailureModeAllow,
+	})
+}
+
+// parseExtAuthzAnnotations returns the per-route config of the external authorization filter
+// that disables it for the routes of an HTTPRoute, or nil if the routes are authorized.
+func parseExtAuthzAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	value, ok := annotations[extAuthzDisabledAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", extAuthzDisabledAnnotation, err)
+	}
+	if !disabled {
+		return nil, nil
+	}
+	return anypb.New(&extauthzv3.ExtAuthzPerRoute{
+		Override: &extauthzv3.ExtAuthzPerRoute_Disabled{Disabled: true},
+	})
+}
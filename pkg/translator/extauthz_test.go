@@ -0,0 +1,80 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	extauthzv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateExtAuthz(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	public := testHTTPRoute("public", "gw", testBackendRef("backend", 80))
+	public.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/public")}
+	public.Annotations = map[string]string{extAuthzDisabledAnnotation: "true"}
+	private := testHTTPRoute("private", "gw", testBackendRef("backend", 80))
+	options := Options{ExtAuthzService: "authz.default.svc:9000", ExtAuthzPathPrefix: "check", ExtAuthzFailureModeAllow: true}
+	tl := newTestTranslator(t, options, testGatewayClass(), gw, testService("backend", 80), public, private)
+	resources := translateGateway(t, tl, gw)
+
+	// Every request is authorized by the filter of the connection manager.
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	filters := httpFilterNames(manager)
+	i := slices.Index(filters, wellknown.HTTPExternalAuthorization)
+	if i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Fatalf("HTTP filters = %v, want %s before the router", filters, wellknown.HTTPExternalAuthorization)
+	}
+	extAuthz := &extauthzv3.ExtAuthz{}
+	if err := manager.HttpFilters[i].GetTypedConfig().UnmarshalTo(extAuthz); err != nil {
+		t.Fatal(err)
+	}
+	httpService := extAuthz.GetHttpService()
+	if httpService.GetServerUri().GetCluster() != extAuthzClusterName || httpService.PathPrefix != "/check" || !extAuthz.FailureModeAllow {
+		t.Errorf("ext_authz filter = %v, want cluster %s, path prefix /check and failure mode allow", extAuthz, extAuthzClusterName)
+	}
+	if cluster := findCluster(t, resources, extAuthzClusterName); cluster.GetType() != clusterv3.Cluster_STRICT_DNS {
+		t.Errorf("ext_authz cluster type = %v, want STRICT_DNS", cluster.GetType())
+	}
+
+	// Only the route that opts out disables the filter.
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	perRouteConfig, ok := findRoute(t, vh, "default-public-rule0-match0").TypedPerFilterConfig[wellknown.HTTPExternalAuthorization]
+	if !ok {
+		t.Fatalf("route default-public-rule0-match0 has no %s override", wellknown.HTTPExternalAuthorization)
+	}
+	extAuthzPerRoute := &extauthzv3.ExtAuthzPerRoute{}
+	if err := perRouteConfig.UnmarshalTo(extAuthzPerRoute); err != nil {
+		t.Fatal(err)
+	}
+	if !extAuthzPerRoute.GetDisabled() {
+		t.Errorf("route default-public-rule0-match0 ext_authz override = %v, want disabled", extAuthzPerRoute)
+	}
+	if _, ok := findRoute(t, vh, "default-private-rule0-match0").TypedPerFilterConfig[wellknown.HTTPExternalAuthorization]; ok {
+		t.Errorf("route default-private-rule0-match0 has a %s override, want none", wellknown.HTTPExternalAuthorization)
+	}
+}
+
+func TestParseExtAuthzAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		value        string
+		wantDisabled bool
+		wantErr      bool
+	}{
+		{value: "true", wantDisabled: true},
+		{value: "false"},
+		{value: "yes", wantErr: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			perRouteConfig, err := parseExtAuthzAnnotations(map[string]string{extAuthzDisabledAnnotation: tc.value})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseExtAuthzAnnotations(%q) error = %v, want error %v", tc.value, err, tc.wantErr)
+			}
+			if (perRouteConfig != nil) != tc.wantDisabled {
+				t.Errorf("parseExtAuthzAnnotations(%q) = %v, want disabled %v", tc.value, perRouteConfig, tc.wantDisabled)
+			}
+		})
+	}
+}
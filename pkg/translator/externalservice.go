@@ -0,0 +1,51 @@
+package translator
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// parseServiceAddress splits the host:port address of a service outside of the cluster's
+// Gateway API resources, such as the rate limit service.
+func parseServiceAddress(address string) (string, uint32, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid service address %q: %w", address, err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil || port == 0 {
+		return "", 0, fmt.Errorf("invalid port in service address %q", address)
+	}
+	return host, uint32(port), nil
+}
+
+// buildStrictDNSCluster builds a cluster for the service at the host:port address, which
+// is resolved through DNS.
+func buildStrictDNSCluster(name, address string) (*clusterv3.Cluster, error) {
+	host, port, err := parseServiceAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterv3.Cluster{
+		Name:                 name,
+		ConnectTimeout:       durationpb.New(DefaultConnectTimeout),
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STRICT_DNS},
+		LoadAssignment:       buildDNSLoadAssignment(name, host, port),
+	}, nil
+}
+
+// buildDNSLoadAssignment builds the load assignment of a DNS cluster, whose single endpoint
+// is the hostname that Envoy resolves.
+func buildDNSLoadAssignment(clusterName, host string, port uint32) *endpointv3.ClusterLoadAssignment {
+	return &endpointv3.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints: []*endpointv3.LocalityLbEndpoints{{
+			LbEndpoints: []*endpointv3.LbEndpoint{createLbEndpoint(host, port)},
+		}},
+	}
+}
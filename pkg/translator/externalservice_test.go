@@ -0,0 +1,45 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTranslateExternalNameService(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: testNamespace},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "api.example.org"},
+	}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, service, testHTTPRoute("web", "gw", testBackendRef("external", 8443)))
+	resources := translateGateway(t, tl, gw)
+
+	// Envoy resolves the external hostname itself, on the port of the backendRef.
+	cluster := findCluster(t, resources, clusterName("external", 8443))
+	if cluster.GetType() != clusterv3.Cluster_STRICT_DNS || cluster.EdsClusterConfig != nil {
+		t.Errorf("cluster type = %v, want STRICT_DNS without EDS", cluster.GetType())
+	}
+	if localities := cluster.GetLoadAssignment().GetEndpoints(); len(localities) != 1 || !slices.Equal(lbEndpointAddresses(localities[0]), []string{"api.example.org:8443"}) {
+		t.Errorf("cluster load assignment = %v, want api.example.org:8443", cluster.LoadAssignment)
+	}
+	if endpoints := resourceNames(resources, resourcev3.EndpointType); slices.Contains(endpoints, clusterName("external", 8443)) {
+		t.Errorf("endpoints = %v, want none served over EDS for the ExternalName Service", endpoints)
+	}
+}
+
+func TestParseServiceAddress(t *testing.T) {
+	host, port, err := parseServiceAddress("ratelimit.default.svc:8081")
+	if err != nil || host != "ratelimit.default.svc" || port != 8081 {
+		t.Errorf("parseServiceAddress() = %s, %d, %v, want ratelimit.default.svc, 8081", host, port, err)
+	}
+	for _, address := range []string{"ratelimit.default.svc", "ratelimit.default.svc:0", "ratelimit.default.svc:http", "ratelimit.default.svc:65536"} {
+		if _, _, err := parseServiceAddress(address); err == nil {
+			t.Errorf("parseServiceAddress(%q) error = nil, want an error", address)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	faultcommonv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// The annotations of an HTTPRoute that inject faults into a percentage of its requests.
+const (
+	faultAbortPercentAnnotation = "gateway.xds/fault-abort-percent"
+	faultAbortStatusAnnotation  = "gateway.xds/fault-abort-status"
+	faultDelayPercentAnnotation = "gateway.xds/fault-delay-percent"
+	faultDelayAnnotation        = "gateway.xds/fault-delay"
+)
+
+const defaultFaultAbortStatus = 503
+
+// parseFaultAnnotations reads the faults from the annotations of an HTTPRoute and returns the
+// per-route config of the fault filter, or nil if no faults are injected. Aborted requests
+// are answered with a 503 by default.
+func parseFaultAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	fault := &faultv3.HTTPFault{}
+
+	if value, ok := annotations[faultAbortPercentAnnotation]; ok {
+		percentage, err := parseFaultPercentage(value)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", faultAbortPercentAnnotation, err)
+		}
+		status := uint64(defaultFaultAbortStatus)
+		if value, ok := annotations[faultAbortStatusAnnotation]; ok {
+			status, err = strconv.ParseUint(value, 10, 32)
+			if err != nil || status < 200 || status > 599 {
+				return nil, fmt.Errorf("annotation %s: invalid HTTP status %q", faultAbortStatusAnnotation, value)
+			}
+		}
+		fault.Abort = &faultv3.FaultAbort{
+			ErrorType:  &faultv3.FaultAbort_HttpStatus{HttpStatus: uint32(status)},
+			Percentage: percentage,
+		}
+	} else if _, ok := annotations[faultAbortStatusAnnotation]; ok {
+		return nil, fmt.Errorf("annotation %s requires annotation %s", faultAbortStatusAnnotation, faultAbortPercentAnnotation)
+	}
+
+	_, hasDelayPercent := annotations[faultDelayPercentAnnotation]
+	delayValue, hasDelay := annotations[faultDelayAnnotation]
+	if hasDelayPercent != hasDelay {
+		return nil, fmt.Errorf("annotations %s and %s must be set together", faultDelayPercentAnnotation, faultDelayAnnotation)
+	}
+	if hasDelay {
+		percentage, err := parseFaultPercentage(annotations[faultDelayPercentAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", faultDelayPercentAnnotation, err)
+		}
+		delay, err := time.ParseDuration(delayValue)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", faultDelayAnnotation, err)
+		}
+		if delay <= 0 {
+			return nil, fmt.Errorf("annotation %s: delay %q must be positive", faultDelayAnnotation, delayValue)
+		}
+		fault.Delay = &faultcommonv3.FaultDelay{
+			FaultDelaySecifier: &faultcommonv3.FaultDelay_FixedDelay{FixedDelay: durationpb.New(delay)},
+			Percentage:         percentage,
+		}
+	}
+
+	if fault.Abort == nil && fault.Delay == nil {
+		return nil, nil
+	}
+	return anypb.New(fault)
+}
+
+// parseFaultPercentage parses a percentage between 0 and 100, e.g. "12.5", with a precision
+// of up to four decimal places.
+func parseFaultPercentage(value string) (*typev3.FractionalPercent, error) {
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(percent) || percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("invalid percentage %q", value)
+	}
+	return &typev3.FractionalPercent{
+		Numerator:   uint32(math.Round(percent * 10000)),
+		Denominator: typev3.FractionalPercent_MILLION,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	faultcommonv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateHTTPRouteFault(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	faulty := testHTTPRoute("faulty", "gw", testBackendRef("backend", 80))
+	faulty.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/faulty")}
+	faulty.Annotations = map[string]string{faultAbortPercentAnnotation: "10", faultAbortStatusAnnotation: "503"}
+	healthy := testHTTPRoute("healthy", "gw", testBackendRef("backend", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), faulty, healthy)
+	resources := translateGateway(t, tl, gw)
+
+	// Faults are injected before the router forwards the request.
+	filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80")))
+	if i := slices.Index(filters, wellknown.Fault); i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Errorf("HTTP filters = %v, want %s before the router", filters, wellknown.Fault)
+	}
+
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	perRouteConfig, ok := findRoute(t, vh, "default-faulty-rule0-match0").TypedPerFilterConfig[wellknown.Fault]
+	if !ok {
+		t.Fatalf("route default-faulty-rule0-match0 has no %s override", wellknown.Fault)
+	}
+	fault := &faultv3.HTTPFault{}
+	if err := perRouteConfig.UnmarshalTo(fault); err != nil {
+		t.Fatal(err)
+	}
+	want := &faultv3.HTTPFault{Abort: &faultv3.FaultAbort{
+		ErrorType:  &faultv3.FaultAbort_HttpStatus{HttpStatus: 503},
+		Percentage: &typev3.FractionalPercent{Numerator: 100000, Denominator: typev3.FractionalPercent_MILLION},
+	}}
+	if !proto.Equal(fault, want) {
+		t.Errorf("fault = %v, want %v", fault, want)
+	}
+	if _, ok := findRoute(t, vh, "default-healthy-rule0-match0").TypedPerFilterConfig[wellknown.Fault]; ok {
+		t.Errorf("route default-healthy-rule0-match0 has a %s override, want none", wellknown.Fault)
+	}
+}
+
+func TestParseFaultAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        *faultv3.HTTPFault
+		wantErr     bool
+	}{
+		{name: "no faults"},
+		{
+			name:        "abort with default status",
+			annotations: map[string]string{faultAbortPercentAnnotation: "12.5"},
+			want: &faultv3.HTTPFault{Abort: &faultv3.FaultAbort{
+				ErrorType:  &faultv3.FaultAbort_HttpStatus{HttpStatus: 503},
+				Percentage: &typev3.FractionalPercent{Numerator: 125000, Denominator: typev3.FractionalPercent_MILLION},
+			}},
+		},
+		{
+			name:        "delay",
+			annotations: map[string]string{faultDelayPercentAnnotation: "100", faultDelayAnnotation: "2s"},
+			want: &faultv3.HTTPFault{Delay: &faultcommonv3.FaultDelay{
+				FaultDelaySecifier: &faultcommonv3.FaultDelay_FixedDelay{FixedDelay: durationpb.New(2 * time.Second)},
+				Percentage:         &typev3.FractionalPercent{Numerator: 1000000, Denominator: typev3.FractionalPercent_MILLION},
+			}},
+		},
+		{name: "percentage above 100", annotations: map[string]string{faultAbortPercentAnnotation: "101"}, wantErr: true},
+		{name: "invalid status", annotations: map[string]string{faultAbortPercentAnnotation: "10", faultAbortStatusAnnotation: "99"}, wantErr: true},
+		{name: "delay without percentage", annotations: map[string]string{faultDelayAnnotation: "2s"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			perRouteConfig, err := parseFaultAnnotations(tc.annotations)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseFaultAnnotations() error = %v, want error %v", err, tc.wantErr)
+			}
+			if tc.want == nil {
+				if perRouteConfig != nil {
+					t.Errorf("parseFaultAnnotations() = %v, want nil", perRouteConfig)
+				}
+				return
+			}
+			fault := &faultv3.HTTPFault{}
+			if err := perRouteConfig.UnmarshalTo(fault); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(fault, tc.want) {
+				t.Errorf("fault = %v, want %v", fault, tc.want)
+			}
+		})
+	}
+}
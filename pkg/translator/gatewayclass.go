@@ -0,0 +1,54 @@
+package translator
+
+import (
+	"fmt"
+	"time"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The keys of the ConfigMap referenced by the parametersRef of a GatewayClass. They set
+// the defaults of the Gateways of the class, which take precedence over the Options.
+const (
+	classConnectTimeoutKey = "connect-timeout"
+	classLBPolicyKey       = "lb-policy"
+)
+
+// forGatewayClass returns a Translator for the Gateways of gatewayClass. Its options are
+// those of t with the defaults of the ConfigMap referenced by the parametersRef of the
+// class applied, or t itself if the class has no parametersRef.
+func (t *Translator) forGatewayClass(gatewayClass *gatewayv1.GatewayClass) (*Translator, error) {
+	ref := gatewayClass.Spec.ParametersRef
+	if ref == nil {
+		return t, nil
+	}
+	if ref.Group != "" || ref.Kind != "ConfigMap" {
+		return nil, fmt.Errorf("parametersRef of GatewayClass %s must reference a ConfigMap, not %s.%s", gatewayClass.Name, ref.Kind, ref.Group)
+	}
+	if ref.Namespace == nil {
+		return nil, fmt.Errorf("parametersRef of GatewayClass %s must set the namespace of the ConfigMap", gatewayClass.Name)
+	}
+	configMap, err := t.configMapLister.ConfigMaps(string(*ref.Namespace)).Get(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not find parameters ConfigMap %s/%s of GatewayClass %s: %w", *ref.Namespace, ref.Name, gatewayClass.Name, err)
+	}
+
+	options := t.options
+	if value, ok := configMap.Data[classConnectTimeoutKey]; ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%s of parameters ConfigMap %s/%s must be a positive duration, got %q", classConnectTimeoutKey, configMap.Namespace, configMap.Name, value)
+		}
+		options.ConnectTimeout = timeout
+	}
+	if value, ok := configMap.Data[classLBPolicyKey]; ok {
+		options.DefaultLBPolicy = value
+	}
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters ConfigMap %s/%s of GatewayClass %s: %w", configMap.Namespace, configMap.Name, gatewayClass.Name, err)
+	}
+
+	classTranslator := *t
+	classTranslator.options = options
+	return &classTranslator, nil
+}
@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// testParametersClass returns testGatewayClass with a parametersRef to the ConfigMap.
+func testParametersClass(ref gatewayv1.ParametersReference) *gatewayv1.GatewayClass {
+	gatewayClass := testGatewayClass()
+	gatewayClass.Spec.ParametersRef = &ref
+	return gatewayClass
+}
+
+// testParametersConfigMap returns the parameters ConfigMap of testParametersRef.
+func testParametersConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway-params", Namespace: "gateway-system"},
+		Data:       data,
+	}
+}
+
+// testParametersRef references the parameters ConfigMap returned by testParametersConfigMap.
+var testParametersRef = gatewayv1.ParametersReference{Kind: "ConfigMap", Name: "gateway-params", Namespace: ptr(gatewayv1.Namespace("gateway-system"))}
+
+func TestTranslateGatewayClassParameters(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	configMap := testParametersConfigMap(map[string]string{classConnectTimeoutKey: "2s", classLBPolicyKey: "RANDOM"})
+	tl := newTestTranslator(t, Options{ConnectTimeout: 10 * time.Second}, testParametersClass(testParametersRef), configMap, gw,
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+
+	resources, err := tl.TranslateGatewaysToXDS(context.Background(), []*gatewayv1.Gateway{gw})
+	if err != nil {
+		t.Fatalf("TranslateGatewaysToXDS() error = %v", err)
+	}
+	// The defaults of the class take precedence over the options.
+	cluster := findCluster(t, resources, clusterName("backend", 80))
+	if got := cluster.GetConnectTimeout().AsDuration(); got != 2*time.Second {
+		t.Errorf("connect timeout = %s, want 2s", got)
+	}
+	if cluster.LbPolicy != clusterv3.Cluster_RANDOM {
+		t.Errorf("load balancing policy = %v, want RANDOM", cluster.LbPolicy)
+	}
+}
+
+func TestForGatewayClassInvalidParameters(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ref       gatewayv1.ParametersReference
+		configMap *corev1.ConfigMap
+	}{
+		{name: "not a ConfigMap", ref: gatewayv1.ParametersReference{Group: "example.com", Kind: "GatewayConfig", Name: "gateway-params"}},
+		{name: "no namespace", ref: gatewayv1.ParametersReference{Kind: "ConfigMap", Name: "gateway-params"}},
+		{name: "missing ConfigMap", ref: testParametersRef},
+		{name: "invalid connect timeout", ref: testParametersRef, configMap: testParametersConfigMap(map[string]string{classConnectTimeoutKey: "0s"})},
+		{name: "invalid LB policy", ref: testParametersRef, configMap: testParametersConfigMap(map[string]string{classLBPolicyKey: "FASTEST"})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tl := newTestTranslator(t, Options{})
+			if tc.configMap != nil {
+				tl = newTestTranslator(t, Options{}, tc.configMap)
+			}
+			if classTranslator, err := tl.forGatewayClass(testParametersClass(tc.ref)); err == nil {
+				t.Errorf("forGatewayClass() = %v, want an error", classTranslator.options)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewaylistersv1beta1 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
+)
+
+// translateGRPCRoute translates a full GRPCRoute into a slice of Envoy Routes.
+// It mirrors translateHTTPRouteToEnvoyRoutes, deriving the route matches from the
+// gRPC method and header matches of each rule.
+func translateGRPCRoute(
+	grpcRoute *gatewayv1.GRPCRoute,
+	serviceLister corev1listers.ServiceLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+) ([]*routev3.Route, []gatewayv1.BackendRef, metav1.Condition, error) {
+
+	var envoyRoutes []*routev3.Route
+	var allValidBackendRefs []gatewayv1.BackendRef
+	overallCondition := createSuccessCondition(grpcRoute.Generation)
+	// regexErrs are the errors of the matches skipped for a regular expression that Envoy
+	// would reject. Unlike other errors, they are also returned, as the rest of the route
+	// is still translated.
+	var regexErrs []error
+
+	headerMatchIgnoreCase, err := parseHeaderMatchIgnoreCase(grpcRoute.Annotations)
+	if err != nil {
+		msg := fmt.Sprintf("GRPCRoute %s/%s: %v", grpcRoute.Namespace, grpcRoute.Name, err)
+		return nil, nil, createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, grpcRoute.Generation), nil
+	}
+
+	for ruleIndex, rule := range grpcRoute.Spec.Rules {
+		var backendRefs []gatewayv1.BackendRef
+		for _, grpcBackendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, grpcBackendRef.BackendRef)
+		}
+
+		buildRoutesForRule := func(match gatewayv1.GRPCRouteMatch, matchIndex int) {
+			routeMatch, err := translateGRPCRouteMatch(match)
+			if err != nil {
+				msg := fmt.Sprintf("GRPCRoute %s/%s rule %d match %d: %v", grpcRoute.Namespace, grpcRoute.Name, ruleIndex, matchIndex, err)
+				overallCondition = createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, grpcRoute.Generation)
+				if errors.Is(err, errInvalidRegex) {
+					regexErrs = append(regexErrs, errors.New(msg))
+				}
+				return
+			}
+			if headerMatchIgnoreCase {
+				setHeaderMatchIgnoreCase(routeMatch)
+			}
+
+			envoyRoute := &routev3.Route{
+				Name:  fmt.Sprintf("%s-%s-rule%d-match%d", grpcRoute.Namespace, grpcRoute.Name, ruleIndex, matchIndex),
+				Match: routeMatch,
+			}
+
+			routeAction, validBackends, err := buildHTTPRouteAction(
+				"GRPCRoute",
+				grpcRoute.Namespace,
+				backendRefs,
+				// gRPC backends must be reached over HTTP/2.
+				clusterVariant{http2: true},
+				serviceLister,
+				referenceGrantLister,
+			)
+			var controllerErr *ControllerError
+			if errors.As(err, &controllerErr) {
+				overallCondition = createFailureCondition(gatewayv1.RouteConditionReason(controllerErr.Reason), controllerErr.Message, grpcRoute.Generation)
+			}
+			if errors.Is(err, errAllBackendsZeroWeight) {
+				allValidBackendRefs = append(allValidBackendRefs, validBackends...)
+				envoyRoute.Action = &routev3.Route_DirectResponse{
+					DirectResponse: &routev3.DirectResponseAction{Status: 503},
+				}
+			} else if routeAction == nil {
+				envoyRoute.Action = &routev3.Route_DirectResponse{
+					DirectResponse: &routev3.DirectResponseAction{Status: 500},
+				}
+			} else {
+				allValidBackendRefs = append(allValidBackendRefs, validBackends...)
+				envoyRoute.Action = &routev3.Route_Route{
+					Route: routeAction,
+				}
+			}
+			envoyRoutes = append(envoyRoutes, envoyRoute)
+		}
+
+		if len(rule.Matches) == 0 {
+			buildRoutesForRule(gatewayv1.GRPCRouteMatch{}, 0)
+		} else {
+			for matchIndex, match := range rule.Matches {
+				buildRoutesForRule(match, matchIndex)
+			}
+		}
+	}
+	return envoyRoutes, allValidBackendRefs, overallCondition, errors.Join(regexErrs...)
+}
+
+// translateGRPCRouteMatch translates a Gateway API GRPCRouteMatch into an Envoy RouteMatch.
+// gRPC requests are HTTP/2 requests whose path is "/<service>/<method>", so method
+// matches are expressed as path matches.
+func translateGRPCRouteMatch(match gatewayv1.GRPCRouteMatch) (*routev3.RouteMatch, error) {
+	routeMatch := &routev3.RouteMatch{}
+
+	if match.Method != nil {
+		if err := translateGRPCMethodMatch(routeMatch, match.Method); err != nil {
+			return nil, err
+		}
+	} else {
+		// A nil method match matches all gRPC services and methods.
+		routeMatch.PathSpecifier = &routev3.RouteMatch_Prefix{Prefix: "/"}
+	}
+
+	// Translate Header Matches
+	for _, headerMatch := range match.Headers {
+		matchType := gatewayv1.HeaderMatchExact
+		if headerMatch.Type != nil {
+			matchType = gatewayv1.HeaderMatchType(*headerMatch.Type)
+		}
+		headerMatcher, err := buildHeaderMatcher(string(headerMatch.Name), headerMatch.Value, matchType)
+		if err != nil {
+			return nil, err
+		}
+		routeMatch.Headers = append(routeMatch.Headers, headerMatcher)
+	}
+
+	return routeMatch, nil
+}
+
+// grpcNameRegex matches any gRPC service or method name, i.e. a path segment.
+const grpcNameRegex = "[^/]+"
+
+// translateGRPCMethodMatch sets the path match of the route match for a gRPC method match.
+// An exact match of both the service and the method is a path match, of only the service a
+// prefix match of the service's methods, and of only the method a regular expression match
+// of the method in any service. Regular expression matches of a missing service or method
+// match any name.
+func translateGRPCMethodMatch(routeMatch *routev3.RouteMatch, method *gatewayv1.GRPCMethodMatch) error {
+	if method.Service == nil && method.Method == nil {
+		return errors.New("gRPC method match must specify a service or a method")
+	}
+	matchType := gatewayv1.GRPCMethodMatchExact
+	if method.Type != nil {
+		matchType = *method.Type
+	}
+
+	var regex string
+	switch matchType {
+	case gatewayv1.GRPCMethodMatchExact:
+		switch {
+		case method.Service != nil && method.Method != nil:
+			routeMatch.PathSpecifier = &routev3.RouteMatch_Path{Path: fmt.Sprintf("/%s/%s", *method.Service, *method.Method)}
+			return nil
+		case method.Service != nil:
+			routeMatch.PathSpecifier = &routev3.RouteMatch_Prefix{Prefix: fmt.Sprintf("/%s/", *method.Service)}
+			return nil
+		default:
+			regex = fmt.Sprintf("/%s/%s", grpcNameRegex, regexp.QuoteMeta(*method.Method))
+		}
+	case gatewayv1.GRPCMethodMatchRegularExpression:
+		service, methodName := grpcNameRegex, grpcNameRegex
+		if method.Service != nil {
+			service = *method.Service
+		}
+		if method.Method != nil {
+			methodName = *method.Method
+		}
+		regex = fmt.Sprintf("/(%s)/(%s)", service, methodName)
+	default:
+		return fmt.Errorf("unsupported gRPC method match type: %s", matchType)
+	}
+
+	regexMatcher, err := newRegexMatcher(regex)
+	if err != nil {
+		return fmt.Errorf("gRPC method match: %w", err)
+	}
+	routeMatch.PathSpecifier = &routev3.RouteMatch_SafeRegex{SafeRegex: regexMatcher}
+	return nil
+}
@@ -0,0 +1,181 @@
+package translator
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	httpv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func testGRPCRoute(name, gateway string, match gatewayv1.GRPCRouteMatch, backendRef gatewayv1.BackendRef) *gatewayv1.GRPCRoute {
+	return &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Generation: 1},
+		Spec: gatewayv1.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}},
+			},
+			Rules: []gatewayv1.GRPCRouteRule{{
+				Matches:     []gatewayv1.GRPCRouteMatch{match},
+				BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: backendRef}},
+			}},
+		},
+	}
+}
+
+// grpcRegexRouteMatch returns a route match of the gRPC paths matching the RE2 regex.
+func grpcRegexRouteMatch(regex string) *routev3.RouteMatch {
+	return &routev3.RouteMatch{
+		PathSpecifier: &routev3.RouteMatch_SafeRegex{SafeRegex: &matcherv3.RegexMatcher{
+			EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
+			Regex:      regex,
+		}},
+	}
+}
+
+func TestTranslateGRPCRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		match gatewayv1.GRPCRouteMatch
+		want  *routev3.RouteMatch
+	}{
+		{
+			name: "exact service and method",
+			match: gatewayv1.GRPCRouteMatch{
+				Method: &gatewayv1.GRPCMethodMatch{Service: ptr("helloworld.Greeter"), Method: ptr("SayHello")},
+			},
+			want: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Path{Path: "/helloworld.Greeter/SayHello"},
+			},
+		},
+		{
+			// A service-only match covers all methods of the service.
+			name: "exact service",
+			match: gatewayv1.GRPCRouteMatch{
+				Method: &gatewayv1.GRPCMethodMatch{Service: ptr("helloworld.Greeter")},
+			},
+			want: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/helloworld.Greeter/"},
+			},
+		},
+		{
+			name: "exact method",
+			match: gatewayv1.GRPCRouteMatch{
+				Method: &gatewayv1.GRPCMethodMatch{Method: ptr("SayHello")},
+			},
+			want: grpcRegexRouteMatch("/[^/]+/SayHello"),
+		},
+		{
+			name: "regular expression service and method",
+			match: gatewayv1.GRPCRouteMatch{
+				Method: &gatewayv1.GRPCMethodMatch{
+					Type:    ptr(gatewayv1.GRPCMethodMatchRegularExpression),
+					Service: ptr(`helloworld\.Greeter(V2)?`),
+					Method:  ptr("Say.*"),
+				},
+			},
+			want: grpcRegexRouteMatch(`/(helloworld\.Greeter(V2)?)/(Say.*)`),
+		},
+		{
+			name: "regular expression service",
+			match: gatewayv1.GRPCRouteMatch{
+				Method: &gatewayv1.GRPCMethodMatch{Type: ptr(gatewayv1.GRPCMethodMatchRegularExpression), Service: ptr(`helloworld\..*`)},
+			},
+			want: grpcRegexRouteMatch(`/(helloworld\..*)/([^/]+)`),
+		},
+		{
+			name: "regular expression method",
+			match: gatewayv1.GRPCRouteMatch{
+				Method: &gatewayv1.GRPCMethodMatch{Type: ptr(gatewayv1.GRPCMethodMatchRegularExpression), Method: ptr("Get.*")},
+			},
+			want: grpcRegexRouteMatch(`/([^/]+)/(Get.*)`),
+		},
+		{
+			name: "header",
+			match: gatewayv1.GRPCRouteMatch{
+				Headers: []gatewayv1.GRPCHeaderMatch{{Name: "x-env", Value: "canary"}},
+			},
+			want: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+				Headers: []*routev3.HeaderMatcher{{
+					Name: "x-env",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+						StringMatch: &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Exact{Exact: "canary"}},
+					},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			route := testGRPCRoute("grpc", "gw", tt.match, testBackendRef("backend", 80))
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+			envoyRoute := findRoute(t, vh, "default-grpc-rule0-match0")
+			if !proto.Equal(envoyRoute.Match, tt.want) {
+				t.Errorf("route match = %v, want %v", envoyRoute.Match, tt.want)
+			}
+			if got, want := envoyRoute.GetRoute().GetCluster(), clusterName("backend", 80)+"_http2"; got != want {
+				t.Errorf("route cluster = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestTranslateGRPCRouteSharedBackend checks that a GRPCRoute gets an HTTP/2 cluster of its
+// own, rather than switching the cluster of an HTTPRoute to the same Service to HTTP/2.
+func TestTranslateGRPCRouteSharedBackend(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	grpcRoute := testGRPCRoute("grpc", "gw", gatewayv1.GRPCRouteMatch{}, testBackendRef("backend", 80))
+	grpcRoute.Spec.Hostnames = []gatewayv1.Hostname{"grpc.example.com"}
+	httpRoute := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	httpRoute.Spec.Hostnames = []gatewayv1.Hostname{"www.example.com"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), grpcRoute, httpRoute)
+	resources := translateGateway(t, tl, gw)
+
+	for _, tt := range []struct {
+		cluster   string
+		wantHTTP2 bool
+	}{
+		{cluster: clusterName("backend", 80)},
+		{cluster: clusterName("backend", 80) + "_http2", wantHTTP2: true},
+	} {
+		cluster := findCluster(t, resources, tt.cluster)
+		options := &httpv3.HttpProtocolOptions{}
+		if protocolOptions, ok := cluster.TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"]; ok {
+			if err := protocolOptions.UnmarshalTo(options); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := options.GetExplicitHttpConfig().GetHttp2ProtocolOptions() != nil; got != tt.wantHTTP2 {
+			t.Errorf("cluster %s speaks HTTP/2 = %t, want %t", tt.cluster, got, tt.wantHTTP2)
+		}
+	}
+}
+
+func TestTranslateGRPCRouteBackreferenceRegex(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testGRPCRoute("grpc", "gw", gatewayv1.GRPCRouteMatch{
+		Method: &gatewayv1.GRPCMethodMatch{Type: ptr(gatewayv1.GRPCMethodMatchRegularExpression), Service: ptr(`(echo)\.\1`)},
+	}, testBackendRef("backend", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources, err := tl.TranslateGatewayToXDS(context.Background(), gw)
+
+	if err == nil || !strings.Contains(err.Error(), "GRPCRoute default/grpc") || !strings.Contains(err.Error(), `(echo)\\.\\1`) {
+		t.Errorf("TranslateGatewayToXDS() error = %v, want the invalid regex of GRPCRoute default/grpc", err)
+	}
+	for _, vh := range findRouteConfiguration(t, resources, "route-80").VirtualHosts {
+		if slices.Contains(routeNames(vh), "default-grpc-rule0-match0") {
+			t.Errorf("virtual host %s has route default-grpc-rule0-match0, want it skipped", vh.Name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	grpcwebv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_web/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// grpcWebAnnotation is the annotation of an HTTPRoute to a gRPC backend that lets browsers
+// call the backend with gRPC-Web.
+const grpcWebAnnotation = "gateway.xds/grpc-web"
+
+// hasGRPCWeb returns whether the HTTPRoute is annotated for gRPC-Web.
+func hasGRPCWeb(httpRoute *gatewayv1.HTTPRoute) bool {
+	enabled, err := strconv.ParseBool(httpRoute.Annotations[grpcWebAnnotation])
+	return err == nil && enabled
+}
+
+// parseGRPCWebAnnotations returns the per-route config that enables the gRPC-Web filter for
+// the routes of an HTTPRoute, or nil if the routes don't accept gRPC-Web.
+func parseGRPCWebAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	value, ok := annotations[grpcWebAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", grpcWebAnnotation, err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+	grpcWebAny, err := anypb.New(&grpcwebv3.GrpcWeb{})
+	if err != nil {
+		return nil, err
+	}
+	return anypb.New(&routev3.FilterConfig{Config: grpcWebAny})
+}
+
+// buildGRPCWebFilter builds the gRPC-Web filter of an HTTP connection manager. It is disabled
+// by default, so that it only translates the requests of the routes that enable it.
+func buildGRPCWebFilter() (*hcm.HttpFilter, error) {
+	grpcWebAny, err := anypb.New(&grpcwebv3.GrpcWeb{})
+	if err != nil {
+		return nil, err
+	}
+	return &hcm.HttpFilter{
+		Name:     wellknown.GRPCWeb,
+		Disabled: true,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: grpcWebAny,
+		},
+	}, nil
+}
@@ -0,0 +1,69 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateHTTPRouteGRPCWeb(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	grpcWeb := testHTTPRoute("grpc-web", "gw", testBackendRef("echo", 80))
+	grpcWeb.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/echo.Echo")}
+	grpcWeb.Annotations = map[string]string{grpcWebAnnotation: "true"}
+	plain := testHTTPRoute("plain", "gw", testBackendRef("backend", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("echo", 80), testService("backend", 80), grpcWeb, plain)
+	resources := translateGateway(t, tl, gw)
+
+	// Browsers reach the gRPC backend through the CORS and gRPC-Web filters, ahead of the router.
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	filters := httpFilterNames(manager)
+	router := slices.Index(filters, wellknown.Router)
+	for _, filter := range []string{wellknown.CORS, wellknown.GRPCWeb} {
+		if i := slices.Index(filters, filter); i < 0 || i > router {
+			t.Errorf("HTTP filters = %v, want %s before the router", filters, filter)
+		}
+	}
+	// The filter is only enabled for the routes annotated for gRPC-Web.
+	if grpcWebFilter := manager.HttpFilters[slices.Index(filters, wellknown.GRPCWeb)]; !grpcWebFilter.Disabled {
+		t.Errorf("gRPC-Web filter is enabled for all routes, want it disabled by default")
+	}
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	grpcWebRoute := findRoute(t, vh, "default-grpc-web-rule0-match0")
+	perRouteConfig, ok := grpcWebRoute.TypedPerFilterConfig[wellknown.GRPCWeb]
+	if !ok {
+		t.Fatalf("route %s has no %s override", grpcWebRoute.Name, wellknown.GRPCWeb)
+	}
+	filterConfig := &routev3.FilterConfig{}
+	if err := perRouteConfig.UnmarshalTo(filterConfig); err != nil {
+		t.Fatal(err)
+	}
+	if filterConfig.Config == nil || filterConfig.Disabled {
+		t.Errorf("route %s gRPC-Web override = %v, want the filter enabled", grpcWebRoute.Name, filterConfig)
+	}
+	if _, ok := findRoute(t, vh, "default-plain-rule0-match0").TypedPerFilterConfig[wellknown.GRPCWeb]; ok {
+		t.Errorf("route default-plain-rule0-match0 has a %s override, want none", wellknown.GRPCWeb)
+	}
+
+	// The gRPC backend is reached over HTTP/2, through a cluster of its own.
+	http2ClusterName := clusterName("echo", 80) + "_http2"
+	if got := grpcWebRoute.GetRoute().GetCluster(); got != http2ClusterName {
+		t.Errorf("route %s cluster = %s, want %s", grpcWebRoute.Name, got, http2ClusterName)
+	}
+	if _, ok := findCluster(t, resources, http2ClusterName).TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"]; !ok {
+		t.Errorf("cluster %s does not enable HTTP/2", http2ClusterName)
+	}
+}
+
+func TestTranslateHTTPRouteWithoutGRPCWeb(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	if filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80"))); slices.Contains(filters, wellknown.GRPCWeb) {
+		t.Errorf("HTTP filters = %v, want no %s", filters, wellknown.GRPCWeb)
+	}
+}
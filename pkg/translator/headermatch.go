@@ -0,0 +1,37 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+)
+
+// headerMatchIgnoreCaseAnnotation is the annotation of an HTTPRoute or GRPCRoute that makes
+// the exact matches of its header values case-insensitive.
+const headerMatchIgnoreCaseAnnotation = "gateway.xds/header-match-ignore-case"
+
+// parseHeaderMatchIgnoreCase reports whether the annotations make the exact header value
+// matches case-insensitive. They are case-sensitive by default.
+func parseHeaderMatchIgnoreCase(annotations map[string]string) (bool, error) {
+	value, ok := annotations[headerMatchIgnoreCaseAnnotation]
+	if !ok {
+		return false, nil
+	}
+	ignoreCase, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("annotation %s: %w", headerMatchIgnoreCaseAnnotation, err)
+	}
+	return ignoreCase, nil
+}
+
+// setHeaderMatchIgnoreCase makes the exact header value matches of the route match
+// case-insensitive. The method match is left as is, as methods are case-sensitive.
+func setHeaderMatchIgnoreCase(match *routev3.RouteMatch) {
+	for _, header := range match.GetHeaders() {
+		if header.GetName() == ":method" || header.GetStringMatch().GetExact() == "" {
+			continue
+		}
+		header.GetStringMatch().IgnoreCase = true
+	}
+}
@@ -0,0 +1,65 @@
+package translator
+
+import (
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// translateHeaderMatchRoute translates an HTTPRoute with the annotations that matches the
+// GET requests with the x-env header, and returns the header matchers of its route.
+func translateHeaderMatchRoute(t *testing.T, annotations map[string]string) map[string]*routev3.HeaderMatcher {
+	t.Helper()
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Annotations = annotations
+	route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{{
+		Method:  ptr(gatewayv1.HTTPMethodGet),
+		Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-env", Value: "Canary"}},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+
+	headers := make(map[string]*routev3.HeaderMatcher)
+	for _, header := range envoyRoute.Match.Headers {
+		headers[header.Name] = header
+	}
+	return headers
+}
+
+func TestTranslateHeaderMatchIgnoreCase(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "default"},
+		{name: "annotated", annotations: map[string]string{headerMatchIgnoreCaseAnnotation: "true"}, want: true},
+		{name: "annotated false", annotations: map[string]string{headerMatchIgnoreCaseAnnotation: "false"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := translateHeaderMatchRoute(t, tc.annotations)
+			if got := headers["x-env"].GetStringMatch().GetIgnoreCase(); got != tc.want {
+				t.Errorf("x-env ignore_case = %v, want %v", got, tc.want)
+			}
+			if got := headers["x-env"].GetStringMatch().GetExact(); got != "Canary" {
+				t.Errorf("x-env exact match = %q, want Canary", got)
+			}
+			// Methods are case-sensitive.
+			if headers[":method"] == nil {
+				t.Fatal("route has no :method header matcher")
+			}
+			if headers[":method"].GetStringMatch().GetIgnoreCase() {
+				t.Error(":method ignore_case = true, want false")
+			}
+		})
+	}
+}
+
+func TestParseHeaderMatchIgnoreCaseInvalid(t *testing.T) {
+	if ignoreCase, err := parseHeaderMatchIgnoreCase(map[string]string{headerMatchIgnoreCaseAnnotation: "sometimes"}); err == nil {
+		t.Errorf("parseHeaderMatchIgnoreCase() = %v, want an error", ignoreCase)
+	}
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	headertometadatav3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/header_to_metadata/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const headerToMetadataFilterName = "envoy.filters.http.header_to_metadata"
+
+// headerToMetadataAnnotation is the annotation of an HTTPRoute that copies request headers
+// into the dynamic metadata of the requests of its routes. Rules are separated by commas
+// and have the form <header>:<metadata namespace>:<key>[:<type>], where the type is STRING,
+// NUMBER or PROTOBUF_VALUE and defaults to STRING.
+const headerToMetadataAnnotation = "gateway.xds/header-to-metadata"
+
+// parseHeaderToMetadataAnnotations reads the header-to-metadata rules from the annotations of
+// an HTTPRoute and returns the per-route config of the header-to-metadata filter, or nil if
+// the routes copy no headers.
+func parseHeaderToMetadataAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	value, ok := annotations[headerToMetadataAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	config := &headertometadatav3.Config{}
+	for _, rule := range splitList(value) {
+		configRule, err := parseHeaderToMetadataRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", headerToMetadataAnnotation, err)
+		}
+		config.RequestRules = append(config.RequestRules, configRule)
+	}
+	if len(config.RequestRules) == 0 {
+		return nil, fmt.Errorf("annotation %s: no rules", headerToMetadataAnnotation)
+	}
+	return anypb.New(config)
+}
+
+func parseHeaderToMetadataRule(rule string) (*headertometadatav3.Config_Rule, error) {
+	parts := strings.Split(rule, ":")
+	if len(parts) < 3 || len(parts) > 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("rule %q must have the form <header>:<metadata namespace>:<key>[:<type>]", rule)
+	}
+	valueType := headertometadatav3.Config_STRING
+	if len(parts) == 4 {
+		parsed, ok := headertometadatav3.Config_ValueType_value[parts[3]]
+		if !ok {
+			return nil, fmt.Errorf("rule %q: unsupported type %q, must be STRING, NUMBER or PROTOBUF_VALUE", rule, parts[3])
+		}
+		valueType = headertometadatav3.Config_ValueType(parsed)
+	}
+	return &headertometadatav3.Config_Rule{
+		Header: parts[0],
+		OnHeaderPresent: &headertometadatav3.Config_KeyValuePair{
+			MetadataNamespace: parts[1],
+			Key:               parts[2],
+			Type:              valueType,
+		},
+	}, nil
+}
+
+// buildHeaderToMetadataFilter builds the header-to-metadata filter of an HTTP connection
+// manager. It has no rules of its own: the routes that copy headers configure them.
+func buildHeaderToMetadataFilter() (*hcm.HttpFilter, error) {
+	headerToMetadataAny, err := anypb.New(&headertometadatav3.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return &hcm.HttpFilter{
+		Name: headerToMetadataFilterName,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: headerToMetadataAny,
+		},
+	}, nil
+}
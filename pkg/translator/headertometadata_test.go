@@ -0,0 +1,67 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	headertometadatav3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/header_to_metadata/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTranslateHeaderToMetadata(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Annotations = map[string]string{headerToMetadataAnnotation: "x-tenant:envoy.lb:tenant:STRING"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80")))
+	i := slices.Index(filters, headerToMetadataFilterName)
+	if i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Fatalf("HTTP filters = %v, want %s before the router", filters, headerToMetadataFilterName)
+	}
+
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	perRouteConfig, ok := findRoute(t, vh, "default-web-rule0-match0").TypedPerFilterConfig[headerToMetadataFilterName]
+	if !ok {
+		t.Fatalf("route default-web-rule0-match0 has no %s config", headerToMetadataFilterName)
+	}
+	config := &headertometadatav3.Config{}
+	if err := perRouteConfig.UnmarshalTo(config); err != nil {
+		t.Fatal(err)
+	}
+	want := &headertometadatav3.Config{
+		RequestRules: []*headertometadatav3.Config_Rule{{
+			Header: "x-tenant",
+			OnHeaderPresent: &headertometadatav3.Config_KeyValuePair{
+				MetadataNamespace: "envoy.lb",
+				Key:               "tenant",
+				Type:              headertometadatav3.Config_STRING,
+			},
+		}},
+	}
+	if !proto.Equal(config, want) {
+		t.Errorf("header-to-metadata config = %v, want %v", config, want)
+	}
+}
+
+func TestTranslateWithoutHeaderToMetadata(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80")))
+	if slices.Contains(filters, headerToMetadataFilterName) {
+		t.Errorf("HTTP filters = %v, want no %s", filters, headerToMetadataFilterName)
+	}
+}
+
+func TestParseHeaderToMetadataAnnotationsInvalid(t *testing.T) {
+	for _, value := range []string{"", "x-tenant", "x-tenant:envoy.lb", "x-tenant::tenant", "x-tenant:envoy.lb:tenant:BOOL", "a:b:c:STRING:extra"} {
+		if config, err := parseHeaderToMetadataAnnotations(map[string]string{headerToMetadataAnnotation: value}); err == nil {
+			t.Errorf("parseHeaderToMetadataAnnotations() of %q = %v, want an error", value, config)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The annotations of a Service that configure active health checks of its clusters.
+const (
+	// healthCheckAnnotation enables active health checks, its value is the protocol
+	// of the checks, healthCheckTypeHTTP or healthCheckTypeGRPC.
+	healthCheckAnnotation                   = "gateway.xds/health-check"
+	healthCheckPathAnnotation               = "gateway.xds/health-check-path"
+	healthCheckExpectedStatusAnnotation     = "gateway.xds/health-check-expected-status"
+	healthCheckGRPCServiceAnnotation        = "gateway.xds/health-check-grpc-service"
+	healthCheckIntervalAnnotation           = "gateway.xds/health-check-interval"
+	healthCheckTimeoutAnnotation            = "gateway.xds/health-check-timeout"
+	healthCheckHealthyThresholdAnnotation   = "gateway.xds/health-check-healthy-threshold"
+	healthCheckUnhealthyThresholdAnnotation = "gateway.xds/health-check-unhealthy-threshold"
+)
+
+const (
+	healthCheckTypeHTTP = "http"
+	healthCheckTypeGRPC = "grpc"
+)
+
+// The active health check defaults.
+const (
+	defaultHealthCheckPath               = "/"
+	defaultHealthCheckInterval           = 10 * time.Second
+	defaultHealthCheckTimeout            = time.Second
+	defaultHealthCheckHealthyThreshold   = 2
+	defaultHealthCheckUnhealthyThreshold = 3
+)
+
+// buildHealthChecks builds the active health checks of a cluster from the annotations of
+// the Service. It returns nil if the Service has no health check annotation.
+func buildHealthChecks(service *corev1.Service) ([]*corev3.HealthCheck, error) {
+	checkType, ok := service.Annotations[healthCheckAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	healthCheck := &corev3.HealthCheck{
+		Interval:           durationpb.New(defaultHealthCheckInterval),
+		Timeout:            durationpb.New(defaultHealthCheckTimeout),
+		HealthyThreshold:   wrapperspb.UInt32(defaultHealthCheckHealthyThreshold),
+		UnhealthyThreshold: wrapperspb.UInt32(defaultHealthCheckUnhealthyThreshold),
+	}
+	switch checkType {
+	case healthCheckTypeHTTP:
+		httpHealthCheck := &corev3.HealthCheck_HttpHealthCheck{
+			Path: defaultHealthCheckPath,
+		}
+		if path, ok := service.Annotations[healthCheckPathAnnotation]; ok {
+			if !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("annotation %s of service %s/%s: path %q must start with /", healthCheckPathAnnotation, service.Namespace, service.Name, path)
+			}
+			httpHealthCheck.Path = path
+		}
+		if expectedStatus, ok := service.Annotations[healthCheckExpectedStatusAnnotation]; ok {
+			statuses, err := parseExpectedStatuses(expectedStatus)
+			if err != nil {
+				return nil, fmt.Errorf("annotation %s of service %s/%s: %w", healthCheckExpectedStatusAnnotation, service.Namespace, service.Name, err)
+			}
+			httpHealthCheck.ExpectedStatuses = statuses
+		}
+		healthCheck.HealthChecker = &corev3.HealthCheck_HttpHealthCheck_{HttpHealthCheck: httpHealthCheck}
+	case healthCheckTypeGRPC:
+		healthCheck.HealthChecker = &corev3.HealthCheck_GrpcHealthCheck_{
+			GrpcHealthCheck: &corev3.HealthCheck_GrpcHealthCheck{
+				ServiceName: service.Annotations[healthCheckGRPCServiceAnnotation],
+			},
+		}
+	default:
+		return nil, fmt.Errorf("annotation %s of service %s/%s: unsupported health check type %q, must be %s or %s",
+			healthCheckAnnotation, service.Namespace, service.Name, checkType, healthCheckTypeHTTP, healthCheckTypeGRPC)
+	}
+
+	interval, ok, err := durationAnnotation(service, healthCheckIntervalAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		healthCheck.Interval = interval
+	}
+	timeout, ok, err := durationAnnotation(service, healthCheckTimeoutAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		healthCheck.Timeout = timeout
+	}
+	healthyThreshold, ok, err := uint32Annotation(service, healthCheckHealthyThresholdAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		healthCheck.HealthyThreshold = wrapperspb.UInt32(healthyThreshold)
+	}
+	unhealthyThreshold, ok, err := uint32Annotation(service, healthCheckUnhealthyThresholdAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		healthCheck.UnhealthyThreshold = wrapperspb.UInt32(unhealthyThreshold)
+	}
+	return []*corev3.HealthCheck{healthCheck}, nil
+}
+
+// parseExpectedStatuses parses a comma-separated list of HTTP status codes and inclusive
+// ranges of status codes, e.g. "200,204-206", into Envoy's half-open ranges.
+func parseExpectedStatuses(value string) ([]*typev3.Int64Range, error) {
+	var ranges []*typev3.Int64Range
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		startStr, endStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			endStr = startStr
+		}
+		start, err := parseStatusCode(startStr)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseStatusCode(endStr)
+		if err != nil {
+			return nil, err
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid status code range %q", part)
+		}
+		ranges = append(ranges, &typev3.Int64Range{Start: start, End: end + 1})
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(value string) (int64, error) {
+	code, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid status code %q", value)
+	}
+	if code < 100 || code > 599 {
+		return 0, fmt.Errorf("status code %d is out of range", code)
+	}
+	return code, nil
+}
@@ -0,0 +1,100 @@
+package translator
+
+import (
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTranslateHealthChecks(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        *corev3.HealthCheck
+		wantHTTP2   bool
+	}{
+		{name: "unannotated"},
+		{
+			name: "HTTP",
+			annotations: map[string]string{
+				healthCheckAnnotation:                   healthCheckTypeHTTP,
+				healthCheckPathAnnotation:               "/healthz",
+				healthCheckExpectedStatusAnnotation:     "200,204-206",
+				healthCheckIntervalAnnotation:           "5s",
+				healthCheckTimeoutAnnotation:            "500ms",
+				healthCheckHealthyThresholdAnnotation:   "1",
+				healthCheckUnhealthyThresholdAnnotation: "4",
+			},
+			want: &corev3.HealthCheck{
+				Interval:           durationpb.New(5 * time.Second),
+				Timeout:            durationpb.New(500 * time.Millisecond),
+				HealthyThreshold:   wrapperspb.UInt32(1),
+				UnhealthyThreshold: wrapperspb.UInt32(4),
+				HealthChecker: &corev3.HealthCheck_HttpHealthCheck_{HttpHealthCheck: &corev3.HealthCheck_HttpHealthCheck{
+					Path:             "/healthz",
+					ExpectedStatuses: []*typev3.Int64Range{{Start: 200, End: 201}, {Start: 204, End: 207}},
+				}},
+			},
+		},
+		{
+			name:        "gRPC",
+			annotations: map[string]string{healthCheckAnnotation: healthCheckTypeGRPC, healthCheckGRPCServiceAnnotation: "echo.Echo"},
+			want: &corev3.HealthCheck{
+				Interval:           durationpb.New(10 * time.Second),
+				Timeout:            durationpb.New(time.Second),
+				HealthyThreshold:   wrapperspb.UInt32(2),
+				UnhealthyThreshold: wrapperspb.UInt32(3),
+				HealthChecker: &corev3.HealthCheck_GrpcHealthCheck_{GrpcHealthCheck: &corev3.HealthCheck_GrpcHealthCheck{
+					ServiceName: "echo.Echo",
+				}},
+			},
+			wantHTTP2: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, Options{}, service)
+
+			if tc.want == nil {
+				if len(cluster.HealthChecks) != 0 {
+					t.Errorf("health checks = %v, want none", cluster.HealthChecks)
+				}
+				return
+			}
+			if len(cluster.HealthChecks) != 1 || !proto.Equal(cluster.HealthChecks[0], tc.want) {
+				t.Errorf("health checks = %v, want %v", cluster.HealthChecks, tc.want)
+			}
+			// gRPC health checks need HTTP/2 to the backend.
+			_, http2 := cluster.TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"]
+			if http2 != tc.wantHTTP2 {
+				t.Errorf("cluster HTTP/2 = %v, want %v", http2, tc.wantHTTP2)
+			}
+		})
+	}
+}
+
+func TestBuildHealthChecksInvalidAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{name: "unsupported type", annotations: map[string]string{healthCheckAnnotation: "tcp"}},
+		{name: "relative path", annotations: map[string]string{healthCheckAnnotation: healthCheckTypeHTTP, healthCheckPathAnnotation: "healthz"}},
+		{name: "inverted status range", annotations: map[string]string{healthCheckAnnotation: healthCheckTypeHTTP, healthCheckExpectedStatusAnnotation: "299-200"}},
+		{name: "invalid interval", annotations: map[string]string{healthCheckAnnotation: healthCheckTypeGRPC, healthCheckIntervalAnnotation: "often"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			if healthChecks, err := buildHealthChecks(service); err == nil {
+				t.Errorf("buildHealthChecks() = %v, want an error", healthChecks)
+			}
+		})
+	}
+}
@@ -3,14 +3,24 @@ package translator
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -18,124 +28,164 @@ import (
 )
 
 // translateHTTPRouteToEnvoyRoutes translates a full HTTPRoute into a slice of Envoy Routes.
-// It now correctly handles RequestHeaderModifier filters.
+// Matches that cannot be translated are skipped and reported in the returned condition. The
+// errors of the matches skipped for an invalid regular expression are also returned.
 func translateHTTPRouteToEnvoyRoutes(
 	httpRoute *gatewayv1.HTTPRoute,
 	serviceLister corev1listers.ServiceLister,
 	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
-) ([]*routev3.Route, []gatewayv1.BackendRef, metav1.Condition) {
+) ([]*routev3.Route, []gatewayv1.BackendRef, metav1.Condition, error) {
 
 	var envoyRoutes []*routev3.Route
 	var allValidBackendRefs []gatewayv1.BackendRef
 	overallCondition := createSuccessCondition(httpRoute.Generation)
+	// regexErrs are the errors of the matches skipped for a regular expression that Envoy
+	// would reject. Unlike other errors, they are also returned, as the rest of the route
+	// is still translated.
+	var regexErrs []error
+
+	routeAnnotations, err := parseHTTPRouteAnnotations(httpRoute.Annotations)
+	if err != nil {
+		msg := fmt.Sprintf("HTTPRoute %s/%s: %v", httpRoute.Namespace, httpRoute.Name, err)
+		return nil, nil, createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, httpRoute.Generation), nil
+	}
+	variant := httpRouteClusterVariant(httpRoute)
 
 	for ruleIndex, rule := range httpRoute.Spec.Rules {
-		var redirectAction *routev3.RedirectAction
+		timeout, perTryTimeout, err := translateHTTPRouteTimeouts(rule.Timeouts)
+		if err != nil {
+			msg := fmt.Sprintf("HTTPRoute %s/%s rule %d: %v", httpRoute.Namespace, httpRoute.Name, ruleIndex, err)
+			overallCondition = createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, httpRoute.Generation)
+			continue
+		}
+
+		var redirect *gatewayv1.HTTPRequestRedirectFilter
+		var urlRewrite *gatewayv1.HTTPURLRewriteFilter
+		var mirrors []*gatewayv1.HTTPRequestMirrorFilter
 		var headersToAdd []*corev3.HeaderValueOption
 		var headersToRemove []string
+		var responseHeadersToAdd []*corev3.HeaderValueOption
+		var responseHeadersToRemove []string
 		for _, filter := range rule.Filters {
 			if filter.Type == gatewayv1.HTTPRouteFilterRequestRedirect && filter.RequestRedirect != nil {
-				redirect := filter.RequestRedirect
-				redirectAction = &routev3.RedirectAction{}
-
-				if redirect.Hostname != nil {
-					redirectAction.HostRedirect = string(*redirect.Hostname)
-				}
+				redirect = filter.RequestRedirect
+				continue // Only one redirect filter is allowed per rule.
+			}
 
-				if redirect.StatusCode != nil {
-					switch *redirect.StatusCode {
-					case 301:
-						redirectAction.ResponseCode = routev3.RedirectAction_MOVED_PERMANENTLY
-					case 302:
-						redirectAction.ResponseCode = routev3.RedirectAction_FOUND
-					case 303:
-						redirectAction.ResponseCode = routev3.RedirectAction_SEE_OTHER
-					case 307:
-						redirectAction.ResponseCode = routev3.RedirectAction_TEMPORARY_REDIRECT
-					case 308:
-						redirectAction.ResponseCode = routev3.RedirectAction_PERMANENT_REDIRECT
-					default:
-						redirectAction.ResponseCode = routev3.RedirectAction_MOVED_PERMANENTLY
-					}
-				} else {
-					// The Gateway API spec defaults to a 302 redirect.
-					// The corresponding Envoy enum is "FOUND".
-					redirectAction.ResponseCode = routev3.RedirectAction_FOUND
-				}
+			if filter.Type == gatewayv1.HTTPRouteFilterURLRewrite && filter.URLRewrite != nil {
+				urlRewrite = filter.URLRewrite
+				continue // Only one URL rewrite filter is allowed per rule.
+			}
 
-				break // Only one redirect filter is allowed per rule.
+			if filter.Type == gatewayv1.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+				mirrors = append(mirrors, filter.RequestMirror)
+				continue
 			}
 
 			if filter.Type == gatewayv1.HTTPRouteFilterRequestHeaderModifier && filter.RequestHeaderModifier != nil {
-				// Handle "set" actions (overwrite)
-				for _, header := range filter.RequestHeaderModifier.Set {
-					headersToAdd = append(headersToAdd, &corev3.HeaderValueOption{
-						Header: &corev3.HeaderValue{
-							Key:   string(header.Name),
-							Value: header.Value,
-						},
-						// This tells Envoy to overwrite the header if it exists.
-						AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
-					})
-				}
-
-				// Handle "add" actions (append)
-				for _, header := range filter.RequestHeaderModifier.Add {
-					headersToAdd = append(headersToAdd, &corev3.HeaderValueOption{
-						Header: &corev3.HeaderValue{
-							Key:   string(header.Name),
-							Value: header.Value,
-						},
-						// This tells Envoy to append the value if the header already exists.
-						AppendAction: corev3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
-					})
-				}
+				add, remove := translateHeaderModifier(filter.RequestHeaderModifier)
+				headersToAdd = append(headersToAdd, add...)
+				headersToRemove = append(headersToRemove, remove...)
+			}
 
-				// Handle "remove" actions
-				headersToRemove = append(headersToRemove, filter.RequestHeaderModifier.Remove...)
+			if filter.Type == gatewayv1.HTTPRouteFilterResponseHeaderModifier && filter.ResponseHeaderModifier != nil {
+				add, remove := translateHeaderModifier(filter.ResponseHeaderModifier)
+				responseHeadersToAdd = append(responseHeadersToAdd, add...)
+				responseHeadersToRemove = append(responseHeadersToRemove, remove...)
 			}
 		}
 
 		buildRoutesForRule := func(match gatewayv1.HTTPRouteMatch, matchIndex int) {
-			routeMatch, matchCondition := translateHTTPRouteMatch(match, httpRoute.Generation)
-			if matchCondition.Status == metav1.ConditionFalse {
-				overallCondition = matchCondition
+			routeMatch, err := translateHTTPRouteMatch(match)
+			if err != nil {
+				msg := fmt.Sprintf("HTTPRoute %s/%s rule %d match %d: %v", httpRoute.Namespace, httpRoute.Name, ruleIndex, matchIndex, err)
+				overallCondition = createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, httpRoute.Generation)
+				if errors.Is(err, errInvalidRegex) {
+					regexErrs = append(regexErrs, errors.New(msg))
+				}
 				return
 			}
+			if routeAnnotations.headerMatchIgnoreCase {
+				setHeaderMatchIgnoreCase(routeMatch)
+			}
 
 			envoyRoute := &routev3.Route{
-				Name:                   fmt.Sprintf("%s-%s-rule%d-match%d", httpRoute.Namespace, httpRoute.Name, ruleIndex, matchIndex),
-				Match:                  routeMatch,
-				RequestHeadersToAdd:    headersToAdd,
-				RequestHeadersToRemove: headersToRemove,
+				Name:                    fmt.Sprintf("%s-%s-rule%d-match%d", httpRoute.Namespace, httpRoute.Name, ruleIndex, matchIndex),
+				Match:                   routeMatch,
+				RequestHeadersToAdd:     headersToAdd,
+				RequestHeadersToRemove:  headersToRemove,
+				ResponseHeadersToAdd:    responseHeadersToAdd,
+				ResponseHeadersToRemove: responseHeadersToRemove,
 			}
 
-			if redirectAction != nil {
+			if redirect != nil {
 				// If this is a redirect, set the Redirect action. No backends are needed.
+				redirectAction, err := translateRequestRedirect(redirect, match)
+				if err != nil {
+					msg := fmt.Sprintf("HTTPRoute %s/%s rule %d match %d: %v", httpRoute.Namespace, httpRoute.Name, ruleIndex, matchIndex, err)
+					overallCondition = createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, httpRoute.Generation)
+					return
+				}
 				envoyRoute.Action = &routev3.Route_Redirect{
 					Redirect: redirectAction,
 				}
 			} else {
 				// Attempt to build the forwarding action and get valid backends.
 				routeAction, validBackends, err := buildHTTPRouteAction(
+					"HTTPRoute",
 					httpRoute.Namespace,
-					rule.BackendRefs,
+					httpBackendRefsToBackendRefs(rule.BackendRefs),
+					variant,
 					serviceLister,
 					referenceGrantLister,
 				)
 				var controllerErr *ControllerError
 				if errors.As(err, &controllerErr) {
 					overallCondition = createFailureCondition(gatewayv1.RouteConditionReason(controllerErr.Reason), controllerErr.Message, httpRoute.Generation)
+				}
+				if errors.Is(err, errAllBackendsZeroWeight) {
+					allValidBackendRefs = append(allValidBackendRefs, validBackends...)
+					envoyRoute.Action = &routev3.Route_DirectResponse{
+						DirectResponse: &routev3.DirectResponseAction{Status: 503},
+					}
+				} else if routeAction == nil {
 					envoyRoute.Action = &routev3.Route_DirectResponse{
 						DirectResponse: &routev3.DirectResponseAction{Status: 500},
 					}
 				} else {
+					if urlRewrite != nil {
+						if err := translateURLRewrite(routeAction, urlRewrite, match); err != nil {
+							msg := fmt.Sprintf("HTTPRoute %s/%s rule %d match %d: %v", httpRoute.Namespace, httpRoute.Name, ruleIndex, matchIndex, err)
+							overallCondition = createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, httpRoute.Generation)
+							return
+						}
+					}
+					routeAction.Timeout = timeout
+					routeAction.RetryPolicy = buildRetryPolicy(routeAnnotations.retry, perTryTimeout)
+					if routeAnnotations.hashPolicy != nil {
+						routeAction.HashPolicy = []*routev3.RouteAction_HashPolicy{routeAnnotations.hashPolicy}
+					}
+					routeAction.RateLimits = routeAnnotations.rateLimits
+					routeAction.UpgradeConfigs = routeAnnotations.webSocketUpgrades
+					if len(mirrors) > 0 {
+						mirrorPolicies, mirrorBackends, err := translateRequestMirrors(httpRoute.Namespace, mirrors, variant, serviceLister, referenceGrantLister)
+						if errors.As(err, &controllerErr) {
+							// Traffic is still forwarded, only the shadow copy is dropped.
+							overallCondition = createFailureCondition(gatewayv1.RouteConditionReason(controllerErr.Reason), controllerErr.Message, httpRoute.Generation)
+						} else {
+							routeAction.RequestMirrorPolicies = mirrorPolicies
+							allValidBackendRefs = append(allValidBackendRefs, mirrorBackends...)
+						}
+					}
 					allValidBackendRefs = append(allValidBackendRefs, validBackends...)
 					envoyRoute.Action = &routev3.Route_Route{
 						Route: routeAction,
 					}
 				}
 			}
+			if len(routeAnnotations.typedPerFilterConfig) > 0 {
+				envoyRoute.TypedPerFilterConfig = maps.Clone(routeAnnotations.typedPerFilterConfig)
+			}
 			envoyRoutes = append(envoyRoutes, envoyRoute)
 		}
 
@@ -147,17 +197,532 @@ func translateHTTPRouteToEnvoyRoutes(
 			}
 		}
 	}
-	return envoyRoutes, allValidBackendRefs, overallCondition
+	return envoyRoutes, allValidBackendRefs, overallCondition, errors.Join(regexErrs...)
+}
+
+// httpRouteAnnotations is the configuration of all routes of an HTTPRoute read from its
+// annotations.
+type httpRouteAnnotations struct {
+	retry                 *routeRetry
+	hashPolicy            *routev3.RouteAction_HashPolicy
+	rateLimits            []*routev3.RateLimit
+	headerMatchIgnoreCase bool
+	webSocketUpgrades     []*routev3.RouteAction_UpgradeConfig
+	// typedPerFilterConfig configures the HTTP filters for all routes of the HTTPRoute.
+	typedPerFilterConfig map[string]*anypb.Any
+}
+
+// httpFilterAnnotationParsers read the per-route configs of the HTTP filters from the
+// annotations of an HTTPRoute. A parser returns nil if the annotations don't configure its
+// filter.
+var httpFilterAnnotationParsers = []struct {
+	filterName string
+	parse      func(annotations map[string]string) (*anypb.Any, error)
+}{
+	{wellknown.CORS, parseCORSAnnotations},
+	{localRateLimitFilterName, parseLocalRateLimitAnnotations},
+	{wellknown.HTTPExternalAuthorization, parseExtAuthzAnnotations},
+	{wellknown.Fault, parseFaultAnnotations},
+	{wellknown.GRPCWeb, parseGRPCWebAnnotations},
+	{compressorFilterName, parseCompressionAnnotations},
+	{wellknown.Buffer, parseBufferAnnotations},
+	{headerToMetadataFilterName, parseHeaderToMetadataAnnotations},
+}
+
+// parseHTTPRouteAnnotations reads the configuration of the routes of an HTTPRoute from its
+// annotations. The errors of all invalid annotations are reported together.
+func parseHTTPRouteAnnotations(annotations map[string]string) (*httpRouteAnnotations, error) {
+	var errs []string
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	routeAnnotations := &httpRouteAnnotations{typedPerFilterConfig: make(map[string]*anypb.Any)}
+	var err error
+	routeAnnotations.retry, err = parseRetryAnnotations(annotations)
+	collect(err)
+	routeAnnotations.hashPolicy, err = parseSessionAffinityAnnotations(annotations)
+	collect(err)
+	routeAnnotations.rateLimits, err = parseRateLimitAnnotations(annotations)
+	collect(err)
+	routeAnnotations.headerMatchIgnoreCase, err = parseHeaderMatchIgnoreCase(annotations)
+	collect(err)
+	routeAnnotations.webSocketUpgrades, err = parseWebSocketAnnotations(annotations)
+	collect(err)
+	for _, parser := range httpFilterAnnotationParsers {
+		filterConfig, err := parser.parse(annotations)
+		collect(err)
+		if filterConfig != nil {
+			routeAnnotations.typedPerFilterConfig[parser.filterName] = filterConfig
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+	return routeAnnotations, nil
+}
+
+// The annotations of an HTTPRoute that configure retries on all of its routes.
+const (
+	retryOnAnnotation       = "gateway.xds/retry-on"
+	numRetriesAnnotation    = "gateway.xds/num-retries"
+	perTryTimeoutAnnotation = "gateway.xds/per-try-timeout"
+)
+
+// retryOnConditions are the retry conditions supported by Envoy's router.
+var retryOnConditions = sets.New(
+	"5xx", "gateway-error", "reset", "reset-before-request", "connect-failure",
+	"envoy-ratelimited", "retriable-4xx", "refused-stream", "retriable-status-codes",
+	"retriable-headers", "http3-post-connect-failure",
+	"cancelled", "deadline-exceeded", "internal", "resource-exhausted", "unavailable",
+)
+
+// routeRetry is the retry configuration read from the annotations of an HTTPRoute.
+type routeRetry struct {
+	retryOn       string
+	numRetries    *uint32
+	perTryTimeout *durationpb.Duration
+}
+
+// parseRetryAnnotations reads the retry configuration from the annotations of an HTTPRoute.
+// It returns nil if no retry conditions are configured, so requests are not retried.
+func parseRetryAnnotations(annotations map[string]string) (*routeRetry, error) {
+	retryOn, ok := annotations[retryOnAnnotation]
+	if !ok {
+		if _, ok := annotations[numRetriesAnnotation]; ok {
+			return nil, fmt.Errorf("annotation %s requires annotation %s", numRetriesAnnotation, retryOnAnnotation)
+		}
+		if _, ok := annotations[perTryTimeoutAnnotation]; ok {
+			return nil, fmt.Errorf("annotation %s requires annotation %s", perTryTimeoutAnnotation, retryOnAnnotation)
+		}
+		return nil, nil
+	}
+
+	retry := &routeRetry{}
+	var conditions []string
+	for _, condition := range strings.Split(retryOn, ",") {
+		condition = strings.TrimSpace(condition)
+		if !retryOnConditions.Has(condition) {
+			return nil, fmt.Errorf("annotation %s: unsupported retry condition %q", retryOnAnnotation, condition)
+		}
+		conditions = append(conditions, condition)
+	}
+	retry.retryOn = strings.Join(conditions, ",")
+
+	if value, ok := annotations[numRetriesAnnotation]; ok {
+		numRetries, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", numRetriesAnnotation, err)
+		}
+		n := uint32(numRetries)
+		retry.numRetries = &n
+	}
+	if value, ok := annotations[perTryTimeoutAnnotation]; ok {
+		duration := gatewayv1.Duration(value)
+		perTryTimeout, err := parseGatewayDuration(&duration)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", perTryTimeoutAnnotation, err)
+		}
+		retry.perTryTimeout = perTryTimeout
+	}
+	return retry, nil
+}
+
+// buildRetryPolicy builds the retry policy of a route. The per-try timeout derived from the
+// rule's backendRequest timeout takes precedence over the one of the retry annotations.
+// It returns nil if neither retries nor a per-try timeout are configured.
+func buildRetryPolicy(retry *routeRetry, perTryTimeout *durationpb.Duration) *routev3.RetryPolicy {
+	if retry == nil && perTryTimeout == nil {
+		return nil
+	}
+	retryPolicy := &routev3.RetryPolicy{}
+	if retry != nil {
+		retryPolicy.RetryOn = retry.retryOn
+		if retry.numRetries != nil {
+			retryPolicy.NumRetries = wrapperspb.UInt32(*retry.numRetries)
+		}
+		retryPolicy.PerTryTimeout = retry.perTryTimeout
+	}
+	if perTryTimeout != nil {
+		retryPolicy.PerTryTimeout = perTryTimeout
+	}
+	return retryPolicy
+}
+
+// The annotations of an HTTPRoute that pin clients to a backend endpoint with a cookie.
+const (
+	sessionAffinityCookieAnnotation    = "gateway.xds/session-affinity-cookie"
+	sessionAffinityCookieTTLAnnotation = "gateway.xds/session-affinity-cookie-ttl"
+)
+
+// hasSessionAffinity reports whether the HTTPRoute requests cookie-based session affinity.
+func hasSessionAffinity(httpRoute *gatewayv1.HTTPRoute) bool {
+	_, ok := httpRoute.Annotations[sessionAffinityCookieAnnotation]
+	return ok
+}
+
+// parseSessionAffinityAnnotations reads the cookie-based session affinity from the
+// annotations of an HTTPRoute and returns the hash policy of its routes, or nil if the
+// HTTPRoute has no session affinity. The TTL is always set, so that Envoy generates the
+// cookie if a request lacks it. A zero TTL, the default, generates a session cookie.
+func parseSessionAffinityAnnotations(annotations map[string]string) (*routev3.RouteAction_HashPolicy, error) {
+	cookieName, ok := annotations[sessionAffinityCookieAnnotation]
+	if !ok {
+		if _, ok := annotations[sessionAffinityCookieTTLAnnotation]; ok {
+			return nil, fmt.Errorf("annotation %s requires annotation %s", sessionAffinityCookieTTLAnnotation, sessionAffinityCookieAnnotation)
+		}
+		return nil, nil
+	}
+	if cookieName == "" {
+		return nil, fmt.Errorf("annotation %s must not be empty", sessionAffinityCookieAnnotation)
+	}
+
+	var ttl time.Duration
+	if value, ok := annotations[sessionAffinityCookieTTLAnnotation]; ok {
+		var err error
+		ttl, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", sessionAffinityCookieTTLAnnotation, err)
+		}
+		if ttl < 0 {
+			return nil, fmt.Errorf("annotation %s: duration %q must not be negative", sessionAffinityCookieTTLAnnotation, value)
+		}
+	}
+	return &routev3.RouteAction_HashPolicy{
+		PolicySpecifier: &routev3.RouteAction_HashPolicy_Cookie_{
+			Cookie: &routev3.RouteAction_HashPolicy_Cookie{
+				Name: cookieName,
+				Ttl:  durationpb.New(ttl),
+			},
+		},
+	}, nil
+}
+
+// translateHTTPRouteTimeouts translates the timeouts of an HTTPRoute rule into the Envoy
+// route timeout and the per-try timeout of the retry policy. A timeout that is unset or zero
+// is returned as nil, which leaves Envoy's default in place.
+func translateHTTPRouteTimeouts(timeouts *gatewayv1.HTTPRouteTimeouts) (*durationpb.Duration, *durationpb.Duration, error) {
+	if timeouts == nil {
+		return nil, nil, nil
+	}
+	timeout, err := parseGatewayDuration(timeouts.Request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid request timeout: %w", err)
+	}
+	perTryTimeout, err := parseGatewayDuration(timeouts.BackendRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid backendRequest timeout: %w", err)
+	}
+	return timeout, perTryTimeout, nil
+}
+
+// parseGatewayDuration parses a Gateway API duration, returning nil if it is unset or zero.
+func parseGatewayDuration(duration *gatewayv1.Duration) (*durationpb.Duration, error) {
+	if duration == nil || *duration == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(string(*duration))
+	if err != nil {
+		return nil, err
+	}
+	if d < 0 {
+		return nil, fmt.Errorf("duration %q must not be negative", *duration)
+	}
+	if d == 0 {
+		return nil, nil
+	}
+	return durationpb.New(d), nil
+}
+
+// translateRequestRedirect translates a RequestRedirect filter into an Envoy RedirectAction
+// for the given match. Fields that are not set keep the corresponding part of the request
+// URL, so a scheme-only redirect leaves the host and path untouched.
+func translateRequestRedirect(redirect *gatewayv1.HTTPRequestRedirectFilter, match gatewayv1.HTTPRouteMatch) (*routev3.RedirectAction, error) {
+	redirectAction := &routev3.RedirectAction{}
+
+	if redirect.Scheme != nil {
+		redirectAction.SchemeRewriteSpecifier = &routev3.RedirectAction_SchemeRedirect{
+			SchemeRedirect: *redirect.Scheme,
+		}
+	}
+	if redirect.Hostname != nil {
+		redirectAction.HostRedirect = string(*redirect.Hostname)
+	}
+	if redirect.Port != nil {
+		redirectAction.PortRedirect = uint32(*redirect.Port)
+	}
+
+	if redirect.Path != nil {
+		switch redirect.Path.Type {
+		case gatewayv1.FullPathHTTPPathModifier:
+			if redirect.Path.ReplaceFullPath == nil {
+				return nil, fmt.Errorf("redirect path of type %s requires replaceFullPath", redirect.Path.Type)
+			}
+			redirectAction.PathRewriteSpecifier = &routev3.RedirectAction_PathRedirect{
+				PathRedirect: *redirect.Path.ReplaceFullPath,
+			}
+		case gatewayv1.PrefixMatchHTTPPathModifier:
+			if redirect.Path.ReplacePrefixMatch == nil {
+				return nil, fmt.Errorf("redirect path of type %s requires replacePrefixMatch", redirect.Path.Type)
+			}
+			prefixRewrite, regexRewrite, err := translatePrefixRewrite(match, *redirect.Path.ReplacePrefixMatch)
+			if err != nil {
+				return nil, err
+			}
+			if regexRewrite != nil {
+				redirectAction.PathRewriteSpecifier = &routev3.RedirectAction_RegexRewrite{RegexRewrite: regexRewrite}
+			} else {
+				redirectAction.PathRewriteSpecifier = &routev3.RedirectAction_PrefixRewrite{PrefixRewrite: prefixRewrite}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported redirect path type: %s", redirect.Path.Type)
+		}
+	}
+
+	if redirect.StatusCode != nil {
+		switch *redirect.StatusCode {
+		case 301:
+			redirectAction.ResponseCode = routev3.RedirectAction_MOVED_PERMANENTLY
+		case 302:
+			redirectAction.ResponseCode = routev3.RedirectAction_FOUND
+		case 303:
+			redirectAction.ResponseCode = routev3.RedirectAction_SEE_OTHER
+		case 307:
+			redirectAction.ResponseCode = routev3.RedirectAction_TEMPORARY_REDIRECT
+		case 308:
+			redirectAction.ResponseCode = routev3.RedirectAction_PERMANENT_REDIRECT
+		default:
+			return nil, fmt.Errorf("unsupported redirect status code: %d", *redirect.StatusCode)
+		}
+	} else {
+		// The Gateway API spec defaults to a 302 redirect.
+		// The corresponding Envoy enum is "FOUND".
+		redirectAction.ResponseCode = routev3.RedirectAction_FOUND
+	}
+	return redirectAction, nil
+}
+
+// translatePrefixRewrite translates a ReplacePrefixMatch path modifier for the given match.
+// Envoy's prefix_rewrite does a plain string replacement, which would leave a double or
+// missing slash when either the matched prefix or the replacement is "/". Those cases are
+// expressed as a regex rewrite instead, and the returned prefix is only valid if the regex
+// rewrite is nil.
+func translatePrefixRewrite(match gatewayv1.HTTPRouteMatch, replacement string) (string, *matcherv3.RegexMatchAndSubstitute, error) {
+	// A match without a path matches the "/" prefix.
+	prefix := "/"
+	if match.Path != nil {
+		if match.Path.Type != nil && *match.Path.Type != gatewayv1.PathMatchPathPrefix {
+			return "", nil, fmt.Errorf("ReplacePrefixMatch requires a %s path match, got %s", gatewayv1.PathMatchPathPrefix, *match.Path.Type)
+		}
+		if match.Path.Value != nil {
+			prefix = *match.Path.Value
+		}
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	replacement = strings.TrimSuffix(replacement, "/")
+
+	if prefix != "" && replacement != "" {
+		return replacement, nil, nil
+	}
+	regexMatcher, err := newRegexMatcher("^" + regexp.QuoteMeta(prefix) + "/*")
+	if err != nil {
+		return "", nil, err
+	}
+	return "", &matcherv3.RegexMatchAndSubstitute{
+		Pattern:      regexMatcher,
+		Substitution: replacement + "/",
+	}, nil
+}
+
+// translateURLRewrite applies a URLRewrite filter for the given match to the route action.
+// Only the parts of the request that are set in the filter are rewritten.
+func translateURLRewrite(routeAction *routev3.RouteAction, urlRewrite *gatewayv1.HTTPURLRewriteFilter, match gatewayv1.HTTPRouteMatch) error {
+	if urlRewrite.Hostname != nil {
+		routeAction.HostRewriteSpecifier = &routev3.RouteAction_HostRewriteLiteral{
+			HostRewriteLiteral: string(*urlRewrite.Hostname),
+		}
+	}
+
+	if urlRewrite.Path != nil {
+		switch urlRewrite.Path.Type {
+		case gatewayv1.FullPathHTTPPathModifier:
+			if urlRewrite.Path.ReplaceFullPath == nil {
+				return fmt.Errorf("rewrite path of type %s requires replaceFullPath", urlRewrite.Path.Type)
+			}
+			// Route actions have no full path rewrite, so the whole path is matched instead.
+			regexMatcher, err := newRegexMatcher("^/.*$")
+			if err != nil {
+				return err
+			}
+			routeAction.RegexRewrite = &matcherv3.RegexMatchAndSubstitute{
+				Pattern:      regexMatcher,
+				Substitution: *urlRewrite.Path.ReplaceFullPath,
+			}
+		case gatewayv1.PrefixMatchHTTPPathModifier:
+			if urlRewrite.Path.ReplacePrefixMatch == nil {
+				return fmt.Errorf("rewrite path of type %s requires replacePrefixMatch", urlRewrite.Path.Type)
+			}
+			prefixRewrite, regexRewrite, err := translatePrefixRewrite(match, *urlRewrite.Path.ReplacePrefixMatch)
+			if err != nil {
+				return err
+			}
+			if regexRewrite != nil {
+				routeAction.RegexRewrite = regexRewrite
+			} else {
+				routeAction.PrefixRewrite = prefixRewrite
+			}
+		default:
+			return fmt.Errorf("unsupported rewrite path type: %s", urlRewrite.Path.Type)
+		}
+	}
+	return nil
+}
+
+// translateRequestMirrors translates RequestMirror filters into Envoy request mirror policies,
+// one per filter that mirrors any requests. It also returns the mirror backends so that their
+// clusters get created; a mirror of the same Service as the primary backend shares its cluster.
+func translateRequestMirrors(
+	namespace string,
+	mirrors []*gatewayv1.HTTPRequestMirrorFilter,
+	variant clusterVariant,
+	serviceLister corev1listers.ServiceLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+) ([]*routev3.RouteAction_RequestMirrorPolicy, []gatewayv1.BackendRef, error) {
+	var mirrorPolicies []*routev3.RouteAction_RequestMirrorPolicy
+	var mirrorBackends []gatewayv1.BackendRef
+	for _, mirror := range mirrors {
+		// Mirror all requests unless a percentage or fraction is given.
+		fraction := &typev3.FractionalPercent{
+			Numerator:   100,
+			Denominator: typev3.FractionalPercent_HUNDRED,
+		}
+		if mirror.Percent != nil {
+			fraction.Numerator = uint32(*mirror.Percent)
+		} else if mirror.Fraction != nil {
+			denominator := int32(100)
+			if mirror.Fraction.Denominator != nil {
+				denominator = *mirror.Fraction.Denominator
+			}
+			// Envoy only supports fixed denominators, so the fraction is scaled to a million.
+			fraction.Numerator = uint32(int64(mirror.Fraction.Numerator) * 1000000 / int64(denominator))
+			fraction.Denominator = typev3.FractionalPercent_MILLION
+		}
+
+		// A mirror of no requests needs neither a policy nor a cluster.
+		if fraction.Numerator == 0 {
+			continue
+		}
+
+		routeAction, validBackendRefs, err := buildHTTPRouteAction(
+			"HTTPRoute",
+			namespace,
+			[]gatewayv1.BackendRef{{BackendObjectReference: mirror.BackendRef}},
+			variant,
+			serviceLister,
+			referenceGrantLister,
+		)
+		if routeAction == nil {
+			return nil, nil, err
+		}
+
+		mirrorPolicies = append(mirrorPolicies, &routev3.RouteAction_RequestMirrorPolicy{
+			Cluster: routeAction.GetCluster(),
+			RuntimeFraction: &corev3.RuntimeFractionalPercent{
+				DefaultValue: fraction,
+			},
+		})
+		mirrorBackends = append(mirrorBackends, validBackendRefs...)
+	}
+	return mirrorPolicies, mirrorBackends, nil
+}
+
+// translateHeaderModifier translates a Gateway API header modifier into the Envoy headers to
+// add and remove. Set headers overwrite existing values, while added headers are appended,
+// so that every value added for the same header name is kept. Removed header names are
+// de-duplicated case-insensitively. A header that is both removed and set or added ends up
+// with only the set or added values, as Envoy removes headers before adding them.
+func translateHeaderModifier(modifier *gatewayv1.HTTPHeaderFilter) ([]*corev3.HeaderValueOption, []string) {
+	var headersToAdd []*corev3.HeaderValueOption
+	for _, header := range modifier.Set {
+		headersToAdd = append(headersToAdd, &corev3.HeaderValueOption{
+			Header: &corev3.HeaderValue{
+				Key:   string(header.Name),
+				Value: header.Value,
+			},
+			AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+		})
+	}
+	for _, header := range modifier.Add {
+		headersToAdd = append(headersToAdd, &corev3.HeaderValueOption{
+			Header: &corev3.HeaderValue{
+				Key:   string(header.Name),
+				Value: header.Value,
+			},
+			AppendAction: corev3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
+		})
+	}
+
+	seen := sets.New[string]()
+	var headersToRemove []string
+	for _, name := range modifier.Remove {
+		name = strings.ToLower(name)
+		if seen.Has(name) {
+			continue
+		}
+		seen.Insert(name)
+		headersToRemove = append(headersToRemove, name)
+	}
+	return headersToAdd, headersToRemove
+}
+
+// serverHeader is the response header that Envoy overwrites with its own name by default.
+const serverHeader = "server"
+
+// routesModifyServerHeader returns whether any route of the virtual hosts sets, adds or
+// removes the server response header.
+func routesModifyServerHeader(virtualHosts []*routev3.VirtualHost) bool {
+	for _, vh := range virtualHosts {
+		for _, route := range vh.Routes {
+			for _, header := range route.ResponseHeadersToAdd {
+				if strings.EqualFold(header.GetHeader().GetKey(), serverHeader) {
+					return true
+				}
+			}
+			if slices.Contains(route.ResponseHeadersToRemove, serverHeader) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
+// httpBackendRefsToBackendRefs strips the HTTP-specific fields from a list of HTTPBackendRefs.
+func httpBackendRefsToBackendRefs(httpBackendRefs []gatewayv1.HTTPBackendRef) []gatewayv1.BackendRef {
+	backendRefs := make([]gatewayv1.BackendRef, 0, len(httpBackendRefs))
+	for _, httpBackendRef := range httpBackendRefs {
+		backendRefs = append(backendRefs, httpBackendRef.BackendRef)
+	}
+	return backendRefs
+}
+
+// errAllBackendsZeroWeight is returned by buildHTTPRouteAction when backends were provided but
+// all of them have a weight of 0. Such a route must not forward any traffic.
+var errAllBackendsZeroWeight = errors.New("all backends have a weight of 0")
+
 // buildHTTPRouteAction returns an action, a list of *valid* BackendRefs, and a structured error.
-func buildHTTPRouteAction(namespace string, backendRefs []gatewayv1.HTTPBackendRef, serviceLister corev1listers.ServiceLister, referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister) (*routev3.RouteAction, []gatewayv1.BackendRef, error) {
+// The routeKind is the kind of the referencing route and is used for ReferenceGrant checks.
+// Cross-namespace backends that no ReferenceGrant permits are skipped: the action forwards to
+// the remaining backends and is returned together with a RefNotPermitted ControllerError.
+// Backends whose Service does not expose the referenced port are skipped the same way, with
+// a BackendNotFound ControllerError. The action forwards to the clusters of the given variant.
+func buildHTTPRouteAction(routeKind gatewayv1.Kind, namespace string, backendRefs []gatewayv1.BackendRef, variant clusterVariant, serviceLister corev1listers.ServiceLister, referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister) (*routev3.RouteAction, []gatewayv1.BackendRef, error) {
 	weightedClusters := &routev3.WeightedCluster{}
 	var validBackendRefs []gatewayv1.BackendRef
+	var totalWeight int32
+	var skippedBackendErr error
 
-	for _, httpBackendRef := range backendRefs {
-		backendRef := httpBackendRef.BackendRef
-
+	for _, backendRef := range backendRefs {
 		ns := namespace
 		if backendRef.Namespace != nil {
 			ns = string(*backendRef.Namespace)
@@ -167,7 +732,7 @@ func buildHTTPRouteAction(namespace string, backendRefs []gatewayv1.HTTPBackendR
 		if ns != namespace {
 			from := gatewayv1beta1.ReferenceGrantFrom{
 				Group:     gatewayv1.GroupName,
-				Kind:      "HTTPRoute",
+				Kind:      routeKind,
 				Namespace: gatewayv1.Namespace(namespace),
 			}
 			to := gatewayv1beta1.ReferenceGrantTo{
@@ -176,16 +741,26 @@ func buildHTTPRouteAction(namespace string, backendRefs []gatewayv1.HTTPBackendR
 				Name:  &backendRef.Name,
 			}
 
-			if !isCrossNamespaceRefAllowed(from, to, ns, referenceGrantLister) {
-				// The reference is not permitted.
-				return nil, nil, &ControllerError{
+			allowed, err := isCrossNamespaceRefAllowed(from, to, ns, referenceGrantLister)
+			if err != nil {
+				skippedBackendErr = &ControllerError{
+					Reason:  string(gatewayv1.RouteReasonRefNotPermitted),
+					Message: fmt.Sprintf("backendRef to Service %s/%s could not be checked against ReferenceGrants: %v", ns, backendRef.Name, err),
+				}
+				continue
+			}
+			if !allowed {
+				// The reference is not permitted, so the backend is skipped.
+				skippedBackendErr = &ControllerError{
 					Reason:  string(gatewayv1.RouteReasonRefNotPermitted),
-					Message: "permission error",
+					Message: fmt.Sprintf("backendRef to Service %s/%s is not permitted by any ReferenceGrant", ns, backendRef.Name),
 				}
+				continue
 			}
 		}
 
-		if _, err := serviceLister.Services(ns).Get(string(backendRef.Name)); err != nil {
+		service, err := serviceLister.Services(ns).Get(string(backendRef.Name))
+		if err != nil {
 			return nil, nil, &ControllerError{
 				Reason:  string(gatewayv1.RouteReasonBackendNotFound),
 				Message: "backend not found",
@@ -195,14 +770,22 @@ func buildHTTPRouteAction(namespace string, backendRefs []gatewayv1.HTTPBackendR
 		if err != nil {
 			return nil, nil, err
 		}
+		clusterName = variant.clusterName(clusterName)
+		if !serviceHasPort(service, int32(*backendRef.Port)) {
+			// A cluster for the port would have no endpoints, so the backend is skipped.
+			skippedBackendErr = &ControllerError{
+				Reason:  string(gatewayv1.RouteReasonBackendNotFound),
+				Message: fmt.Sprintf("backendRef to Service %s/%s references port %d, which the Service does not expose", ns, backendRef.Name, *backendRef.Port),
+			}
+			continue
+		}
 
 		weight := int32(1)
-		if httpBackendRef.Weight != nil {
-			weight = *httpBackendRef.Weight
-		}
-		if weight == 0 {
-			continue
+		if backendRef.Weight != nil {
+			weight = *backendRef.Weight
 		}
+		totalWeight += weight
+		// Backends with a weight of 0 still get a cluster, they just receive no traffic.
 		validBackendRefs = append(validBackendRefs, backendRef)
 		weightedClusters.Clusters = append(weightedClusters.Clusters, &routev3.WeightedCluster_ClusterWeight{
 			Name:   clusterName,
@@ -211,7 +794,13 @@ func buildHTTPRouteAction(namespace string, backendRefs []gatewayv1.HTTPBackendR
 	}
 
 	if len(weightedClusters.Clusters) == 0 {
-		return nil, nil, &ControllerError{Reason: string(gatewayv1.RouteReasonUnsupportedValue), Message: "no valid backends provided with a weight > 0"}
+		if skippedBackendErr != nil {
+			return nil, nil, skippedBackendErr
+		}
+		return nil, nil, &ControllerError{Reason: string(gatewayv1.RouteReasonUnsupportedValue), Message: "no valid backends provided"}
+	}
+	if totalWeight == 0 {
+		return nil, validBackendRefs, errors.Join(skippedBackendErr, errAllBackendsZeroWeight)
 	}
 
 	var action *routev3.RouteAction
@@ -221,12 +810,12 @@ func buildHTTPRouteAction(namespace string, backendRefs []gatewayv1.HTTPBackendR
 		action = &routev3.RouteAction{ClusterSpecifier: &routev3.RouteAction_WeightedClusters{WeightedClusters: weightedClusters}}
 	}
 
-	return action, validBackendRefs, nil
+	return action, validBackendRefs, skippedBackendErr
 }
 
 // translateHTTPRouteMatch translates a Gateway API HTTPRouteMatch into an Envoy RouteMatch.
-// It returns the result and a condition indicating success or failure.
-func translateHTTPRouteMatch(match gatewayv1.HTTPRouteMatch, generation int64) (*routev3.RouteMatch, metav1.Condition) {
+// Invalid regular expressions are reported with an error wrapping errInvalidRegex.
+func translateHTTPRouteMatch(match gatewayv1.HTTPRouteMatch) (*routev3.RouteMatch, error) {
 	routeMatch := &routev3.RouteMatch{}
 
 	if match.Path != nil {
@@ -235,8 +824,7 @@ func translateHTTPRouteMatch(match gatewayv1.HTTPRouteMatch, generation int64) (
 			pathType = *match.Path.Type
 		}
 		if match.Path.Value == nil {
-			msg := "path match value cannot be nil"
-			return nil, createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, generation)
+			return nil, errors.New("path match value cannot be nil")
 		}
 		pathValue := *match.Path.Value
 
@@ -251,68 +839,155 @@ func translateHTTPRouteMatch(match gatewayv1.HTTPRouteMatch, generation int64) (
 				routeMatch.PathSpecifier = &routev3.RouteMatch_PathSeparatedPrefix{PathSeparatedPrefix: path}
 			}
 		case gatewayv1.PathMatchRegularExpression:
-			routeMatch.PathSpecifier = &routev3.RouteMatch_SafeRegex{
-				SafeRegex: &matcherv3.RegexMatcher{
-					EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
-					Regex:      pathValue,
-				},
+			regexMatcher, err := newRegexMatcher(pathValue)
+			if err != nil {
+				return nil, fmt.Errorf("path: %w", err)
 			}
+			routeMatch.PathSpecifier = &routev3.RouteMatch_SafeRegex{SafeRegex: regexMatcher}
 		default:
-			msg := fmt.Sprintf("unsupported path match type: %s", pathType)
-			return nil, createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, generation)
+			return nil, fmt.Errorf("unsupported path match type: %s", pathType)
 		}
 	} else {
 		// As per Gateway API spec, a nil path match defaults to matching everything.
 		routeMatch.PathSpecifier = &routev3.RouteMatch_Prefix{Prefix: "/"}
 	}
 
-	// Translate Header Matches
+	// Translate Header Matches. All header matches must match, so they are ANDed on the route.
+	// Header names are case-insensitive and only the first match for a given name is honored.
+	seenHeaders := sets.New[string]()
 	for _, headerMatch := range match.Headers {
-		headerMatcher := &routev3.HeaderMatcher{
-			Name: string(headerMatch.Name),
+		headerName := strings.ToLower(string(headerMatch.Name))
+		if seenHeaders.Has(headerName) {
+			continue
 		}
+		seenHeaders.Insert(headerName)
+
 		matchType := gatewayv1.HeaderMatchExact
 		if headerMatch.Type != nil {
 			matchType = *headerMatch.Type
 		}
-
-		switch matchType {
-		case gatewayv1.HeaderMatchExact:
-			headerMatcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_StringMatch{
-				StringMatch: &matcherv3.StringMatcher{
-					MatchPattern: &matcherv3.StringMatcher_Exact{Exact: headerMatch.Value},
-				},
-			}
-		case gatewayv1.HeaderMatchRegularExpression:
-			headerMatcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_SafeRegexMatch{
-				SafeRegexMatch: &matcherv3.RegexMatcher{
-					EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
-					Regex:      headerMatch.Value,
-				},
-			}
-		default:
-			msg := fmt.Sprintf("unsupported header match type: %s", matchType)
-			return nil, createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, generation)
+		headerMatcher, err := buildHeaderMatcher(headerName, headerMatch.Value, matchType)
+		if err != nil {
+			return nil, err
 		}
 		routeMatch.Headers = append(routeMatch.Headers, headerMatcher)
 	}
 
+	// Translate Method Match. Envoy exposes the request method as the :method pseudo-header.
+	if match.Method != nil {
+		methodMatcher, err := buildHeaderMatcher(":method", string(*match.Method), gatewayv1.HeaderMatchExact)
+		if err != nil {
+			return nil, err
+		}
+		routeMatch.Headers = append(routeMatch.Headers, methodMatcher)
+	}
+
 	// Translate Query Parameter Matches
+	// Unlike header names, query parameter names are case-sensitive.
+	seenQueryParams := sets.New[string]()
 	for _, queryMatch := range match.QueryParams {
-		// Gateway API only supports "Exact" match for query parameters.
-		queryMatcher := &routev3.QueryParameterMatcher{
-			Name: string(queryMatch.Name),
-			QueryParameterMatchSpecifier: &routev3.QueryParameterMatcher_StringMatch{
-				StringMatch: &matcherv3.StringMatcher{
-					MatchPattern: &matcherv3.StringMatcher_Exact{Exact: queryMatch.Value},
-				},
-			},
+		queryName := string(queryMatch.Name)
+		if seenQueryParams.Has(queryName) {
+			continue
+		}
+		seenQueryParams.Insert(queryName)
+
+		matchType := gatewayv1.QueryParamMatchExact
+		if queryMatch.Type != nil {
+			matchType = *queryMatch.Type
+		}
+		queryMatcher, err := buildQueryParameterMatcher(queryName, queryMatch.Value, matchType)
+		if err != nil {
+			return nil, err
 		}
 		routeMatch.QueryParameters = append(routeMatch.QueryParameters, queryMatcher)
 	}
 
-	// If all translations were successful, return the final object and a success condition.
-	return routeMatch, createSuccessCondition(generation)
+	return routeMatch, nil
+}
+
+// presentMatchRegex is the regular expression that matches any header value, so a match on
+// it only requires the header to be present.
+const presentMatchRegex = ".*"
+
+// buildHeaderMatcher translates a single Gateway API header match into an Envoy HeaderMatcher.
+// It is shared by HTTPRoute and GRPCRoute header matches. An exact match on an empty value,
+// or a regular expression match on ".*", matches whenever the header is present.
+func buildHeaderMatcher(name, value string, matchType gatewayv1.HeaderMatchType) (*routev3.HeaderMatcher, error) {
+	headerMatcher := &routev3.HeaderMatcher{
+		// Envoy stores header names in lowercase.
+		Name: strings.ToLower(name),
+	}
+
+	if (matchType == gatewayv1.HeaderMatchExact && value == "") ||
+		(matchType == gatewayv1.HeaderMatchRegularExpression && value == presentMatchRegex) {
+		headerMatcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_PresentMatch{PresentMatch: true}
+		return headerMatcher, nil
+	}
+
+	switch matchType {
+	case gatewayv1.HeaderMatchExact:
+		headerMatcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_StringMatch{
+			StringMatch: &matcherv3.StringMatcher{
+				MatchPattern: &matcherv3.StringMatcher_Exact{Exact: value},
+			},
+		}
+	case gatewayv1.HeaderMatchRegularExpression:
+		regexMatcher, err := newRegexMatcher(value)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+		headerMatcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_StringMatch{
+			StringMatch: &matcherv3.StringMatcher{
+				MatchPattern: &matcherv3.StringMatcher_SafeRegex{SafeRegex: regexMatcher},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported header match type: %s", matchType)
+	}
+	return headerMatcher, nil
+}
+
+// buildQueryParameterMatcher translates a single Gateway API query parameter match into an
+// Envoy QueryParameterMatcher.
+func buildQueryParameterMatcher(name, value string, matchType gatewayv1.QueryParamMatchType) (*routev3.QueryParameterMatcher, error) {
+	stringMatcher := &matcherv3.StringMatcher{}
+	switch matchType {
+	case gatewayv1.QueryParamMatchExact:
+		stringMatcher.MatchPattern = &matcherv3.StringMatcher_Exact{Exact: value}
+	case gatewayv1.QueryParamMatchRegularExpression:
+		regexMatcher, err := newRegexMatcher(value)
+		if err != nil {
+			return nil, fmt.Errorf("query parameter %s: %w", name, err)
+		}
+		stringMatcher.MatchPattern = &matcherv3.StringMatcher_SafeRegex{SafeRegex: regexMatcher}
+	default:
+		return nil, fmt.Errorf("unsupported query parameter match type: %s", matchType)
+	}
+	return &routev3.QueryParameterMatcher{
+		Name: name,
+		QueryParameterMatchSpecifier: &routev3.QueryParameterMatcher_StringMatch{
+			StringMatch: stringMatcher,
+		},
+	}, nil
+}
+
+// errInvalidRegex is returned by newRegexMatcher for regular expressions that Envoy's RE2
+// engine would reject, e.g. those with backreferences or lookarounds.
+var errInvalidRegex = errors.New("invalid regular expression")
+
+// newRegexMatcher validates the regular expression and returns an RE2 RegexMatcher for it.
+// Envoy matches the regex against the whole value, as if it were anchored with ^ and $,
+// so "v[0-9]+" matches "v2" but not "v2-beta".
+func newRegexMatcher(regex string) (*matcherv3.RegexMatcher, error) {
+	// Go's regexp package implements the RE2 syntax used by Envoy.
+	if _, err := regexp.Compile(regex); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", errInvalidRegex, regex, err)
+	}
+	return &matcherv3.RegexMatcher{
+		EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
+		Regex:      regex,
+	}, nil
 }
 
 func createSuccessCondition(generation int64) metav1.Condition {
@@ -339,7 +1014,7 @@ func createFailureCondition(reason gatewayv1.RouteConditionReason, message strin
 
 // sortRoutes is the definitive sorter for Envoy routes based on Gateway API precedence.
 func sortRoutes(routes []*routev3.Route) {
-	sort.Slice(routes, func(i, j int) bool {
+	sort.SliceStable(routes, func(i, j int) bool {
 		matchI := routes[i].GetMatch()
 		matchJ := routes[j].GetMatch()
 
@@ -368,14 +1043,25 @@ func sortRoutes(routes []*routev3.Route) {
 			return len(prefixI) > len(prefixJ) // Longer prefix is higher precedence
 		}
 
-		// Precedence Rule 3: Number of Header Matches
+		// Precedence Rule 3: Method Match
+		hasMethodI := hasMethodMatch(matchI)
+		hasMethodJ := hasMethodMatch(matchJ)
+		if hasMethodI != hasMethodJ {
+			return hasMethodI // A method match is higher precedence
+		}
+
+		// Precedence Rule 4: Number of Header Matches, the method match is counted separately.
 		headerCountI := len(matchI.GetHeaders())
 		headerCountJ := len(matchJ.GetHeaders())
+		if hasMethodI {
+			headerCountI--
+			headerCountJ--
+		}
 		if headerCountI != headerCountJ {
 			return headerCountI > headerCountJ // More headers is higher precedence
 		}
 
-		// Precedence Rule 4: Number of Query Param Matches
+		// Precedence Rule 5: Number of Query Param Matches
 		queryCountI := len(matchI.GetQueryParameters())
 		queryCountJ := len(matchJ.GetQueryParameters())
 		if queryCountI != queryCountJ {
@@ -387,6 +1073,16 @@ func sortRoutes(routes []*routev3.Route) {
 	})
 }
 
+// hasMethodMatch reports whether the route match matches on the request method.
+func hasMethodMatch(match *routev3.RouteMatch) bool {
+	for _, header := range match.GetHeaders() {
+		if header.GetName() == ":method" {
+			return true
+		}
+	}
+	return false
+}
+
 // getPathMatchValue is a helper to extract the path string for comparison.
 func getPathMatchValue(match *routev3.RouteMatch) string {
 	if match.GetPath() != "" {
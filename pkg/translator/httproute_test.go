@@ -0,0 +1,1037 @@
+package translator
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// pathMatch returns an HTTPRouteMatch of the path.
+func pathMatch(matchType gatewayv1.PathMatchType, path string) gatewayv1.HTTPRouteMatch {
+	return gatewayv1.HTTPRouteMatch{Path: &gatewayv1.HTTPPathMatch{Type: ptr(matchType), Value: ptr(path)}}
+}
+
+// routeNames returns the names of the routes of the virtual host in order.
+func routeNames(vh *routev3.VirtualHost) []string {
+	var names []string
+	for _, route := range vh.Routes {
+		names = append(names, route.Name)
+	}
+	return names
+}
+
+// translateMatch translates an HTTPRoute with the match and returns the Envoy route match.
+func translateMatch(t *testing.T, match gatewayv1.HTTPRouteMatch) *routev3.RouteMatch {
+	t.Helper()
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{match}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+	return findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0").Match
+}
+
+func exactHeaderMatcher(name, value string) *routev3.HeaderMatcher {
+	return &routev3.HeaderMatcher{
+		Name: name,
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+			StringMatch: &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Exact{Exact: value}},
+		},
+	}
+}
+
+func TestTranslateHTTPRouteHeaderMatch(t *testing.T) {
+	match := translateMatch(t, gatewayv1.HTTPRouteMatch{
+		Headers: []gatewayv1.HTTPHeaderMatch{
+			// Header names are case-insensitive, so they are lowercased like Envoy does.
+			{Name: "X-Env", Value: "canary"},
+			{Type: ptr(gatewayv1.HeaderMatchExact), Name: "x-team", Value: "web"},
+		},
+	})
+
+	// All header matches of a rule are ANDed on the route.
+	want := []*routev3.HeaderMatcher{exactHeaderMatcher("x-env", "canary"), exactHeaderMatcher("x-team", "web")}
+	if len(match.Headers) != len(want) {
+		t.Fatalf("header matchers = %v, want %v", match.Headers, want)
+	}
+	for i := range want {
+		if !proto.Equal(match.Headers[i], want[i]) {
+			t.Errorf("header matcher %d = %v, want %v", i, match.Headers[i], want[i])
+		}
+	}
+}
+
+// TestTranslateHTTPRoutePresentHeaderMatch checks that an exact match on an empty value, or a
+// regex match on any value, matches whenever the header is present, along with the other
+// matches of the rule.
+func TestTranslateHTTPRoutePresentHeaderMatch(t *testing.T) {
+	for _, header := range []gatewayv1.HTTPHeaderMatch{
+		{Name: "X-Internal"},
+		{Type: ptr(gatewayv1.HeaderMatchRegularExpression), Name: "x-internal", Value: presentMatchRegex},
+	} {
+		match := translateMatch(t, gatewayv1.HTTPRouteMatch{
+			Path:    &gatewayv1.HTTPPathMatch{Type: ptr(gatewayv1.PathMatchPathPrefix), Value: ptr("/admin")},
+			Headers: []gatewayv1.HTTPHeaderMatch{header, {Name: "x-team", Value: "web"}},
+		})
+
+		want := []*routev3.HeaderMatcher{
+			{Name: "x-internal", HeaderMatchSpecifier: &routev3.HeaderMatcher_PresentMatch{PresentMatch: true}},
+			exactHeaderMatcher("x-team", "web"),
+		}
+		if len(match.Headers) != len(want) || !proto.Equal(match.Headers[0], want[0]) || !proto.Equal(match.Headers[1], want[1]) {
+			t.Errorf("header matchers of %v = %v, want %v", header, match.Headers, want)
+		}
+		if match.GetPathSeparatedPrefix() != "/admin" {
+			t.Errorf("path match of %v = %v, want prefix /admin", header, match.PathSpecifier)
+		}
+	}
+}
+
+func TestTranslateHTTPRouteRegexHeaderMatch(t *testing.T) {
+	match := translateMatch(t, gatewayv1.HTTPRouteMatch{
+		Headers: []gatewayv1.HTTPHeaderMatch{{Type: ptr(gatewayv1.HeaderMatchRegularExpression), Name: "x-version", Value: "v[0-9]+"}},
+	})
+
+	want := &routev3.HeaderMatcher{
+		Name: "x-version",
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+			StringMatch: &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_SafeRegex{SafeRegex: &matcherv3.RegexMatcher{
+				EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
+				Regex:      "v[0-9]+",
+			}}},
+		},
+	}
+	if len(match.Headers) != 1 || !proto.Equal(match.Headers[0], want) {
+		t.Errorf("header matchers = %v, want %v", match.Headers, want)
+	}
+}
+
+// TestTranslateHTTPRouteInvalidRegexHeaderMatch checks that a match with a regex that RE2
+// rejects is skipped and reported, while the other matches of the route are translated.
+func TestTranslateHTTPRouteInvalidRegexHeaderMatch(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{
+		{Headers: []gatewayv1.HTTPHeaderMatch{{Type: ptr(gatewayv1.HeaderMatchRegularExpression), Name: "x-version", Value: `(v)\1`}}},
+		pathMatch(gatewayv1.PathMatchPathPrefix, "/valid"),
+	}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources, err := tl.TranslateGatewayToXDS(context.Background(), gw)
+	if err == nil || !strings.Contains(err.Error(), errInvalidRegex.Error()) {
+		t.Errorf("TranslateGatewayToXDS() error = %v, want %v", err, errInvalidRegex)
+	}
+
+	got := routeNames(findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"))
+	if want := []string{"default-web-rule0-match1", "gw-vh-80-*-not-found"}; !slices.Equal(got, want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+}
+
+func TestTranslateHTTPRouteQueryParamMatch(t *testing.T) {
+	match := translateMatch(t, gatewayv1.HTTPRouteMatch{
+		Path: &gatewayv1.HTTPPathMatch{Type: ptr(gatewayv1.PathMatchPathPrefix), Value: ptr("/api")},
+		QueryParams: []gatewayv1.HTTPQueryParamMatch{
+			{Name: "version", Value: "2"},
+			// Query parameter names are case-sensitive, unlike header names.
+			{Type: ptr(gatewayv1.QueryParamMatchRegularExpression), Name: "Region", Value: "eu-.*"},
+		},
+	})
+
+	want := []*routev3.QueryParameterMatcher{
+		{
+			Name: "version",
+			QueryParameterMatchSpecifier: &routev3.QueryParameterMatcher_StringMatch{
+				StringMatch: &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Exact{Exact: "2"}},
+			},
+		},
+		{
+			Name: "Region",
+			QueryParameterMatchSpecifier: &routev3.QueryParameterMatcher_StringMatch{
+				StringMatch: &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_SafeRegex{SafeRegex: &matcherv3.RegexMatcher{
+					EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
+					Regex:      "eu-.*",
+				}}},
+			},
+		},
+	}
+	if len(match.QueryParameters) != len(want) {
+		t.Fatalf("query parameter matchers = %v, want %v", match.QueryParameters, want)
+	}
+	for i := range want {
+		if !proto.Equal(match.QueryParameters[i], want[i]) {
+			t.Errorf("query parameter matcher %d = %v, want %v", i, match.QueryParameters[i], want[i])
+		}
+	}
+	// The query parameters are ANDed with the path of the same match.
+	if match.GetPathSeparatedPrefix() != "/api" {
+		t.Errorf("path specifier = %v, want the /api prefix", match.PathSpecifier)
+	}
+}
+
+func TestTranslateHTTPRouteMethodMatch(t *testing.T) {
+	match := translateMatch(t, gatewayv1.HTTPRouteMatch{
+		Method:  ptr(gatewayv1.HTTPMethodPut),
+		Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-env", Value: "canary"}},
+	})
+
+	// The method is matched on the :method pseudo-header, ANDed with the other headers.
+	want := []*routev3.HeaderMatcher{exactHeaderMatcher("x-env", "canary"), exactHeaderMatcher(":method", "PUT")}
+	if len(match.Headers) != len(want) {
+		t.Fatalf("header matchers = %v, want %v", match.Headers, want)
+	}
+	for i := range want {
+		if !proto.Equal(match.Headers[i], want[i]) {
+			t.Errorf("header matcher %d = %v, want %v", i, match.Headers[i], want[i])
+		}
+	}
+}
+
+func TestTranslateHTTPRoutePathMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		match gatewayv1.HTTPRouteMatch
+		want  *routev3.RouteMatch
+	}{
+		{
+			name:  "exact",
+			match: pathMatch(gatewayv1.PathMatchExact, "/healthz"),
+			want:  &routev3.RouteMatch{PathSpecifier: &routev3.RouteMatch_Path{Path: "/healthz"}},
+		},
+		{
+			name:  "regular expression",
+			match: pathMatch(gatewayv1.PathMatchRegularExpression, "/api/v[12]/.*"),
+			want: &routev3.RouteMatch{PathSpecifier: &routev3.RouteMatch_SafeRegex{SafeRegex: &matcherv3.RegexMatcher{
+				EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
+				Regex:      "/api/v[12]/.*",
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translateMatch(t, tt.match); !proto.Equal(got, tt.want) {
+				t.Errorf("route match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateHTTPRouteInvalidRegexPathMatch(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchRegularExpression, "/api/(?=v1)")}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	_, err := tl.TranslateGatewayToXDS(context.Background(), gw)
+
+	// The error names the route, so that its owner can find it.
+	if err == nil || !strings.Contains(err.Error(), "HTTPRoute default/web rule 0 match 0") || !strings.Contains(err.Error(), errInvalidRegex.Error()) {
+		t.Errorf("TranslateGatewayToXDS() error = %v, want an invalid regex of HTTPRoute default/web", err)
+	}
+}
+
+// TestTranslateHTTPRouteBackreferenceRegex checks that a regex with a backreference, which
+// RE2 doesn't support, is reported with the route and the pattern rather than emitted.
+func TestTranslateHTTPRouteBackreferenceRegex(t *testing.T) {
+	const pattern = `/(v[0-9])/\1`
+	for _, tc := range []struct {
+		name  string
+		match gatewayv1.HTTPRouteMatch
+	}{
+		{name: "path", match: pathMatch(gatewayv1.PathMatchRegularExpression, pattern)},
+		{name: "header", match: gatewayv1.HTTPRouteMatch{
+			Headers: []gatewayv1.HTTPHeaderMatch{{Type: ptr(gatewayv1.HeaderMatchRegularExpression), Name: "x-path", Value: pattern}},
+		}},
+		{name: "query parameter", match: gatewayv1.HTTPRouteMatch{
+			QueryParams: []gatewayv1.HTTPQueryParamMatch{{Type: ptr(gatewayv1.QueryParamMatchRegularExpression), Name: "path", Value: pattern}},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+			route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{tc.match}
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+			resources, _, routeStatuses, err := tl.buildEnvoyResourcesForGateway(gw)
+
+			if err == nil || !strings.Contains(err.Error(), errInvalidRegex.Error()) {
+				t.Fatalf("buildEnvoyResourcesForGateway() error = %v, want an invalid regex", err)
+			}
+			for _, want := range []string{"HTTPRoute default/web", strconv.Quote(pattern)} {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want it to name %s", err, want)
+				}
+			}
+			for _, vh := range findRouteConfiguration(t, resources, "route-80").VirtualHosts {
+				if slices.Contains(routeNames(vh), "default-web-rule0-match0") {
+					t.Errorf("virtual host %s has route default-web-rule0-match0, want it skipped", vh.Name)
+				}
+			}
+			parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "web"}]
+			if len(parents) != 1 {
+				t.Fatalf("got %d parent statuses, want 1", len(parents))
+			}
+			resolvedRefs := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+			if resolvedRefs == nil || resolvedRefs.Status != metav1.ConditionFalse || resolvedRefs.Reason != string(gatewayv1.RouteReasonUnsupportedValue) {
+				t.Errorf("ResolvedRefs = %v, want False/UnsupportedValue", resolvedRefs)
+			}
+		})
+	}
+}
+
+// TestTranslateHTTPRouteNonexistentPort checks that a backendRef to a port that the Service
+// doesn't expose is skipped and reported, while the other backends of the rule are kept.
+func TestTranslateHTTPRouteNonexistentPort(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	missingPort := testHTTPRoute("missing-port", "gw", testBackendRef("backend", 8080))
+	split := testHTTPRoute("split", "gw", testBackendRef("backend", 80), testBackendRef("backend", 8080))
+	split.Spec.Hostnames = []gatewayv1.Hostname{"split.example.com"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), missingPort, split)
+	resources, _, routeStatuses, err := tl.buildEnvoyResourcesForGateway(gw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resourceNames(resources, resourcev3.ClusterType), []string{clusterName("backend", 80)}; !slices.Equal(got, want) {
+		t.Errorf("clusters = %v, want %v", got, want)
+	}
+	splitRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "split.example.com"), "default-split-rule0-match0")
+	if got := splitRoute.GetRoute().GetCluster(); got != clusterName("backend", 80) {
+		t.Errorf("route default-split-rule0-match0 cluster = %q, want %s", got, clusterName("backend", 80))
+	}
+
+	for _, route := range []string{"missing-port", "split"} {
+		parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: route}]
+		if len(parents) != 1 {
+			t.Fatalf("got %d parent statuses of %s, want 1", len(parents), route)
+		}
+		resolvedRefs := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+		if resolvedRefs == nil || resolvedRefs.Status != metav1.ConditionFalse || resolvedRefs.Reason != string(gatewayv1.RouteReasonBackendNotFound) ||
+			!strings.Contains(resolvedRefs.Message, "port 8080") {
+			t.Errorf("ResolvedRefs of %s = %v, want False/BackendNotFound for port 8080", route, resolvedRefs)
+		}
+	}
+}
+
+// weightedRoute translates an HTTPRoute splitting its traffic across the Services by weight,
+// and returns the Envoy route and the resources.
+func weightedRoute(t *testing.T, weights map[string]int32) (*routev3.Route, map[resourcev3.Type][]envoyproxytypes.Resource) {
+	t.Helper()
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw")
+	objs := []runtime.Object{testGatewayClass(), gw, route}
+	services := slices.Sorted(maps.Keys(weights))
+	for _, service := range services {
+		backendRef := testBackendRef(service, 80)
+		backendRef.Weight = ptr(weights[service])
+		route.Spec.Rules[0].BackendRefs = append(route.Spec.Rules[0].BackendRefs, gatewayv1.HTTPBackendRef{BackendRef: backendRef})
+		objs = append(objs, testService(service, 80))
+	}
+	tl := newTestTranslator(t, Options{}, objs...)
+	resources := translateGateway(t, tl, gw)
+	return findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0"), resources
+}
+
+func TestTranslateHTTPRouteWeightedBackends(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]int32
+	}{
+		{name: "canary split", weights: map[string]int32{"stable": 90, "canary": 10}},
+		// A backend without traffic still gets its cluster.
+		{name: "zero weight", weights: map[string]int32{"stable": 100, "canary": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envoyRoute, resources := weightedRoute(t, tt.weights)
+
+			got := make(map[string]int32)
+			for _, cluster := range envoyRoute.GetRoute().GetWeightedClusters().GetClusters() {
+				got[cluster.Name] = int32(cluster.GetWeight().GetValue())
+			}
+			want := make(map[string]int32)
+			for service, weight := range tt.weights {
+				want[clusterName(service, 80)] = weight
+				findCluster(t, resources, clusterName(service, 80))
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("weighted clusters = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestTranslateHTTPRouteAllBackendsZeroWeight checks that a rule whose backends all have a
+// weight of 0 responds with a 503, as the Gateway API requires.
+func TestTranslateHTTPRouteAllBackendsZeroWeight(t *testing.T) {
+	envoyRoute, _ := weightedRoute(t, map[string]int32{"stable": 0, "canary": 0})
+	if status := envoyRoute.GetDirectResponse().GetStatus(); status != 503 {
+		t.Errorf("route action = %v, want a direct 503 response", envoyRoute.Action)
+	}
+}
+
+func TestTranslateHTTPRouteRequestHeaderModifier(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+			Set:    []gatewayv1.HTTPHeader{{Name: "x-foo", Value: "bar"}},
+			Add:    []gatewayv1.HTTPHeader{{Name: "x-tag", Value: "a"}, {Name: "x-tag", Value: "b"}},
+			Remove: []string{"x-debug"},
+		},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+
+	want := []*corev3.HeaderValueOption{
+		{Header: &corev3.HeaderValue{Key: "x-foo", Value: "bar"}, AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD},
+		// Every value added for the same header is kept.
+		{Header: &corev3.HeaderValue{Key: "x-tag", Value: "a"}, AppendAction: corev3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD},
+		{Header: &corev3.HeaderValue{Key: "x-tag", Value: "b"}, AppendAction: corev3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD},
+	}
+	if len(envoyRoute.RequestHeadersToAdd) != len(want) {
+		t.Fatalf("request headers to add = %v, want %v", envoyRoute.RequestHeadersToAdd, want)
+	}
+	for i := range want {
+		if !proto.Equal(envoyRoute.RequestHeadersToAdd[i], want[i]) {
+			t.Errorf("request header to add %d = %v, want %v", i, envoyRoute.RequestHeadersToAdd[i], want[i])
+		}
+	}
+	if !slices.Equal(envoyRoute.RequestHeadersToRemove, []string{"x-debug"}) {
+		t.Errorf("request headers to remove = %v, want [x-debug]", envoyRoute.RequestHeadersToRemove)
+	}
+}
+
+// TestTranslateHTTPRouteResponseHeaderModifier checks that the response headers are set,
+// added and removed on the route, and that the connection manager passes the server header
+// through so that removing it takes effect.
+func TestTranslateHTTPRouteResponseHeaderModifier(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+		ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+			Set:    []gatewayv1.HTTPHeader{{Name: "x-frame-options", Value: "DENY"}},
+			Add:    []gatewayv1.HTTPHeader{{Name: "x-served-by", Value: "gateway"}},
+			Remove: []string{"Server", "x-debug"},
+		},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	assertGolden(t, findRouteConfiguration(t, resources, "route-80"))
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	if manager.ServerHeaderTransformation != hcm.HttpConnectionManager_PASS_THROUGH {
+		t.Errorf("server header transformation = %v, want PASS_THROUGH", manager.ServerHeaderTransformation)
+	}
+}
+
+// TestTranslateHTTPRouteResponseHeadersToRemove checks that every removed header is removed
+// once, and that a header both set and removed keeps the set value.
+func TestTranslateHTTPRouteResponseHeadersToRemove(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+		ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+			Set:    []gatewayv1.HTTPHeader{{Name: "x-cache", Value: "miss"}},
+			Remove: []string{"x-debug", "X-Powered-By", "x-cache", "X-Debug"},
+		},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+	if got, want := envoyRoute.ResponseHeadersToRemove, []string{"x-debug", "x-powered-by", "x-cache"}; !slices.Equal(got, want) {
+		t.Errorf("response headers to remove = %v, want %v", got, want)
+	}
+	// Envoy removes headers before adding them, so the set value wins.
+	if len(envoyRoute.ResponseHeadersToAdd) != 1 || envoyRoute.ResponseHeadersToAdd[0].GetHeader().GetValue() != "miss" {
+		t.Errorf("response headers to add = %v, want x-cache: miss", envoyRoute.ResponseHeadersToAdd)
+	}
+}
+
+// Envoy keeps setting the server header on listeners whose routes leave it alone.
+func TestTranslateHTTPRouteServerHeaderUnmodified(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+		ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Remove: []string{"x-debug"}},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	if manager.ServerHeaderTransformation != hcm.HttpConnectionManager_OVERWRITE {
+		t.Errorf("server header transformation = %v, want OVERWRITE", manager.ServerHeaderTransformation)
+	}
+}
+
+func TestTranslateRequestRedirect(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		redirect gatewayv1.HTTPRequestRedirectFilter
+		match    gatewayv1.HTTPRouteMatch
+		want     *routev3.RedirectAction
+	}{
+		{
+			// The HTTP to HTTPS redirect leaves the host and the path untouched.
+			name:     "scheme only",
+			redirect: gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr("https")},
+			want: &routev3.RedirectAction{
+				SchemeRewriteSpecifier: &routev3.RedirectAction_SchemeRedirect{SchemeRedirect: "https"},
+				ResponseCode:           routev3.RedirectAction_FOUND,
+			},
+		},
+		{
+			name: "full path",
+			redirect: gatewayv1.HTTPRequestRedirectFilter{
+				Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr("/maintenance")},
+				StatusCode: ptr(301),
+			},
+			want: &routev3.RedirectAction{
+				PathRewriteSpecifier: &routev3.RedirectAction_PathRedirect{PathRedirect: "/maintenance"},
+				ResponseCode:         routev3.RedirectAction_MOVED_PERMANENTLY,
+			},
+		},
+		{
+			name: "prefix",
+			redirect: gatewayv1.HTTPRequestRedirectFilter{
+				Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr("/v2")},
+			},
+			match: pathMatch(gatewayv1.PathMatchPathPrefix, "/v1"),
+			want: &routev3.RedirectAction{
+				PathRewriteSpecifier: &routev3.RedirectAction_PrefixRewrite{PrefixRewrite: "/v2"},
+				ResponseCode:         routev3.RedirectAction_FOUND,
+			},
+		},
+		{
+			name:     "hostname and port",
+			redirect: gatewayv1.HTTPRequestRedirectFilter{Hostname: ptr(gatewayv1.PreciseHostname("www.example.com")), Port: ptr(gatewayv1.PortNumber(8443))},
+			want: &routev3.RedirectAction{
+				HostRedirect: "www.example.com",
+				PortRedirect: 8443,
+				ResponseCode: routev3.RedirectAction_FOUND,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := translateRequestRedirect(&tc.redirect, tc.match)
+			if err != nil {
+				t.Fatalf("translateRequestRedirect() error = %v", err)
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("redirect = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTranslateHTTPRouteRequestRedirect(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw")
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr("https")},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, route)
+	resources := translateGateway(t, tl, gw)
+
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+	if envoyRoute.GetRedirect().GetSchemeRedirect() != "https" {
+		t.Errorf("route action = %v, want a redirect to https", envoyRoute.Action)
+	}
+}
+
+func TestTranslateHTTPRouteURLRewriteHostname(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		urlRewrite gatewayv1.HTTPURLRewriteFilter
+		wantPrefix string
+	}{
+		// The path passes through unchanged when only the hostname is rewritten.
+		{name: "hostname only", urlRewrite: gatewayv1.HTTPURLRewriteFilter{Hostname: ptr(gatewayv1.PreciseHostname("internal.example.com"))}},
+		{
+			name: "hostname and path",
+			urlRewrite: gatewayv1.HTTPURLRewriteFilter{
+				Hostname: ptr(gatewayv1.PreciseHostname("internal.example.com")),
+				Path:     &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr("/internal")},
+			},
+			wantPrefix: "/internal",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+			route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/api")}
+			route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{Type: gatewayv1.HTTPRouteFilterURLRewrite, URLRewrite: &tc.urlRewrite}}
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			routeAction := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0").GetRoute()
+			if routeAction.GetHostRewriteLiteral() != "internal.example.com" {
+				t.Errorf("host rewrite = %v, want host_rewrite_literal internal.example.com", routeAction.HostRewriteSpecifier)
+			}
+			if routeAction.PrefixRewrite != tc.wantPrefix || routeAction.RegexRewrite != nil {
+				t.Errorf("path rewrite = %q, %v, want prefix rewrite %q", routeAction.PrefixRewrite, routeAction.RegexRewrite, tc.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestTranslateURLRewritePath(t *testing.T) {
+	regexRewrite := func(pattern, substitution string) *matcherv3.RegexMatchAndSubstitute {
+		return &matcherv3.RegexMatchAndSubstitute{
+			Pattern: &matcherv3.RegexMatcher{
+				EngineType: &matcherv3.RegexMatcher_GoogleRe2{GoogleRe2: &matcherv3.RegexMatcher_GoogleRE2{}},
+				Regex:      pattern,
+			},
+			Substitution: substitution,
+		}
+	}
+	for _, tc := range []struct {
+		name    string
+		path    gatewayv1.HTTPPathModifier
+		match   gatewayv1.HTTPRouteMatch
+		want    *routev3.RouteAction
+		wantErr bool
+	}{
+		{
+			name:  "replace prefix",
+			path:  gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr("/v2")},
+			match: pathMatch(gatewayv1.PathMatchPathPrefix, "/api"),
+			want:  &routev3.RouteAction{PrefixRewrite: "/v2"},
+		},
+		{
+			// /api/users becomes /users rather than //users.
+			name:  "replace prefix with /",
+			path:  gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr("/")},
+			match: pathMatch(gatewayv1.PathMatchPathPrefix, "/api/"),
+			want:  &routev3.RouteAction{RegexRewrite: regexRewrite("^/api/*", "/")},
+		},
+		{
+			// /users becomes /v2/users rather than /v2users.
+			name:  "replace / prefix",
+			path:  gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr("/v2")},
+			match: pathMatch(gatewayv1.PathMatchPathPrefix, "/"),
+			want:  &routev3.RouteAction{RegexRewrite: regexRewrite("^/*", "/v2/")},
+		},
+		{
+			name: "replace full path",
+			path: gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr("/index.html")},
+			want: &routev3.RouteAction{RegexRewrite: regexRewrite("^/.*$", "/index.html")},
+		},
+		{
+			name:    "replace prefix of an exact match",
+			path:    gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr("/v2")},
+			match:   pathMatch(gatewayv1.PathMatchExact, "/api"),
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := &routev3.RouteAction{}
+			err := translateURLRewrite(got, &gatewayv1.HTTPURLRewriteFilter{Path: &tc.path}, tc.match)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("translateURLRewrite() error = %v, want error %v", err, tc.wantErr)
+			}
+			if err == nil && !proto.Equal(got, tc.want) {
+				t.Errorf("route action = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// translateMirrors translates an HTTPRoute to the backend Service with the RequestMirror
+// filters, and returns the mirror policies of its route and the resources.
+func translateMirrors(t *testing.T, mirrors ...*gatewayv1.HTTPRequestMirrorFilter) ([]*routev3.RouteAction_RequestMirrorPolicy, map[resourcev3.Type][]envoyproxytypes.Resource) {
+	t.Helper()
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	for _, mirror := range mirrors {
+		route.Spec.Rules[0].Filters = append(route.Spec.Rules[0].Filters, gatewayv1.HTTPRouteFilter{
+			Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: mirror,
+		})
+	}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend", 80), testService("shadow", 80), testService("audit", 80), route)
+	resources := translateGateway(t, tl, gw)
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+	return envoyRoute.GetRoute().GetRequestMirrorPolicies(), resources
+}
+
+func TestTranslateHTTPRouteRequestMirror(t *testing.T) {
+	policies, resources := translateMirrors(t,
+		&gatewayv1.HTTPRequestMirrorFilter{BackendRef: testBackendRef("shadow", 80).BackendObjectReference},
+		&gatewayv1.HTTPRequestMirrorFilter{BackendRef: testBackendRef("audit", 80).BackendObjectReference},
+		// A mirror of the primary backend shares its cluster.
+		&gatewayv1.HTTPRequestMirrorFilter{BackendRef: testBackendRef("backend", 80).BackendObjectReference},
+	)
+
+	// Every filter gets a policy of its own, which mirrors all requests by default.
+	var clusters []string
+	for _, policy := range policies {
+		clusters = append(clusters, policy.Cluster)
+		if fraction := policy.GetRuntimeFraction().GetDefaultValue(); fraction.GetNumerator() != 100 || fraction.GetDenominator() != typev3.FractionalPercent_HUNDRED {
+			t.Errorf("mirror to %s runtime fraction = %v, want 100%%", policy.Cluster, fraction)
+		}
+	}
+	want := []string{clusterName("shadow", 80), clusterName("audit", 80), clusterName("backend", 80)}
+	if !slices.Equal(clusters, want) {
+		t.Errorf("mirror clusters = %v, want %v", clusters, want)
+	}
+	got := resourceNames(resources, resourcev3.ClusterType)
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("clusters = %v, want %v", got, want)
+	}
+}
+
+func TestTranslateHTTPRouteRequestMirrorPercent(t *testing.T) {
+	policies, resources := translateMirrors(t,
+		&gatewayv1.HTTPRequestMirrorFilter{BackendRef: testBackendRef("shadow", 80).BackendObjectReference, Percent: ptr(int32(25))},
+		&gatewayv1.HTTPRequestMirrorFilter{
+			BackendRef: testBackendRef("audit", 80).BackendObjectReference,
+			Fraction:   &gatewayv1.Fraction{Numerator: 1, Denominator: ptr(int32(3))},
+		},
+	)
+
+	want := []*routev3.RouteAction_RequestMirrorPolicy{
+		{
+			Cluster: clusterName("shadow", 80),
+			RuntimeFraction: &corev3.RuntimeFractionalPercent{
+				DefaultValue: &typev3.FractionalPercent{Numerator: 25, Denominator: typev3.FractionalPercent_HUNDRED},
+			},
+		},
+		{
+			Cluster: clusterName("audit", 80),
+			RuntimeFraction: &corev3.RuntimeFractionalPercent{
+				DefaultValue: &typev3.FractionalPercent{Numerator: 333333, Denominator: typev3.FractionalPercent_MILLION},
+			},
+		},
+	}
+	if len(policies) != len(want) {
+		t.Fatalf("mirror policies = %v, want %v", policies, want)
+	}
+	for i := range want {
+		if !proto.Equal(policies[i], want[i]) {
+			t.Errorf("mirror policy %d = %v, want %v", i, policies[i], want[i])
+		}
+	}
+	findCluster(t, resources, clusterName("shadow", 80))
+}
+
+func TestTranslateHTTPRouteRequestMirrorZeroPercent(t *testing.T) {
+	policies, resources := translateMirrors(t,
+		&gatewayv1.HTTPRequestMirrorFilter{BackendRef: testBackendRef("shadow", 80).BackendObjectReference, Percent: ptr(int32(0))},
+	)
+
+	// A mirror of no requests needs neither a policy nor a cluster.
+	if len(policies) != 0 {
+		t.Errorf("mirror policies = %v, want none", policies)
+	}
+	if cluster := findResource(resources, resourcev3.ClusterType, clusterName("shadow", 80)); cluster != nil {
+		t.Errorf("cluster %s is emitted for a mirror of no requests", clusterName("shadow", 80))
+	}
+}
+
+func TestTranslateHTTPRouteTimeouts(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		timeouts          gatewayv1.HTTPRouteTimeouts
+		wantTimeout       time.Duration
+		wantPerTryTimeout time.Duration
+	}{
+		{name: "request", timeouts: gatewayv1.HTTPRouteTimeouts{Request: ptr(gatewayv1.Duration("5s"))}, wantTimeout: 5 * time.Second},
+		{
+			name:              "request and backend request",
+			timeouts:          gatewayv1.HTTPRouteTimeouts{Request: ptr(gatewayv1.Duration("5s")), BackendRequest: ptr(gatewayv1.Duration("2s"))},
+			wantTimeout:       5 * time.Second,
+			wantPerTryTimeout: 2 * time.Second,
+		},
+		// A zero timeout leaves Envoy's default in place.
+		{name: "zero", timeouts: gatewayv1.HTTPRouteTimeouts{Request: ptr(gatewayv1.Duration("0s"))}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+			route.Spec.Rules[0].Timeouts = &tc.timeouts
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			routeAction := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0").GetRoute()
+			if tc.wantTimeout == 0 {
+				if routeAction.Timeout != nil {
+					t.Errorf("route timeout = %v, want Envoy's default", routeAction.Timeout.AsDuration())
+				}
+			} else if routeAction.GetTimeout().AsDuration() != tc.wantTimeout {
+				t.Errorf("route timeout = %v, want %v", routeAction.Timeout, tc.wantTimeout)
+			}
+			if tc.wantPerTryTimeout == 0 {
+				if routeAction.RetryPolicy != nil {
+					t.Errorf("retry policy = %v, want none", routeAction.RetryPolicy)
+				}
+			} else if routeAction.GetRetryPolicy().GetPerTryTimeout().AsDuration() != tc.wantPerTryTimeout {
+				t.Errorf("per-try timeout = %v, want %v", routeAction.GetRetryPolicy().GetPerTryTimeout(), tc.wantPerTryTimeout)
+			}
+		})
+	}
+}
+
+func TestTranslateHTTPRouteInvalidTimeout(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.Rules[0].Timeouts = &gatewayv1.HTTPRouteTimeouts{Request: ptr(gatewayv1.Duration("five seconds"))}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources, _, routeStatuses, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+	// The rule is skipped, and the route reports why.
+	for _, vh := range findRouteConfiguration(t, resources, "route-80").VirtualHosts {
+		if slices.Contains(routeNames(vh), "default-web-rule0-match0") {
+			t.Errorf("virtual host %s has the route of the rule with the invalid timeout", vh.Name)
+		}
+	}
+	parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "web"}]
+	if len(parents) != 1 {
+		t.Fatalf("got %d parent statuses, want 1", len(parents))
+	}
+	condition := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != string(gatewayv1.RouteReasonUnsupportedValue) ||
+		!strings.Contains(condition.Message, "invalid request timeout") {
+		t.Errorf("ResolvedRefs = %v, want False/%s about the invalid request timeout", condition, gatewayv1.RouteReasonUnsupportedValue)
+	}
+}
+
+func TestTranslateHTTPRouteRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        *routev3.RetryPolicy
+	}{
+		{name: "no annotations"},
+		{
+			name:        "3 retries on 5xx",
+			annotations: map[string]string{retryOnAnnotation: "5xx", numRetriesAnnotation: "3"},
+			want:        &routev3.RetryPolicy{RetryOn: "5xx", NumRetries: wrapperspb.UInt32(3)},
+		},
+		{
+			name:        "conditions and per-try timeout",
+			annotations: map[string]string{retryOnAnnotation: "5xx, reset", perTryTimeoutAnnotation: "250ms"},
+			want:        &routev3.RetryPolicy{RetryOn: "5xx,reset", PerTryTimeout: durationpb.New(250 * time.Millisecond)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+			route.Annotations = tc.annotations
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			routeAction := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0").GetRoute()
+			if !proto.Equal(routeAction.RetryPolicy, tc.want) {
+				t.Errorf("retry policy = %v, want %v", routeAction.RetryPolicy, tc.want)
+			}
+		})
+	}
+}
+
+// TestParseHTTPRouteAnnotationsInvalid checks that the errors of all invalid annotations of
+// an HTTPRoute are reported together.
+func TestParseHTTPRouteAnnotationsInvalid(t *testing.T) {
+	annotations := map[string]string{
+		retryOnAnnotation:                  "5xx,sometimes",
+		sessionAffinityCookieTTLAnnotation: "1h",
+		bufferMaxRequestBytesAnnotation:    "1MiB",
+	}
+	routeAnnotations, err := parseHTTPRouteAnnotations(annotations)
+	if err == nil {
+		t.Fatalf("parseHTTPRouteAnnotations() = %v, want an error", routeAnnotations)
+	}
+	for annotation := range annotations {
+		if !strings.Contains(err.Error(), annotation) {
+			t.Errorf("parseHTTPRouteAnnotations() error = %q, want it to report %s", err, annotation)
+		}
+	}
+}
+
+func TestParseRetryAnnotationsInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{name: "unsupported condition", annotations: map[string]string{retryOnAnnotation: "5xx,sometimes"}},
+		{name: "invalid number of retries", annotations: map[string]string{retryOnAnnotation: "5xx", numRetriesAnnotation: "-1"}},
+		{name: "invalid per-try timeout", annotations: map[string]string{retryOnAnnotation: "5xx", perTryTimeoutAnnotation: "soon"}},
+		{name: "retries without conditions", annotations: map[string]string{numRetriesAnnotation: "3"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if retry, err := parseRetryAnnotations(tc.annotations); err == nil {
+				t.Errorf("parseRetryAnnotations(%v) = %v, want an error", tc.annotations, retry)
+			}
+		})
+	}
+}
+
+// TestTranslateHTTPRouteSessionAffinity checks that an HTTPRoute with session affinity hashes
+// on its cookie and forwards to a consistent hashing cluster of its own, while another route
+// to the same backend keeps the default load balancing policy.
+func TestTranslateHTTPRouteSessionAffinity(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	sticky := testHTTPRoute("sticky", "gw", testBackendRef("backend", 80))
+	sticky.Annotations = map[string]string{sessionAffinityCookieAnnotation: "session"}
+	sticky.Spec.Hostnames = []gatewayv1.Hostname{"sticky.example.com"}
+	plain := testHTTPRoute("plain", "gw", testBackendRef("backend", 80))
+	plain.Spec.Hostnames = []gatewayv1.Hostname{"plain.example.com"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), sticky, plain)
+	resources := translateGateway(t, tl, gw)
+	routeConfiguration := findRouteConfiguration(t, resources, "route-80")
+
+	stickyRoute := findRoute(t, findVirtualHost(t, routeConfiguration, "sticky.example.com"), "default-sticky-rule0-match0")
+	hashPolicies := stickyRoute.GetRoute().GetHashPolicy()
+	if len(hashPolicies) != 1 || hashPolicies[0].GetCookie().GetName() != "session" {
+		t.Errorf("hash policies = %v, want one on cookie session", hashPolicies)
+	}
+	stickyCluster := clusterName("backend", 80) + "_session_affinity"
+	if got := stickyRoute.GetRoute().GetCluster(); got != stickyCluster {
+		t.Errorf("sticky route cluster = %q, want %q", got, stickyCluster)
+	}
+	if got := findCluster(t, resources, stickyCluster).LbPolicy; got != clusterv3.Cluster_RING_HASH {
+		t.Errorf("sticky cluster LB policy = %v, want RING_HASH", got)
+	}
+
+	plainRoute := findRoute(t, findVirtualHost(t, routeConfiguration, "plain.example.com"), "default-plain-rule0-match0")
+	if got, want := plainRoute.GetRoute().GetCluster(), clusterName("backend", 80); got != want {
+		t.Errorf("plain route cluster = %q, want %q", got, want)
+	}
+	if len(plainRoute.GetRoute().GetHashPolicy()) != 0 {
+		t.Errorf("plain route hash policies = %v, want none", plainRoute.GetRoute().GetHashPolicy())
+	}
+	if got := findCluster(t, resources, clusterName("backend", 80)).LbPolicy; got != clusterv3.Cluster_ROUND_ROBIN {
+		t.Errorf("plain cluster LB policy = %v, want ROUND_ROBIN", got)
+	}
+}
+
+// TestTranslateHTTPRoutePrecedence checks that the routes of a virtual host are ordered by
+// the Gateway API precedence rather than by their order in the HTTPRoutes: exact paths
+// first, then longer prefixes, then more specific matches of the same prefix, and finally
+// the older route.
+func TestTranslateHTTPRoutePrecedence(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.CreationTimestamp = metav1.NewTime(time.Unix(200, 0))
+	prefix := pathMatch(gatewayv1.PathMatchPathPrefix, "/api")
+	withMethod := pathMatch(gatewayv1.PathMatchPathPrefix, "/api")
+	withMethod.Method = ptr(gatewayv1.HTTPMethodGet)
+	withHeader := pathMatch(gatewayv1.PathMatchPathPrefix, "/api")
+	withHeader.Headers = []gatewayv1.HTTPHeaderMatch{{Name: "x-env", Value: "canary"}}
+	route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{
+		prefix,
+		withHeader,
+		withMethod,
+		pathMatch(gatewayv1.PathMatchPathPrefix, "/api/v1"),
+		pathMatch(gatewayv1.PathMatchExact, "/api"),
+	}
+	// An older route with the same prefix wins the tie.
+	older := testHTTPRoute("older", "gw", testBackendRef("backend", 80))
+	older.CreationTimestamp = metav1.NewTime(time.Unix(100, 0))
+	older.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/api")}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route, older)
+	resources := translateGateway(t, tl, gw)
+
+	got := routeNames(findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"))
+	want := []string{
+		"default-web-rule0-match4",   // exact /api
+		"default-web-rule0-match3",   // prefix /api/v1
+		"default-web-rule0-match2",   // prefix /api with a method
+		"default-web-rule0-match1",   // prefix /api with a header
+		"default-older-rule0-match0", // prefix /api of the older route
+		"default-web-rule0-match0",   // prefix /api
+		"gw-vh-80-*-not-found",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+}
+
+func TestTranslateRequestRedirectStatusCode(t *testing.T) {
+	for _, tc := range []struct {
+		statusCode *int
+		want       routev3.RedirectAction_RedirectResponseCode
+		wantErr    bool
+	}{
+		{statusCode: nil, want: routev3.RedirectAction_FOUND},
+		{statusCode: ptr(301), want: routev3.RedirectAction_MOVED_PERMANENTLY},
+		{statusCode: ptr(302), want: routev3.RedirectAction_FOUND},
+		{statusCode: ptr(303), want: routev3.RedirectAction_SEE_OTHER},
+		{statusCode: ptr(307), want: routev3.RedirectAction_TEMPORARY_REDIRECT},
+		{statusCode: ptr(308), want: routev3.RedirectAction_PERMANENT_REDIRECT},
+		{statusCode: ptr(304), wantErr: true},
+		{statusCode: ptr(418), wantErr: true},
+	} {
+		name := "default"
+		if tc.statusCode != nil {
+			name = strconv.Itoa(*tc.statusCode)
+		}
+		t.Run(name, func(t *testing.T) {
+			redirect := &gatewayv1.HTTPRequestRedirectFilter{StatusCode: tc.statusCode}
+			redirectAction, err := translateRequestRedirect(redirect, gatewayv1.HTTPRouteMatch{})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("translateRequestRedirect() error = %v, want error %v", err, tc.wantErr)
+			}
+			if err == nil && redirectAction.ResponseCode != tc.want {
+				t.Errorf("response code = %v, want %v", redirectAction.ResponseCode, tc.want)
+			}
+		})
+	}
+}
+
+// A redirect with an unsupported status code is reported on the route instead of silently
+// redirecting with another code.
+func TestTranslateHTTPRouteInvalidRedirectStatusCode(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw")
+	route.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{StatusCode: ptr(418)},
+	}}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, route)
+	resources, _, routeStatuses, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+	for _, vh := range findRouteConfiguration(t, resources, "route-80").VirtualHosts {
+		for _, envoyRoute := range vh.Routes {
+			if envoyRoute.GetRedirect() != nil {
+				t.Errorf("route %s redirects with %v", envoyRoute.Name, envoyRoute.GetRedirect().ResponseCode)
+			}
+		}
+	}
+	parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "web"}]
+	if len(parents) != 1 {
+		t.Fatalf("got %d parent statuses, want 1", len(parents))
+	}
+	condition := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != string(gatewayv1.RouteReasonUnsupportedValue) {
+		t.Errorf("ResolvedRefs = %v, want False/%s", condition, gatewayv1.RouteReasonUnsupportedValue)
+	}
+}
@@ -0,0 +1,130 @@
+package translator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The annotations of a Service that select the load balancing policy of its clusters.
+const (
+	lbPolicyAnnotation      = "gateway.xds/lb-policy"
+	lbChoiceCountAnnotation = "gateway.xds/lb-choice-count"
+	// The slow start annotations ramp up the traffic to new endpoints over the window,
+	// faster or slower with an aggression above or below 1. They require the ROUND_ROBIN
+	// or LEAST_REQUEST load balancing policy.
+	slowStartWindowAnnotation     = "gateway.xds/slow-start-window"
+	slowStartAggressionAnnotation = "gateway.xds/slow-start-aggression"
+)
+
+// slowStartAggressionRuntimeKey is the runtime key that can override the slow start
+// aggression of the clusters.
+const slowStartAggressionRuntimeKey = "upstream.slow_start_aggression"
+
+// lbPolicies are the supported load balancing policies by name.
+var lbPolicies = map[string]clusterv3.Cluster_LbPolicy{
+	"ROUND_ROBIN":   clusterv3.Cluster_ROUND_ROBIN,
+	"LEAST_REQUEST": clusterv3.Cluster_LEAST_REQUEST,
+	"RANDOM":        clusterv3.Cluster_RANDOM,
+	"RING_HASH":     clusterv3.Cluster_RING_HASH,
+	"MAGLEV":        clusterv3.Cluster_MAGLEV,
+}
+
+// parseLBPolicy returns the load balancing policy with the given name. An empty name
+// selects round robin, Envoy's default.
+func parseLBPolicy(name string) (clusterv3.Cluster_LbPolicy, error) {
+	if name == "" {
+		return clusterv3.Cluster_ROUND_ROBIN, nil
+	}
+	lbPolicy, ok := lbPolicies[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown load balancing policy %q, must be one of ROUND_ROBIN, LEAST_REQUEST, RANDOM, RING_HASH or MAGLEV", name)
+	}
+	return lbPolicy, nil
+}
+
+// setLBPolicy sets the load balancing policy of a cluster for the Service. The policy of
+// the Service's annotation takes precedence over the default policy.
+func setLBPolicy(cluster *clusterv3.Cluster, defaultPolicy string, service *corev1.Service) error {
+	name := defaultPolicy
+	if value, ok := service.Annotations[lbPolicyAnnotation]; ok {
+		name = value
+	}
+	lbPolicy, err := parseLBPolicy(name)
+	if err != nil {
+		return fmt.Errorf("service %s/%s: %w", service.Namespace, service.Name, err)
+	}
+	cluster.LbPolicy = lbPolicy
+
+	choiceCount, hasChoiceCount, err := uint32Annotation(service, lbChoiceCountAnnotation)
+	if err != nil {
+		return err
+	}
+	if hasChoiceCount {
+		if lbPolicy != clusterv3.Cluster_LEAST_REQUEST {
+			return fmt.Errorf("annotation %s of service %s/%s requires the LEAST_REQUEST load balancing policy", lbChoiceCountAnnotation, service.Namespace, service.Name)
+		}
+		if choiceCount < 2 {
+			return fmt.Errorf("annotation %s of service %s/%s: choice count must be at least 2", lbChoiceCountAnnotation, service.Namespace, service.Name)
+		}
+	}
+
+	slowStartConfig, err := buildSlowStartConfig(service)
+	if err != nil {
+		return err
+	}
+	if slowStartConfig != nil && lbPolicy != clusterv3.Cluster_ROUND_ROBIN && lbPolicy != clusterv3.Cluster_LEAST_REQUEST {
+		return fmt.Errorf("annotation %s of service %s/%s requires the ROUND_ROBIN or LEAST_REQUEST load balancing policy", slowStartWindowAnnotation, service.Namespace, service.Name)
+	}
+
+	switch {
+	case lbPolicy == clusterv3.Cluster_LEAST_REQUEST && (hasChoiceCount || slowStartConfig != nil):
+		leastRequestLbConfig := &clusterv3.Cluster_LeastRequestLbConfig{
+			SlowStartConfig: slowStartConfig,
+		}
+		if hasChoiceCount {
+			leastRequestLbConfig.ChoiceCount = wrapperspb.UInt32(choiceCount)
+		}
+		cluster.LbConfig = &clusterv3.Cluster_LeastRequestLbConfig_{LeastRequestLbConfig: leastRequestLbConfig}
+	case lbPolicy == clusterv3.Cluster_ROUND_ROBIN && slowStartConfig != nil:
+		cluster.LbConfig = &clusterv3.Cluster_RoundRobinLbConfig_{
+			RoundRobinLbConfig: &clusterv3.Cluster_RoundRobinLbConfig{SlowStartConfig: slowStartConfig},
+		}
+	}
+	return nil
+}
+
+// buildSlowStartConfig builds the slow start config of a cluster for the Service from its
+// annotations. It returns nil if the Service has no slow start window, so that new endpoints
+// get their full share of the traffic at once.
+func buildSlowStartConfig(service *corev1.Service) (*clusterv3.Cluster_SlowStartConfig, error) {
+	window, ok, err := durationAnnotation(service, slowStartWindowAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	aggressionValue, hasAggression := service.Annotations[slowStartAggressionAnnotation]
+	if !ok {
+		if hasAggression {
+			return nil, fmt.Errorf("annotation %s of service %s/%s requires the %s annotation", slowStartAggressionAnnotation, service.Namespace, service.Name, slowStartWindowAnnotation)
+		}
+		return nil, nil
+	}
+
+	slowStartConfig := &clusterv3.Cluster_SlowStartConfig{SlowStartWindow: window}
+	if hasAggression {
+		aggression, err := strconv.ParseFloat(aggressionValue, 64)
+		if err != nil || math.IsNaN(aggression) || math.IsInf(aggression, 0) || aggression <= 0 {
+			return nil, fmt.Errorf("annotation %s of service %s/%s: aggression %q must be a positive number", slowStartAggressionAnnotation, service.Namespace, service.Name, aggressionValue)
+		}
+		slowStartConfig.Aggression = &corev3.RuntimeDouble{
+			DefaultValue: aggression,
+			RuntimeKey:   slowStartAggressionRuntimeKey,
+		}
+	}
+	return slowStartConfig, nil
+}
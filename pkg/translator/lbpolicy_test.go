@@ -0,0 +1,140 @@
+package translator
+
+import (
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTranslateLBPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		defaultPolicy   string
+		annotations     map[string]string
+		wantPolicy      clusterv3.Cluster_LbPolicy
+		wantChoiceCount uint32
+	}{
+		{name: "unset", wantPolicy: clusterv3.Cluster_ROUND_ROBIN},
+		{name: "default policy", defaultPolicy: "RANDOM", wantPolicy: clusterv3.Cluster_RANDOM},
+		{name: "annotation overrides default", defaultPolicy: "RANDOM", annotations: map[string]string{lbPolicyAnnotation: "MAGLEV"}, wantPolicy: clusterv3.Cluster_MAGLEV},
+		{
+			name:            "least request with choice count",
+			annotations:     map[string]string{lbPolicyAnnotation: "LEAST_REQUEST", lbChoiceCountAnnotation: "5"},
+			wantPolicy:      clusterv3.Cluster_LEAST_REQUEST,
+			wantChoiceCount: 5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, Options{DefaultLBPolicy: tc.defaultPolicy}, service)
+
+			if cluster.LbPolicy != tc.wantPolicy {
+				t.Errorf("load balancing policy = %v, want %v", cluster.LbPolicy, tc.wantPolicy)
+			}
+			choiceCount := cluster.GetLeastRequestLbConfig().GetChoiceCount()
+			if tc.wantChoiceCount == 0 {
+				if choiceCount != nil {
+					t.Errorf("choice count = %v, want Envoy's default", choiceCount.GetValue())
+				}
+			} else if choiceCount.GetValue() != tc.wantChoiceCount {
+				t.Errorf("choice count = %v, want %d", choiceCount, tc.wantChoiceCount)
+			}
+		})
+	}
+}
+
+func TestSetLBPolicyInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		defaultPolicy string
+		annotations   map[string]string
+	}{
+		{name: "unknown default policy", defaultPolicy: "FASTEST"},
+		{name: "unknown annotated policy", annotations: map[string]string{lbPolicyAnnotation: "least_request"}},
+		{name: "choice count without least request", annotations: map[string]string{lbChoiceCountAnnotation: "3"}},
+		{name: "choice count below 2", annotations: map[string]string{lbPolicyAnnotation: "LEAST_REQUEST", lbChoiceCountAnnotation: "1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := &clusterv3.Cluster{}
+			if err := setLBPolicy(cluster, tc.defaultPolicy, service); err == nil {
+				t.Errorf("setLBPolicy() = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestTranslateSlowStart(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		annotations    map[string]string
+		wantAggression *corev3.RuntimeDouble
+	}{
+		{name: "round robin", annotations: map[string]string{slowStartWindowAnnotation: "30s"}},
+		{
+			name: "least request with aggression",
+			annotations: map[string]string{
+				lbPolicyAnnotation:            "LEAST_REQUEST",
+				slowStartWindowAnnotation:     "30s",
+				slowStartAggressionAnnotation: "1.5",
+			},
+			wantAggression: &corev3.RuntimeDouble{DefaultValue: 1.5, RuntimeKey: slowStartAggressionRuntimeKey},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, Options{}, service)
+
+			slowStartConfig := cluster.GetRoundRobinLbConfig().GetSlowStartConfig()
+			if cluster.LbPolicy == clusterv3.Cluster_LEAST_REQUEST {
+				slowStartConfig = cluster.GetLeastRequestLbConfig().GetSlowStartConfig()
+			}
+			if slowStartConfig == nil {
+				t.Fatalf("%v cluster has no slow start config", cluster.LbPolicy)
+			}
+			if got := slowStartConfig.GetSlowStartWindow().AsDuration(); got != 30*time.Second {
+				t.Errorf("slow start window = %s, want 30s", got)
+			}
+			if !proto.Equal(slowStartConfig.Aggression, tc.wantAggression) {
+				t.Errorf("slow start aggression = %v, want %v", slowStartConfig.Aggression, tc.wantAggression)
+			}
+		})
+	}
+
+	// New endpoints get their full share of the traffic at once by default.
+	if cluster := translateServiceCluster(t, Options{}, testService("backend", 80)); cluster.LbConfig != nil {
+		t.Errorf("LB config = %v, want no slow start", cluster.LbConfig)
+	}
+}
+
+func TestBuildSlowStartConfigInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{name: "aggression without window", annotations: map[string]string{slowStartAggressionAnnotation: "2"}},
+		{name: "zero aggression", annotations: map[string]string{slowStartWindowAnnotation: "30s", slowStartAggressionAnnotation: "0"}},
+		{name: "invalid window", annotations: map[string]string{slowStartWindowAnnotation: "a while"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			if slowStartConfig, err := buildSlowStartConfig(service); err == nil {
+				t.Errorf("buildSlowStartConfig() = %v, want an error", slowStartConfig)
+			}
+		})
+	}
+
+	// Only the round robin and least request policies support slow start.
+	service := testService("backend", 80)
+	service.Annotations = map[string]string{lbPolicyAnnotation: "RANDOM", slowStartWindowAnnotation: "30s"}
+	if err := setLBPolicy(&clusterv3.Cluster{}, "", service); err == nil {
+		t.Errorf("setLBPolicy() of a RANDOM cluster with slow start = nil, want an error")
+	}
+}
@@ -8,18 +8,21 @@ import (
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	corsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	routerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	proxyprotocolv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
 	tlsinspector "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/tls_inspector/v3"
 	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	tcpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
-	udpproxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
@@ -57,25 +60,37 @@ func (t *Translator) validateListeners(gateway *gatewayv1.Gateway) map[gatewayv1
 	}
 
 	for _, listenersOnPort := range listenersByPort {
-		// Rule: A TCP listener cannot share a port with HTTP/HTTPS/TLS listeners.
+		// Rule: A TCP listener cannot share a port with HTTP/HTTPS/TLS listeners, and an
+		// HTTP listener cannot share a port with HTTPS/TLS listeners. Plaintext HTTP
+		// connections cannot be told apart from the TLS connections by SNI.
 		hasTCP := false
-		hasHTTPTLS := false
+		hasHTTP := false
+		hasTLS := false
 		for _, listener := range listenersOnPort {
-			if listener.Protocol == gatewayv1.TCPProtocolType || listener.Protocol == gatewayv1.UDPProtocolType {
+			switch listener.Protocol {
+			case gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType:
 				hasTCP = true
-			}
-			if listener.Protocol == gatewayv1.HTTPProtocolType || listener.Protocol == gatewayv1.HTTPSProtocolType || listener.Protocol == gatewayv1.TLSProtocolType {
-				hasHTTPTLS = true
+			case gatewayv1.HTTPProtocolType:
+				hasHTTP = true
+			case gatewayv1.HTTPSProtocolType, gatewayv1.TLSProtocolType:
+				hasTLS = true
 			}
 		}
 
-		if hasTCP && hasHTTPTLS {
+		var protocolConflict string
+		switch {
+		case hasTCP && (hasHTTP || hasTLS):
+			protocolConflict = "Protocol conflict: TCP/UDP listeners cannot share a port with HTTP/HTTPS/TLS listeners."
+		case hasHTTP && hasTLS:
+			protocolConflict = "Protocol conflict: HTTP listeners cannot share a port with HTTPS/TLS listeners."
+		}
+		if protocolConflict != "" {
 			for _, listener := range listenersOnPort {
 				setListenerCondition(listenerConditions, listener.Name, metav1.Condition{
 					Type:    string(gatewayv1.ListenerConditionConflicted),
 					Status:  metav1.ConditionTrue,
 					Reason:  string(gatewayv1.ListenerReasonProtocolConflict),
-					Message: "Protocol conflict: TCP/UDP listeners cannot share a port with HTTP/HTTPS/TLS listeners.",
+					Message: protocolConflict,
 				})
 			}
 			continue // Skip further checks for this port
@@ -107,6 +122,24 @@ func (t *Translator) validateListeners(gateway *gatewayv1.Gateway) map[gatewayv1
 		}
 	}
 
+	// Rule: Listener names must be unique. The statuses of listeners are keyed by name, so
+	// listeners sharing a name cannot be told apart and are all excluded. The Gateway API has
+	// no reason for duplicate names, they are reported as a protocol conflict.
+	listenersByName := make(map[gatewayv1.SectionName]int)
+	for _, listener := range gateway.Spec.Listeners {
+		listenersByName[listener.Name]++
+	}
+	for name, count := range listenersByName {
+		if count > 1 {
+			setListenerCondition(listenerConditions, name, metav1.Condition{
+				Type:    string(gatewayv1.ListenerConditionConflicted),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(gatewayv1.ListenerReasonProtocolConflict),
+				Message: fmt.Sprintf("Listener name '%s' is used by %d listeners.", name, count),
+			})
+		}
+	}
+
 	for _, listener := range gateway.Spec.Listeners {
 		// If a listener is already conflicted, we don't need to check its secrets.
 		if meta.IsStatusConditionTrue(listenerConditions[listener.Name], string(gatewayv1.ListenerConditionConflicted)) {
@@ -150,26 +183,14 @@ func (t *Translator) validateListeners(gateway *gatewayv1.Gateway) map[gatewayv1
 				secretNamespace = string(*certRef.Namespace)
 			}
 
-			if secretNamespace != gateway.Namespace {
-				from := gatewayv1beta1.ReferenceGrantFrom{
-					Group:     gatewayv1.GroupName,
-					Kind:      "Gateway",
-					Namespace: gatewayv1.Namespace(gateway.Namespace),
-				}
-				to := gatewayv1beta1.ReferenceGrantTo{
-					Group: "", // Core group for Secret
-					Kind:  "Secret",
-					Name:  &certRef.Name,
-				}
-				if !isCrossNamespaceRefAllowed(from, to, secretNamespace, t.referenceGrantLister) {
-					setListenerCondition(listenerConditions, listener.Name, metav1.Condition{
-						Type:    string(gatewayv1.ListenerConditionResolvedRefs),
-						Status:  metav1.ConditionFalse,
-						Reason:  string(gatewayv1.ListenerReasonRefNotPermitted),
-						Message: fmt.Sprintf("reference to Secret %s/%s not permitted by any ReferenceGrant", secretNamespace, certRef.Name),
-					})
-					break
-				}
+			if !t.isCertificateRefAllowed(gateway, secretNamespace, certRef) {
+				setListenerCondition(listenerConditions, listener.Name, metav1.Condition{
+					Type:    string(gatewayv1.ListenerConditionResolvedRefs),
+					Status:  metav1.ConditionFalse,
+					Reason:  string(gatewayv1.ListenerReasonRefNotPermitted),
+					Message: fmt.Sprintf("reference to Secret %s/%s not permitted by any ReferenceGrant", secretNamespace, certRef.Name),
+				})
+				break
 			}
 
 			secret, err := t.secretLister.Secrets(secretNamespace).Get(string(certRef.Name))
@@ -207,7 +228,9 @@ func (t *Translator) validateListeners(gateway *gatewayv1.Gateway) map[gatewayv1
 	return listenerConditions
 }
 
-func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway, lis gatewayv1.Listener, virtualHosts []*routev3.VirtualHost, routeName string) (*listener.FilterChain, error) {
+// translateListenerToFilterChain builds the filter chain of a listener. The SDS secrets referenced
+// by the TLS context of HTTPS and TLS listeners are added to envoySecrets.
+func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway, lis gatewayv1.Listener, virtualHosts []*routev3.VirtualHost, routeName string, envoySecrets map[string]envoyproxytypes.Resource) (*listener.FilterChain, error) {
 	var filterChain *listener.FilterChain
 
 	switch lis.Protocol {
@@ -215,12 +238,22 @@ func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway,
 		routerProto := &routerv3.Router{}
 		routerAny, err := anypb.New(routerProto)
 		if err != nil {
-			klog.Errorf("Failed to marshal router config: %v", err)
+			return nil, fmt.Errorf("failed to marshal router config: %w", err)
+		}
+		// The CORS filter only acts on routes that configure a CORS policy.
+		corsAny, err := anypb.New(&corsv3.Cors{})
+		if err != nil {
+			return nil, err
+		}
+
+		accessLogs, err := buildAccessLogs(t.options)
+		if err != nil {
 			return nil, err
 		}
 
 		hcmConfig := &hcm.HttpConnectionManager{
 			StatPrefix: string(lis.Name),
+			AccessLog:  accessLogs,
 			RouteSpecifier: &hcm.HttpConnectionManager_Rds{
 				Rds: &hcm.Rds{
 					ConfigSource: &corev3.ConfigSource{
@@ -230,13 +263,124 @@ func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway,
 					RouteConfigName: routeName,
 				},
 			},
-			HttpFilters: []*hcm.HttpFilter{{
-				Name: wellknown.Router,
+		}
+		// The address recovered from the PROXY protocol header is the address of the client,
+		// which Envoy only trusts and appends to X-Forwarded-For with use_remote_address.
+		if usesProxyProtocol(gateway) {
+			hcmConfig.UseRemoteAddress = wrapperspb.Bool(true)
+		}
+		// By default Envoy overwrites the server header of every response after the route's
+		// response headers are applied, which would undo a ResponseHeaderModifier that sets or
+		// removes it. Passing the header through leaves it under the control of the routes.
+		if routesModifyServerHeader(virtualHosts) {
+			hcmConfig.ServerHeaderTransformation = hcm.HttpConnectionManager_PASS_THROUGH
+		}
+		// Routes can only enable the upgrades that the connection manager lists.
+		if t.options.WebSockets || routesUseWebSockets(virtualHosts) {
+			hcmConfig.UpgradeConfigs = buildWebSocketUpgradeConfigs(t.options.WebSockets)
+		}
+		if t.options.TracingCollector != "" {
+			hcmConfig.Tracing, err = buildTracing(gateway, t.options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, &hcm.HttpFilter{
+			Name: wellknown.CORS,
+			ConfigType: &hcm.HttpFilter_TypedConfig{
+				TypedConfig: corsAny,
+			},
+		})
+		// Responses are compressed after all other filters have processed them.
+		if t.options.Compression != "" {
+			compressorFilter, err := buildCompressorFilter(t.options.Compression)
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, compressorFilter)
+		}
+		// gRPC-Web requests are translated to gRPC right after their CORS preflights are answered.
+		if routesUseFilter(virtualHosts, wellknown.GRPCWeb) {
+			grpcWebFilter, err := buildGRPCWebFilter()
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, grpcWebFilter)
+		}
+		// Headers are copied into the dynamic metadata before any filter that may read it.
+		if routesUseFilter(virtualHosts, headerToMetadataFilterName) {
+			headerToMetadataFilter, err := buildHeaderToMetadataFilter()
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, headerToMetadataFilter)
+		}
+		// Requests are authorized after CORS preflights are answered, but before they consume rate limits.
+		if t.options.ExtAuthzService != "" {
+			extAuthzAny, err := buildExtAuthzFilter(t.options)
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, &hcm.HttpFilter{
+				Name: wellknown.HTTPExternalAuthorization,
 				ConfigType: &hcm.HttpFilter_TypedConfig{
-					TypedConfig: routerAny,
+					TypedConfig: extAuthzAny,
 				},
-			}},
+			})
+		}
+		// Unlike the CORS filter, the local rate limit filter is only added if a route uses it.
+		if routesUseFilter(virtualHosts, localRateLimitFilterName) {
+			localRateLimitAny, err := buildLocalRateLimitFilter()
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, &hcm.HttpFilter{
+				Name: localRateLimitFilterName,
+				ConfigType: &hcm.HttpFilter_TypedConfig{
+					TypedConfig: localRateLimitAny,
+				},
+			})
+		}
+		if t.options.RateLimitService != "" && routesUseRateLimits(virtualHosts) {
+			rateLimitAny, err := buildRateLimitFilter(t.options.RateLimitDomain)
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, &hcm.HttpFilter{
+				Name: wellknown.HTTPRateLimit,
+				ConfigType: &hcm.HttpFilter_TypedConfig{
+					TypedConfig: rateLimitAny,
+				},
+			})
+		}
+		// Faults are injected into the requests that passed all other filters.
+		if routesUseFilter(virtualHosts, wellknown.Fault) {
+			faultAny, err := anypb.New(&faultv3.HTTPFault{})
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, &hcm.HttpFilter{
+				Name: wellknown.Fault,
+				ConfigType: &hcm.HttpFilter_TypedConfig{
+					TypedConfig: faultAny,
+				},
+			})
 		}
+		// Requests are only buffered once they passed all other filters.
+		if t.options.BufferMaxRequestBytes > 0 || routesUseFilter(virtualHosts, wellknown.Buffer) {
+			bufferFilter, err := buildBufferFilter(t.options.BufferMaxRequestBytes)
+			if err != nil {
+				return nil, err
+			}
+			hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, bufferFilter)
+		}
+		// The router must be the last filter.
+		hcmConfig.HttpFilters = append(hcmConfig.HttpFilters, &hcm.HttpFilter{
+			Name: wellknown.Router,
+			ConfigType: &hcm.HttpFilter_TypedConfig{
+				TypedConfig: routerAny,
+			},
+		})
 		hcmAny, err := anypb.New(hcmConfig)
 		if err != nil {
 			return nil, err
@@ -251,13 +395,13 @@ func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway,
 			}},
 		}
 
-	case gatewayv1.TCPProtocolType, gatewayv1.TLSProtocolType:
-		// TCP and TLS listeners require a TCP proxy filter.
+	case gatewayv1.TLSProtocolType:
+		// TLS listeners require a TCP proxy filter.
 		// We'll assume for now that routes for these are not supported and it's a direct pass-through.
 		tcpProxy := &tcpproxyv3.TcpProxy{
 			StatPrefix: string(lis.Name),
 			ClusterSpecifier: &tcpproxyv3.TcpProxy_Cluster{
-				Cluster: "some_static_cluster", // This needs to be determined from a TCPRoute/TLSRoute
+				Cluster: "some_static_cluster", // This needs to be determined from a TLSRoute
 			},
 		}
 		tcpProxyAny, err := anypb.New(tcpProxy)
@@ -273,25 +417,6 @@ func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway,
 			}},
 		}
 
-	case gatewayv1.UDPProtocolType:
-		udpProxy := &udpproxy.UdpProxyConfig{
-			StatPrefix: string(lis.Name),
-			RouteSpecifier: &udpproxy.UdpProxyConfig_Cluster{
-				Cluster: "some_udp_cluster", // This needs to be determined from a UDPRoute
-			},
-		}
-		udpProxyAny, err := anypb.New(udpProxy)
-		if err != nil {
-			return nil, err
-		}
-		filterChain = &listener.FilterChain{
-			Filters: []*listener.Filter{{
-				Name: "envoy.filters.udp_listener.udp_proxy",
-				ConfigType: &listener.Filter_TypedConfig{
-					TypedConfig: udpProxyAny,
-				},
-			}},
-		}
 	}
 
 	// Add SNI matching for applicable protocols
@@ -302,7 +427,7 @@ func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway,
 			}
 		}
 		// Configure TLS context
-		tlsContext, err := t.buildDownstreamTLSContext(context.Background(), gateway, lis)
+		tlsContext, err := t.buildDownstreamTLSContext(context.Background(), gateway, lis, envoySecrets)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build TLS context for listener %s: %w", lis.Name, err)
 		}
@@ -319,7 +444,10 @@ func (t *Translator) translateListenerToFilterChain(gateway *gatewayv1.Gateway,
 	return filterChain, nil
 }
 
-func (t *Translator) buildDownstreamTLSContext(ctx context.Context, gateway *gatewayv1.Gateway, lis gatewayv1.Listener) (*anypb.Any, error) {
+// buildDownstreamTLSContext builds a TLS context that fetches the certificates of the listener
+// over SDS. One SDS secret is added to envoySecrets per certificateRef, and one for the CA of
+// the client certificates if the listener requires them.
+func (t *Translator) buildDownstreamTLSContext(ctx context.Context, gateway *gatewayv1.Gateway, lis gatewayv1.Listener, envoySecrets map[string]envoyproxytypes.Resource) (*anypb.Any, error) {
 	if lis.TLS == nil {
 		return nil, nil
 	}
@@ -327,11 +455,23 @@ func (t *Translator) buildDownstreamTLSContext(ctx context.Context, gateway *gat
 		return nil, fmt.Errorf("TLS is configured, but no certificate refs are provided")
 	}
 
+	alpnProtocols, err := listenerALPNProtocols(lis)
+	if err != nil {
+		return nil, err
+	}
+	tlsParams, err := listenerTLSParameters(lis, t.options)
+	if err != nil {
+		return nil, err
+	}
 	tlsContext := &tlsv3.DownstreamTlsContext{
 		CommonTlsContext: &tlsv3.CommonTlsContext{
-			TlsCertificates: []*tlsv3.TlsCertificate{},
+			TlsParams:     tlsParams,
+			AlpnProtocols: alpnProtocols,
 		},
 	}
+	// The secrets are only added once all certificateRefs resolved, so that a listener
+	// that fails to be programmed doesn't leave any secrets behind.
+	var secrets []*tlsv3.Secret
 
 	for _, certRef := range lis.TLS.CertificateRefs {
 		if certRef.Group != nil && *certRef.Group != "" {
@@ -347,10 +487,12 @@ func (t *Translator) buildDownstreamTLSContext(ctx context.Context, gateway *gat
 		}
 
 		secretName := string(certRef.Name)
+		// Fail closed: a Secret in another namespace is only read if a ReferenceGrant allows it.
+		if !t.isCertificateRefAllowed(gateway, secretNamespace, certRef) {
+			return nil, fmt.Errorf("reference to Secret %s/%s not permitted by any ReferenceGrant", secretNamespace, secretName)
+		}
 		secret, err := t.secretLister.Secrets(secretNamespace).Get(secretName)
 		if err != nil {
-			// Per the spec, if the grant was missing, we must not reveal that the secret doesn't exist.
-			// The error from the grant check above takes precedence.
 			return nil, fmt.Errorf("failed to get secret %s/%s: %w", secretNamespace, secretName, err)
 		}
 
@@ -358,16 +500,123 @@ func (t *Translator) buildDownstreamTLSContext(ctx context.Context, gateway *gat
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert secret to tls certificate: %v", err)
 		}
-		tlsContext.CommonTlsContext.TlsCertificates = append(tlsContext.CommonTlsContext.TlsCertificates, tlsCert)
+		name := sdsSecretName(secretNamespace, secretName)
+		secrets = append(secrets, &tlsv3.Secret{
+			Name: name,
+			Type: &tlsv3.Secret_TlsCertificate{TlsCertificate: tlsCert},
+		})
+		tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs = append(tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs, &tlsv3.SdsSecretConfig{
+			Name: name,
+			SdsConfig: &corev3.ConfigSource{
+				ResourceApiVersion:    corev3.ApiVersion_V3,
+				ConfigSourceSpecifier: &corev3.ConfigSource_Ads{Ads: &corev3.AggregatedConfigSource{}},
+			},
+		})
+	}
+
+	clientValidationSecret, err := t.buildClientValidationSecret(gateway, lis)
+	if err != nil {
+		return nil, err
+	}
+	if clientValidationSecret != nil {
+		secrets = append(secrets, clientValidationSecret)
+		tlsContext.RequireClientCertificate = wrapperspb.Bool(true)
+		tlsContext.CommonTlsContext.ValidationContextType = &tlsv3.CommonTlsContext_ValidationContextSdsSecretConfig{
+			ValidationContextSdsSecretConfig: &tlsv3.SdsSecretConfig{
+				Name: clientValidationSecret.Name,
+				SdsConfig: &corev3.ConfigSource{
+					ResourceApiVersion:    corev3.ApiVersion_V3,
+					ConfigSourceSpecifier: &corev3.ConfigSource_Ads{Ads: &corev3.AggregatedConfigSource{}},
+				},
+			},
+		}
 	}
 
 	any, err := anypb.New(tlsContext)
 	if err != nil {
 		return nil, err
 	}
+	for _, secret := range secrets {
+		envoySecrets[secret.Name] = secret
+	}
 	return any, nil
 }
 
+// sdsSecretName returns the name of the SDS secret for a Kubernetes Secret.
+func sdsSecretName(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// isCertificateRefAllowed reports whether the Gateway may reference the certificate Secret
+// in secretNamespace. References to another namespace require a ReferenceGrant.
+func (t *Translator) isCertificateRefAllowed(gateway *gatewayv1.Gateway, secretNamespace string, certRef gatewayv1.SecretObjectReference) bool {
+	if secretNamespace == gateway.Namespace {
+		return true
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{
+		Group:     gatewayv1.GroupName,
+		Kind:      "Gateway",
+		Namespace: gatewayv1.Namespace(gateway.Namespace),
+	}
+	to := gatewayv1beta1.ReferenceGrantTo{
+		Group: "", // Core group for Secret
+		Kind:  "Secret",
+		Name:  &certRef.Name,
+	}
+	allowed, err := isCrossNamespaceRefAllowed(from, to, secretNamespace, t.referenceGrantLister)
+	if err != nil {
+		t.logger().Error("failed to check certificate reference", "namespace", gateway.Namespace, "name", gateway.Name,
+			"secretNamespace", secretNamespace, "secret", certRef.Name, "err", err)
+		return false
+	}
+	return allowed
+}
+
+// hasHTTPSListenerForHostname reports whether the Gateway has an HTTPS listener with the
+// given hostname. A nil hostname only matches HTTPS listeners without a hostname.
+func hasHTTPSListenerForHostname(gateway *gatewayv1.Gateway, hostname *gatewayv1.Hostname) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Protocol != gatewayv1.HTTPSProtocolType {
+			continue
+		}
+		if listener.Hostname == nil || hostname == nil {
+			if listener.Hostname == hostname {
+				return true
+			}
+			continue
+		}
+		if *listener.Hostname == *hostname {
+			return true
+		}
+	}
+	return false
+}
+
+// buildHTTPSRedirectVirtualHost builds a virtual host that permanently redirects every request
+// for the listener hostname to HTTPS.
+func buildHTTPSRedirectVirtualHost(gateway *gatewayv1.Gateway, port gatewayv1.PortNumber, hostname *gatewayv1.Hostname) *routev3.VirtualHost {
+	domain := "*"
+	if hostname != nil && *hostname != "" {
+		domain = string(*hostname)
+	}
+	return &routev3.VirtualHost{
+		Name:    fmt.Sprintf("%s-vh-%d-%s", gateway.Name, port, domain),
+		Domains: []string{domain},
+		Routes: []*routev3.Route{{
+			Name: fmt.Sprintf("%s-https-redirect-%d-%s", gateway.Name, port, domain),
+			Match: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+			},
+			Action: &routev3.Route_Redirect{
+				Redirect: &routev3.RedirectAction{
+					SchemeRewriteSpecifier: &routev3.RedirectAction_HttpsRedirect{HttpsRedirect: true},
+					ResponseCode:           routev3.RedirectAction_MOVED_PERMANENTLY,
+				},
+			},
+		}},
+	}
+}
+
 func validateSecretCertificate(secret *corev1.Secret) error {
 	privateKey, ok := secret.Data[corev1.TLSPrivateKeyKey]
 	if !ok {
@@ -436,14 +685,24 @@ func createEnvoyAddress(port uint32) *corev3.Address {
 	}
 }
 
-func createListenerFilters() []*listener.ListenerFilter {
-	tlsInspectorConfig, _ := anypb.New(&tlsinspector.TlsInspector{})
-	return []*listener.ListenerFilter{
-		{
-			Name: wellknown.TlsInspector,
+// createListenerFilters returns the listener filters of the TCP listeners of the Gateway. The
+// PROXY protocol header is decoded first, as the TLS inspector expects the TLS handshake.
+func createListenerFilters(gateway *gatewayv1.Gateway) []*listener.ListenerFilter {
+	var listenerFilters []*listener.ListenerFilter
+	if usesProxyProtocol(gateway) {
+		proxyProtocolConfig, _ := anypb.New(&proxyprotocolv3.ProxyProtocol{})
+		listenerFilters = append(listenerFilters, &listener.ListenerFilter{
+			Name: wellknown.ProxyProtocol,
 			ConfigType: &listener.ListenerFilter_TypedConfig{
-				TypedConfig: tlsInspectorConfig,
+				TypedConfig: proxyProtocolConfig,
 			},
-		},
+		})
 	}
+	tlsInspectorConfig, _ := anypb.New(&tlsinspector.TlsInspector{})
+	return append(listenerFilters, &listener.ListenerFilter{
+		Name: wellknown.TlsInspector,
+		ConfigType: &listener.ListenerFilter_TypedConfig{
+			TypedConfig: tlsInspectorConfig,
+		},
+	})
 }
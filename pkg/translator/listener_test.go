@@ -0,0 +1,267 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// listenerCondition returns the condition of the given type of the listener status, or nil.
+func listenerCondition(listenerStatuses []gatewayv1.ListenerStatus, listener gatewayv1.SectionName, conditionType gatewayv1.ListenerConditionType) *metav1.Condition {
+	for _, listenerStatus := range listenerStatuses {
+		if listenerStatus.Name == listener {
+			return meta.FindStatusCondition(listenerStatus.Conditions, string(conditionType))
+		}
+	}
+	return nil
+}
+
+func TestTranslateCrossNamespaceCertificateRef(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		grant      *gatewayv1beta1.ReferenceGrant
+		wantSecret bool
+		wantStatus metav1.ConditionStatus
+		wantReason gatewayv1.ListenerConditionReason
+	}{
+		{
+			name:       "allowed by a grant",
+			grant:      testReferenceGrant("certs", "Gateway", "Secret"),
+			wantSecret: true,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: gatewayv1.ListenerReasonResolvedRefs,
+		},
+		{
+			name:       "no grant",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: gatewayv1.ListenerReasonRefNotPermitted,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listener := httpsListener("https", 443, "web-cert")
+			listener.TLS.CertificateRefs[0].Namespace = ptr(gatewayv1.Namespace("certs"))
+			gw := testGateway("gw", listener)
+			secret := testTLSSecret("web-cert")
+			secret.Namespace = "certs"
+			objs := []runtime.Object{testGatewayClass(), gw, secret}
+			if tc.grant != nil {
+				objs = append(objs, tc.grant)
+			}
+			tl := newTestTranslator(t, Options{}, objs...)
+			resources, listenerStatuses, _, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+			// The Secret is only read, and served over SDS, if the reference is allowed.
+			if got := findResource(resources, resourcev3.SecretType, "certs/web-cert") != nil; got != tc.wantSecret {
+				t.Errorf("secrets = %v, want certs/web-cert %v", resourceNames(resources, resourcev3.SecretType), tc.wantSecret)
+			}
+			condition := listenerCondition(listenerStatuses, "https", gatewayv1.ListenerConditionResolvedRefs)
+			if condition == nil || condition.Status != tc.wantStatus || condition.Reason != string(tc.wantReason) {
+				t.Errorf("ResolvedRefs = %v, want %s/%s", condition, tc.wantStatus, tc.wantReason)
+			}
+		})
+	}
+}
+
+// filterChainTLSContext returns the downstream TLS context of the filter chain.
+func filterChainTLSContext(t testing.TB, filterChain *listenerv3.FilterChain) *tlsv3.DownstreamTlsContext {
+	t.Helper()
+	if filterChain.TransportSocket == nil {
+		t.Fatalf("filter chain %s does not terminate TLS", filterChain.Name)
+	}
+	tlsContext := &tlsv3.DownstreamTlsContext{}
+	if err := filterChain.TransportSocket.GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+		t.Fatal(err)
+	}
+	return tlsContext
+}
+
+func TestTranslateHTTPSListenerSecrets(t *testing.T) {
+	gw := testGateway("gw", httpsListener("https", 443, "web-cert"))
+	secret := testTLSSecret("web-cert")
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, secret,
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	// The certificate is served over SDS under the namespace and name of the Secret.
+	envoySecret, ok := findResource(resources, resourcev3.SecretType, "default/web-cert").(*tlsv3.Secret)
+	if !ok {
+		t.Fatalf("secret default/web-cert not found in %v", resourceNames(resources, resourcev3.SecretType))
+	}
+	tlsCertificate := envoySecret.GetTlsCertificate()
+	if string(tlsCertificate.GetCertificateChain().GetInlineBytes()) != string(secret.Data[corev1.TLSCertKey]) ||
+		string(tlsCertificate.GetPrivateKey().GetInlineBytes()) != string(secret.Data[corev1.TLSPrivateKeyKey]) {
+		t.Errorf("secret default/web-cert = %v, want the certificate and key of the Secret", tlsCertificate)
+	}
+
+	// The TLS context of the listener refers to it rather than inlining it.
+	tlsContext := filterChainTLSContext(t, findListener(t, resources, "listener-443").FilterChains[0])
+	sdsConfigs := tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs
+	if len(sdsConfigs) != 1 || sdsConfigs[0].Name != "default/web-cert" || sdsConfigs[0].GetSdsConfig().GetAds() == nil {
+		t.Errorf("TLS certificate SDS configs = %v, want default/web-cert over ADS", sdsConfigs)
+	}
+	if len(tlsContext.CommonTlsContext.TlsCertificates) != 0 {
+		t.Errorf("TLS certificates = %v, want none inlined", tlsContext.CommonTlsContext.TlsCertificates)
+	}
+}
+
+func TestTranslateMultipleListeners(t *testing.T) {
+	apiListener := httpsListener("api", 443, "api-cert")
+	apiListener.Hostname = ptr(gatewayv1.Hostname("api.example.com"))
+	wwwListener := httpsListener("www", 443, "www-cert")
+	wwwListener.Hostname = ptr(gatewayv1.Hostname("www.example.com"))
+	gw := testGateway("gw", httpListener("http", 80), apiListener, wwwListener)
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testTLSSecret("api-cert"), testTLSSecret("www-cert"),
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	// Listeners on distinct ports become distinct Envoy listeners.
+	if got, want := resourceNames(resources, resourcev3.ListenerType), []string{"listener-443", "listener-80"}; !slices.Equal(slices.Sorted(slices.Values(got)), want) {
+		t.Fatalf("listeners = %v, want %v", got, want)
+	}
+	if filterChains := findListener(t, resources, "listener-80").FilterChains; len(filterChains) != 1 || filterChains[0].TransportSocket != nil {
+		t.Errorf("listener-80 filter chains = %v, want one plaintext filter chain", filterChains)
+	}
+
+	// The listeners sharing port 443 each get a filter chain selected by SNI, which terminates
+	// TLS with the certificate of the listener and serves the routes of the listener.
+	filterChains := findListener(t, resources, "listener-443").FilterChains
+	if len(filterChains) != 2 {
+		t.Fatalf("listener-443 has %d filter chains, want 2", len(filterChains))
+	}
+	for _, tc := range []struct {
+		hostname, secret, routeConfig string
+	}{
+		{hostname: "api.example.com", secret: "default/api-cert", routeConfig: "route-443-api"},
+		{hostname: "www.example.com", secret: "default/www-cert", routeConfig: "route-443-www"},
+	} {
+		i := slices.IndexFunc(filterChains, func(filterChain *listenerv3.FilterChain) bool {
+			return slices.Equal(filterChain.GetFilterChainMatch().GetServerNames(), []string{tc.hostname})
+		})
+		if i < 0 {
+			t.Errorf("no filter chain matches server name %s", tc.hostname)
+			continue
+		}
+		sdsConfigs := filterChainTLSContext(t, filterChains[i]).CommonTlsContext.TlsCertificateSdsSecretConfigs
+		if len(sdsConfigs) != 1 || sdsConfigs[0].Name != tc.secret {
+			t.Errorf("filter chain of %s certificates = %v, want %s", tc.hostname, sdsConfigs, tc.secret)
+		}
+		if got := filterChainHCM(t, filterChains[i]).GetRds().GetRouteConfigName(); got != tc.routeConfig {
+			t.Errorf("filter chain of %s route config = %s, want %s", tc.hostname, got, tc.routeConfig)
+		}
+		findVirtualHost(t, findRouteConfiguration(t, resources, tc.routeConfig), tc.hostname)
+	}
+}
+
+func TestTranslateHTTPSRedirect(t *testing.T) {
+	hostname := ptr(gatewayv1.Hostname("www.example.com"))
+	plainListener := httpListener("http", 80)
+	plainListener.Hostname = hostname
+	tlsListener := httpsListener("https", 443, "web-cert")
+	tlsListener.Hostname = hostname
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Spec.ParentRefs[0].SectionName = ptr(gatewayv1.SectionName("http"))
+
+	for _, tc := range []struct {
+		name         string
+		listeners    []gatewayv1.Listener
+		wantRedirect bool
+	}{
+		{name: "hostname served over HTTPS", listeners: []gatewayv1.Listener{plainListener, tlsListener}, wantRedirect: true},
+		{name: "hostname only served over HTTP", listeners: []gatewayv1.Listener{plainListener}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", tc.listeners...)
+			tl := newTestTranslator(t, Options{HTTPSRedirect: true}, testGatewayClass(), gw, testTLSSecret("web-cert"), testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "www.example.com")
+			if !tc.wantRedirect {
+				// The routes of the HTTP listener are proxied as usual.
+				if names := routeNames(vh); !slices.Contains(names, "default-web-rule0-match0") {
+					t.Errorf("virtual host %s routes = %v, want default-web-rule0-match0", vh.Name, names)
+				}
+				findCluster(t, resources, clusterName("backend", 80))
+				return
+			}
+			// The HTTP listener only redirects to HTTPS, so its routes need no clusters.
+			for _, envoyRoute := range vh.Routes {
+				if envoyRoute.GetRoute() != nil {
+					t.Errorf("route %s forwards to %v, want no forwarding routes", envoyRoute.Name, envoyRoute.GetRoute().GetCluster())
+				}
+			}
+			redirect := vh.Routes[0].GetRedirect()
+			if !redirect.GetHttpsRedirect() || redirect.GetResponseCode() != routev3.RedirectAction_MOVED_PERMANENTLY {
+				t.Errorf("route %s redirect = %v, want a 301 redirect to HTTPS", vh.Routes[0].Name, redirect)
+			}
+			if clusters := resourceNames(resources, resourcev3.ClusterType); len(clusters) != 0 {
+				t.Errorf("clusters = %v, want none", clusters)
+			}
+		})
+	}
+}
+
+func TestTranslateListenerConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		listeners  []gatewayv1.Listener
+		conflicted []gatewayv1.SectionName
+		reason     gatewayv1.ListenerConditionReason
+		wantPorts  []string
+	}{
+		{
+			// Listeners sharing a name can't be told apart in the status, so all are excluded.
+			name:       "duplicate name",
+			listeners:  []gatewayv1.Listener{httpListener("web", 80), httpListener("web", 8080), httpListener("api", 9090)},
+			conflicted: []gatewayv1.SectionName{"web"},
+			reason:     gatewayv1.ListenerReasonProtocolConflict,
+			wantPorts:  []string{"listener-9090"},
+		},
+		{
+			name:       "protocol clash",
+			listeners:  []gatewayv1.Listener{httpListener("web", 80), tcpListener("db", 80), httpListener("api", 9090)},
+			conflicted: []gatewayv1.SectionName{"web", "db"},
+			reason:     gatewayv1.ListenerReasonProtocolConflict,
+			wantPorts:  []string{"listener-9090"},
+		},
+		{
+			name:       "hostname clash",
+			listeners:  []gatewayv1.Listener{httpListener("web", 80), httpListener("www", 80), httpListener("api", 9090)},
+			conflicted: []gatewayv1.SectionName{"web", "www"},
+			reason:     gatewayv1.ListenerReasonHostnameConflict,
+			wantPorts:  []string{"listener-9090"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", tc.listeners...)
+			tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+			resources, listenerStatuses, _, err := tl.buildEnvoyResourcesForGateway(gw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range tc.conflicted {
+				condition := listenerCondition(listenerStatuses, name, gatewayv1.ListenerConditionConflicted)
+				if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != string(tc.reason) {
+					t.Errorf("Conflicted of listener %s = %v, want True/%s", name, condition, tc.reason)
+				}
+			}
+			if condition := listenerCondition(listenerStatuses, "api", gatewayv1.ListenerConditionConflicted); condition != nil && condition.Status == metav1.ConditionTrue {
+				t.Errorf("Conflicted of listener api = %v, want not conflicted", condition)
+			}
+			// The conflicted listeners are excluded from translation.
+			if got := resourceNames(resources, resourcev3.ListenerType); !slices.Equal(got, tc.wantPorts) {
+				t.Errorf("listeners = %v, want %v", got, tc.wantPorts)
+			}
+		})
+	}
+}
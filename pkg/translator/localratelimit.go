@@ -0,0 +1,117 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	localRateLimitFilterName = "envoy.filters.http.local_ratelimit"
+	localRateLimitStatPrefix = "http_local_rate_limiter"
+)
+
+// The annotations of an HTTPRoute that configure the token bucket rate limiting its routes
+// on each Envoy instance.
+const (
+	localRateLimitMaxTokensAnnotation     = "gateway.xds/local-rate-limit-max-tokens"
+	localRateLimitTokensPerFillAnnotation = "gateway.xds/local-rate-limit-tokens-per-fill"
+	localRateLimitFillIntervalAnnotation  = "gateway.xds/local-rate-limit-fill-interval"
+)
+
+const defaultLocalRateLimitFillInterval = time.Second
+
+// parseLocalRateLimitAnnotations reads the token bucket from the annotations of an HTTPRoute
+// and returns the per-route config of the local rate limit filter, or nil if the HTTPRoute
+// is not rate limited. The bucket is refilled with max tokens every second by default.
+func parseLocalRateLimitAnnotations(annotations map[string]string) (*anypb.Any, error) {
+	value, ok := annotations[localRateLimitMaxTokensAnnotation]
+	if !ok {
+		for _, annotation := range []string{localRateLimitTokensPerFillAnnotation, localRateLimitFillIntervalAnnotation} {
+			if _, ok := annotations[annotation]; ok {
+				return nil, fmt.Errorf("annotation %s requires annotation %s", annotation, localRateLimitMaxTokensAnnotation)
+			}
+		}
+		return nil, nil
+	}
+	maxTokens, err := parsePositiveUint32(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", localRateLimitMaxTokensAnnotation, err)
+	}
+	tokensPerFill := maxTokens
+	if value, ok := annotations[localRateLimitTokensPerFillAnnotation]; ok {
+		tokensPerFill, err = parsePositiveUint32(value)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", localRateLimitTokensPerFillAnnotation, err)
+		}
+	}
+	fillInterval := defaultLocalRateLimitFillInterval
+	if value, ok := annotations[localRateLimitFillIntervalAnnotation]; ok {
+		fillInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", localRateLimitFillIntervalAnnotation, err)
+		}
+		// Envoy rejects fill intervals shorter than a millisecond.
+		if fillInterval < time.Millisecond {
+			return nil, fmt.Errorf("annotation %s: fill interval %q must be at least 1ms", localRateLimitFillIntervalAnnotation, value)
+		}
+	}
+
+	// The filter is enabled and enforced for all requests of the route.
+	allRequests := func(runtimeKey string) *corev3.RuntimeFractionalPercent {
+		return &corev3.RuntimeFractionalPercent{
+			DefaultValue: &typev3.FractionalPercent{Numerator: 100, Denominator: typev3.FractionalPercent_HUNDRED},
+			RuntimeKey:   runtimeKey,
+		}
+	}
+	return anypb.New(&localratelimitv3.LocalRateLimit{
+		StatPrefix: localRateLimitStatPrefix,
+		TokenBucket: &typev3.TokenBucket{
+			MaxTokens:     maxTokens,
+			TokensPerFill: wrapperspb.UInt32(tokensPerFill),
+			FillInterval:  durationpb.New(fillInterval),
+		},
+		FilterEnabled:  allRequests("local_rate_limit_enabled"),
+		FilterEnforced: allRequests("local_rate_limit_enforced"),
+	})
+}
+
+// buildLocalRateLimitFilter builds the local rate limit filter of an HTTP connection manager.
+// It has no token bucket of its own, so it only limits routes that configure one.
+func buildLocalRateLimitFilter() (*anypb.Any, error) {
+	return anypb.New(&localratelimitv3.LocalRateLimit{
+		StatPrefix: localRateLimitStatPrefix,
+	})
+}
+
+// routesUseFilter reports whether any route of the virtual hosts has a per-route config
+// for the named HTTP filter.
+func routesUseFilter(virtualHosts []*routev3.VirtualHost, filterName string) bool {
+	for _, vh := range virtualHosts {
+		for _, route := range vh.Routes {
+			if _, ok := route.TypedPerFilterConfig[filterName]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parsePositiveUint32(value string) (uint32, error) {
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if parsed == 0 {
+		return 0, fmt.Errorf("%q must be positive", value)
+	}
+	return uint32(parsed), nil
+}
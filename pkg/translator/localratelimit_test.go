@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateHTTPRouteLocalRateLimit(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	limited := testHTTPRoute("limited", "gw", testBackendRef("backend", 80))
+	limited.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/limited")}
+	limited.Annotations = map[string]string{
+		localRateLimitMaxTokensAnnotation:     "100",
+		localRateLimitTokensPerFillAnnotation: "10",
+		localRateLimitFillIntervalAnnotation:  "500ms",
+	}
+	open := testHTTPRoute("open", "gw", testBackendRef("backend", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), limited, open)
+	resources := translateGateway(t, tl, gw)
+
+	// The filter of the connection manager rate limits the routes that override it.
+	filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80")))
+	if i := slices.Index(filters, localRateLimitFilterName); i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Errorf("HTTP filters = %v, want %s before the router", filters, localRateLimitFilterName)
+	}
+
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	perRouteConfig, ok := findRoute(t, vh, "default-limited-rule0-match0").TypedPerFilterConfig[localRateLimitFilterName]
+	if !ok {
+		t.Fatalf("route default-limited-rule0-match0 has no %s override", localRateLimitFilterName)
+	}
+	localRateLimit := &localratelimitv3.LocalRateLimit{}
+	if err := perRouteConfig.UnmarshalTo(localRateLimit); err != nil {
+		t.Fatal(err)
+	}
+	tokenBucket := localRateLimit.TokenBucket
+	if tokenBucket.GetMaxTokens() != 100 || tokenBucket.GetTokensPerFill().GetValue() != 10 || tokenBucket.GetFillInterval().AsDuration() != 500*time.Millisecond {
+		t.Errorf("token bucket = %v, want 100 max tokens, 10 per fill every 500ms", tokenBucket)
+	}
+	if localRateLimit.GetFilterEnforced().GetDefaultValue().GetNumerator() != 100 {
+		t.Errorf("filter enforced = %v, want all requests", localRateLimit.FilterEnforced)
+	}
+	if _, ok := findRoute(t, vh, "default-open-rule0-match0").TypedPerFilterConfig[localRateLimitFilterName]; ok {
+		t.Errorf("route default-open-rule0-match0 has a %s override, want none", localRateLimitFilterName)
+	}
+}
+
+func TestTranslateHTTPRouteWithoutLocalRateLimit(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	if filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80"))); slices.Contains(filters, localRateLimitFilterName) {
+		t.Errorf("HTTP filters = %v, want no %s", filters, localRateLimitFilterName)
+	}
+}
+
+func TestParseLocalRateLimitAnnotationsInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{name: "zero max tokens", annotations: map[string]string{localRateLimitMaxTokensAnnotation: "0"}},
+		{name: "fill interval below 1ms", annotations: map[string]string{localRateLimitMaxTokensAnnotation: "10", localRateLimitFillIntervalAnnotation: "100us"}},
+		{name: "tokens per fill without max tokens", annotations: map[string]string{localRateLimitTokensPerFillAnnotation: "10"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if config, err := parseLocalRateLimitAnnotations(tc.annotations); err == nil {
+				t.Errorf("parseLocalRateLimitAnnotations(%v) = %v, want an error", tc.annotations, config)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package translator
+
+import (
+	"fmt"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+)
+
+const (
+	// DefaultNotFoundStatus is the status of the responses to requests that match no route.
+	DefaultNotFoundStatus = 404
+	// DefaultNotFoundBody is the body of the responses to requests that match no route.
+	DefaultNotFoundBody = "no matching route\n"
+)
+
+// validateNotFoundStatus reports whether the status can be returned for unmatched requests.
+func validateNotFoundStatus(status uint32) error {
+	if status != 0 && (status < 200 || status > 599) {
+		return fmt.Errorf("invalid HTTP status %d", status)
+	}
+	return nil
+}
+
+// buildNotFoundRoute builds the catch-all route of a virtual host, which answers the requests
+// that match none of its other routes with a direct response, so that they don't depend on
+// Envoy's defaults.
+func buildNotFoundRoute(vh *routev3.VirtualHost, options Options) *routev3.Route {
+	directResponse := &routev3.DirectResponseAction{
+		Status: options.NotFoundStatus,
+	}
+	if directResponse.Status == 0 {
+		directResponse.Status = DefaultNotFoundStatus
+	}
+	if options.NotFoundBody != "" {
+		directResponse.Body = &corev3.DataSource{
+			Specifier: &corev3.DataSource_InlineString{InlineString: options.NotFoundBody},
+		}
+	}
+	return &routev3.Route{
+		Name: vh.Name + "-not-found",
+		Match: &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+		},
+		Action: &routev3.Route_DirectResponse{DirectResponse: directResponse},
+	}
+}
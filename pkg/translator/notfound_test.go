@@ -0,0 +1,55 @@
+package translator
+
+import (
+	"testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateNotFoundRoute(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		options    Options
+		wantStatus uint32
+		wantBody   string
+	}{
+		{name: "defaults", options: Options{NotFoundBody: DefaultNotFoundBody}, wantStatus: 404, wantBody: "no matching route\n"},
+		{name: "configured", options: Options{NotFoundStatus: 410, NotFoundBody: "gone\n"}, wantStatus: 410, wantBody: "gone\n"},
+		{name: "no body", wantStatus: 404},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			// The catch-all prefix of the route must not be shadowed by the not-found route.
+			route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+			route.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchPathPrefix, "/")}
+			tl := newTestTranslator(t, tc.options, testGatewayClass(), gw, testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+			if names := routeNames(vh); len(names) != 2 || names[0] != "default-web-rule0-match0" {
+				t.Fatalf("virtual host %s routes = %v, want default-web-rule0-match0 first", vh.Name, names)
+			}
+			last := vh.Routes[len(vh.Routes)-1]
+			directResponse := last.GetDirectResponse()
+			if last.Name != vh.Name+"-not-found" || last.GetMatch().GetPrefix() != "/" || directResponse == nil {
+				t.Fatalf("last route = %v, want a catch-all direct response", last)
+			}
+			if directResponse.Status != tc.wantStatus || directResponse.GetBody().GetInlineString() != tc.wantBody {
+				t.Errorf("direct response = %d %q, want %d %q", directResponse.Status, directResponse.GetBody().GetInlineString(), tc.wantStatus, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestValidateNotFoundStatus(t *testing.T) {
+	for _, status := range []uint32{0, 200, 404, 599} {
+		if err := validateNotFoundStatus(status); err != nil {
+			t.Errorf("validateNotFoundStatus(%d) = %v, want nil", status, err)
+		}
+	}
+	for _, status := range []uint32{99, 199, 600} {
+		if err := validateNotFoundStatus(status); err == nil {
+			t.Errorf("validateNotFoundStatus(%d) = nil, want an error", status)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package translator
+
+import (
+	"fmt"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	originaldstv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/original_dst/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tcpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// originalDstClusterNames returns the names of the clusters that forward connections to
+// their original destination.
+func originalDstClusterNames(clusters map[string]envoyproxytypes.Resource) sets.Set[string] {
+	names := sets.New[string]()
+	for name, res := range clusters {
+		if cluster, ok := res.(*clusterv3.Cluster); ok && cluster.GetType() == clusterv3.Cluster_ORIGINAL_DST {
+			names.Insert(name)
+		}
+	}
+	return names
+}
+
+// addOriginalDstListenerFilters makes the TCP listeners that forward connections to original
+// destination clusters recover the original destination address of their connections. The
+// filter runs first, so that the other listener filters see the original destination.
+func addOriginalDstListenerFilters(listeners []envoyproxytypes.Resource, routeConfigs []envoyproxytypes.Resource, clusters map[string]envoyproxytypes.Resource) error {
+	originalDstClusters := originalDstClusterNames(clusters)
+	if originalDstClusters.Len() == 0 {
+		return nil
+	}
+	routeConfigsByName := make(map[string]*routev3.RouteConfiguration, len(routeConfigs))
+	for _, res := range routeConfigs {
+		routeConfig := res.(*routev3.RouteConfiguration)
+		routeConfigsByName[routeConfig.Name] = routeConfig
+	}
+
+	originalDstConfig, err := anypb.New(&originaldstv3.OriginalDst{})
+	if err != nil {
+		return err
+	}
+	for _, res := range listeners {
+		envoyListener, ok := res.(*listenerv3.Listener)
+		if !ok || envoyListener.GetAddress().GetSocketAddress().GetProtocol() != corev3.SocketAddress_TCP {
+			continue
+		}
+		listenerClusters, err := listenerClusterNames(envoyListener, routeConfigsByName)
+		if err != nil {
+			return err
+		}
+		if !listenerClusters.HasAny(originalDstClusters.UnsortedList()...) {
+			continue
+		}
+		envoyListener.ListenerFilters = append([]*listenerv3.ListenerFilter{{
+			Name: wellknown.OriginalDestination,
+			ConfigType: &listenerv3.ListenerFilter_TypedConfig{
+				TypedConfig: originalDstConfig,
+			},
+		}}, envoyListener.ListenerFilters...)
+	}
+	return nil
+}
+
+// listenerClusterNames returns the names of the clusters that the HTTP connection managers
+// and TCP proxies of the listener forward to.
+func listenerClusterNames(envoyListener *listenerv3.Listener, routeConfigs map[string]*routev3.RouteConfiguration) (sets.Set[string], error) {
+	names := sets.New[string]()
+	for _, filterChain := range envoyListener.FilterChains {
+		for _, filter := range filterChain.Filters {
+			switch filter.Name {
+			case wellknown.HTTPConnectionManager:
+				hcmConfig := &hcm.HttpConnectionManager{}
+				if err := filter.GetTypedConfig().UnmarshalTo(hcmConfig); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal HTTP connection manager of listener %s: %w", envoyListener.Name, err)
+				}
+				routeConfig := hcmConfig.GetRouteConfig()
+				if rds := hcmConfig.GetRds(); rds != nil {
+					routeConfig = routeConfigs[rds.RouteConfigName]
+				}
+				for _, vh := range routeConfig.GetVirtualHosts() {
+					for _, route := range vh.Routes {
+						routeAction := route.GetRoute()
+						if cluster := routeAction.GetCluster(); cluster != "" {
+							names.Insert(cluster)
+						}
+						for _, clusterWeight := range routeAction.GetWeightedClusters().GetClusters() {
+							names.Insert(clusterWeight.Name)
+						}
+						for _, mirrorPolicy := range routeAction.GetRequestMirrorPolicies() {
+							names.Insert(mirrorPolicy.Cluster)
+						}
+					}
+				}
+			case wellknown.TCPProxy:
+				tcpProxy := &tcpproxyv3.TcpProxy{}
+				if err := filter.GetTypedConfig().UnmarshalTo(tcpProxy); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal TCP proxy of listener %s: %w", envoyListener.Name, err)
+				}
+				if cluster := tcpProxy.GetCluster(); cluster != "" {
+					names.Insert(cluster)
+				}
+				for _, clusterWeight := range tcpProxy.GetWeightedClusters().GetClusters() {
+					names.Insert(clusterWeight.Name)
+				}
+			}
+		}
+	}
+	return names, nil
+}
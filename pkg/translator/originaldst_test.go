@@ -0,0 +1,46 @@
+package translator
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// TestTranslateOriginalDstCluster checks that a Service resolved to the original destination
+// gets an ORIGINAL_DST cluster, and that only the listener forwarding to it recovers the
+// original destination of its connections.
+func TestTranslateOriginalDstCluster(t *testing.T) {
+	gw := testGateway("gw", httpListener("transparent", 80), httpListener("plain", 8080))
+	transparentService := testService("transparent", 80)
+	transparentService.Annotations = map[string]string{resolutionAnnotation: resolutionOriginalDst}
+	transparentRoute := testHTTPRoute("transparent", "gw", testBackendRef("transparent", 80))
+	transparentRoute.Spec.ParentRefs[0].SectionName = ptr(gatewayv1.SectionName("transparent"))
+	plainRoute := testHTTPRoute("plain", "gw", testBackendRef("plain", 80))
+	plainRoute.Spec.ParentRefs[0].SectionName = ptr(gatewayv1.SectionName("plain"))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		transparentService, transparentRoute, testService("plain", 80), plainRoute)
+	resources := translateGateway(t, tl, gw)
+
+	cluster := findCluster(t, resources, clusterName("transparent", 80))
+	if cluster.GetType() != clusterv3.Cluster_ORIGINAL_DST {
+		t.Errorf("cluster type = %v, want ORIGINAL_DST", cluster.GetType())
+	}
+	if cluster.LbPolicy != clusterv3.Cluster_CLUSTER_PROVIDED {
+		t.Errorf("cluster LB policy = %v, want CLUSTER_PROVIDED", cluster.LbPolicy)
+	}
+	if cluster.GetEdsClusterConfig() != nil || cluster.GetLoadAssignment() != nil {
+		t.Errorf("ORIGINAL_DST cluster has endpoints: %v", cluster)
+	}
+
+	listenerFilters := findListener(t, resources, "listener-80").ListenerFilters
+	if len(listenerFilters) == 0 || listenerFilters[0].Name != wellknown.OriginalDestination {
+		t.Errorf("listener-80 filters = %v, want %s first", listenerFilters, wellknown.OriginalDestination)
+	}
+	for _, filter := range findListener(t, resources, "listener-8080").ListenerFilters {
+		if filter.Name == wellknown.OriginalDestination {
+			t.Errorf("listener-8080 has the %s filter but forwards to no ORIGINAL_DST cluster", wellknown.OriginalDestination)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package translator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The annotations of a Service that override the outlier detection of its clusters.
+const (
+	outlierConsecutive5xxAnnotation     = "gateway.xds/outlier-consecutive-5xx"
+	outlierIntervalAnnotation           = "gateway.xds/outlier-interval"
+	outlierBaseEjectionTimeAnnotation   = "gateway.xds/outlier-base-ejection-time"
+	outlierMaxEjectionPercentAnnotation = "gateway.xds/outlier-max-ejection-percent"
+)
+
+// healthyPanicThresholdAnnotation is the annotation of a Service that sets the percentage of
+// healthy endpoints of its clusters below which Envoy panics and load balances across all
+// endpoints, ejected or not. A threshold of 0 disables panic mode.
+const healthyPanicThresholdAnnotation = "gateway.xds/healthy-panic-threshold"
+
+// The outlier detection defaults, which match Envoy's defaults.
+const (
+	defaultOutlierConsecutive5xx     = 5
+	defaultOutlierInterval           = 10 * time.Second
+	defaultOutlierBaseEjectionTime   = 30 * time.Second
+	defaultOutlierMaxEjectionPercent = 10
+)
+
+// buildOutlierDetection builds the outlier detection of a cluster for the Service, so that
+// endpoints returning consecutive 5xx responses are ejected from the load balancing pool.
+// The defaults can be overridden with the annotations of the Service.
+func buildOutlierDetection(service *corev1.Service) (*clusterv3.OutlierDetection, error) {
+	outlierDetection := &clusterv3.OutlierDetection{
+		Consecutive_5Xx:    wrapperspb.UInt32(defaultOutlierConsecutive5xx),
+		Interval:           durationpb.New(defaultOutlierInterval),
+		BaseEjectionTime:   durationpb.New(defaultOutlierBaseEjectionTime),
+		MaxEjectionPercent: wrapperspb.UInt32(defaultOutlierMaxEjectionPercent),
+	}
+
+	consecutive5xx, ok, err := uint32Annotation(service, outlierConsecutive5xxAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		outlierDetection.Consecutive_5Xx = wrapperspb.UInt32(consecutive5xx)
+	}
+	interval, ok, err := durationAnnotation(service, outlierIntervalAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		outlierDetection.Interval = interval
+	}
+	baseEjectionTime, ok, err := durationAnnotation(service, outlierBaseEjectionTimeAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		outlierDetection.BaseEjectionTime = baseEjectionTime
+	}
+	maxEjectionPercent, ok, err := uint32Annotation(service, outlierMaxEjectionPercentAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if maxEjectionPercent > 100 {
+			return nil, fmt.Errorf("annotation %s of service %s/%s: %d is not a percentage", outlierMaxEjectionPercentAnnotation, service.Namespace, service.Name, maxEjectionPercent)
+		}
+		outlierDetection.MaxEjectionPercent = wrapperspb.UInt32(maxEjectionPercent)
+	}
+	return outlierDetection, nil
+}
+
+// buildCommonLBConfig builds the common load balancing config of a cluster for the Service
+// from its healthy panic threshold annotation. It returns nil if the annotation is not set,
+// so that Envoy's default threshold of 50% applies.
+func buildCommonLBConfig(service *corev1.Service) (*clusterv3.Cluster_CommonLbConfig, error) {
+	value, ok := service.Annotations[healthyPanicThresholdAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(threshold) || threshold < 0 || threshold > 100 {
+		return nil, fmt.Errorf("annotation %s of service %s/%s: %q is not a percentage", healthyPanicThresholdAnnotation, service.Namespace, service.Name, value)
+	}
+	return &clusterv3.Cluster_CommonLbConfig{
+		HealthyPanicThreshold: &typev3.Percent{Value: threshold},
+	}, nil
+}
@@ -0,0 +1,121 @@
+package translator
+
+import (
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTranslateOutlierDetection(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        *clusterv3.OutlierDetection
+	}{
+		{
+			name: "defaults",
+			want: &clusterv3.OutlierDetection{
+				Consecutive_5Xx:    wrapperspb.UInt32(5),
+				Interval:           durationpb.New(10 * time.Second),
+				BaseEjectionTime:   durationpb.New(30 * time.Second),
+				MaxEjectionPercent: wrapperspb.UInt32(10),
+			},
+		},
+		{
+			name: "annotated Service",
+			annotations: map[string]string{
+				outlierConsecutive5xxAnnotation:     "3",
+				outlierIntervalAnnotation:           "5s",
+				outlierBaseEjectionTimeAnnotation:   "1m",
+				outlierMaxEjectionPercentAnnotation: "50",
+			},
+			want: &clusterv3.OutlierDetection{
+				Consecutive_5Xx:    wrapperspb.UInt32(3),
+				Interval:           durationpb.New(5 * time.Second),
+				BaseEjectionTime:   durationpb.New(time.Minute),
+				MaxEjectionPercent: wrapperspb.UInt32(50),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = tc.annotations
+			cluster := translateServiceCluster(t, Options{}, service)
+			if !proto.Equal(cluster.OutlierDetection, tc.want) {
+				t.Errorf("outlier detection = %v, want %v", cluster.OutlierDetection, tc.want)
+			}
+		})
+	}
+}
+
+func TestTranslateOutlierDetectionExternalName(t *testing.T) {
+	// Envoy resolves an ExternalName Service to a single host, which can't be ejected.
+	service := testService("backend", 80)
+	service.Spec = corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "backend.example.com", Ports: service.Spec.Ports}
+	cluster := translateServiceCluster(t, Options{}, service)
+	if cluster.OutlierDetection != nil {
+		t.Errorf("outlier detection of %v cluster = %v, want none", cluster.GetType(), cluster.OutlierDetection)
+	}
+}
+
+func TestBuildOutlierDetectionInvalidAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		annotation string
+		value      string
+	}{
+		{annotation: outlierConsecutive5xxAnnotation, value: "-1"},
+		{annotation: outlierIntervalAnnotation, value: "often"},
+		{annotation: outlierMaxEjectionPercentAnnotation, value: "150"},
+	} {
+		t.Run(tc.annotation, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = map[string]string{tc.annotation: tc.value}
+			if outlierDetection, err := buildOutlierDetection(service); err == nil {
+				t.Errorf("buildOutlierDetection() = %v, want an error for %s=%q", outlierDetection, tc.annotation, tc.value)
+			}
+		})
+	}
+}
+
+func TestTranslateHealthyPanicThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value string
+		want  float64
+	}{
+		{name: "threshold", value: "25", want: 25},
+		// A threshold of 0 disables panic mode, so it must be kept rather than left to the default.
+		{name: "disabled", value: "0", want: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Annotations = map[string]string{healthyPanicThresholdAnnotation: tc.value}
+			cluster := translateServiceCluster(t, Options{}, service)
+
+			threshold := cluster.GetCommonLbConfig().GetHealthyPanicThreshold()
+			if threshold == nil || threshold.Value != tc.want {
+				t.Errorf("healthy panic threshold = %v, want %v%%", threshold, tc.want)
+			}
+		})
+	}
+
+	// Envoy's default threshold applies to Services without the annotation.
+	if cluster := translateServiceCluster(t, Options{}, testService("backend", 80)); cluster.CommonLbConfig != nil {
+		t.Errorf("common LB config = %v, want none", cluster.CommonLbConfig)
+	}
+}
+
+func TestBuildCommonLBConfigInvalid(t *testing.T) {
+	for _, value := range []string{"-1", "100.5", "half", "NaN"} {
+		service := testService("backend", 80)
+		service.Annotations = map[string]string{healthyPanicThresholdAnnotation: value}
+		if commonLBConfig, err := buildCommonLBConfig(service); err == nil {
+			t.Errorf("buildCommonLBConfig() = %v, want an error for %q", commonLBConfig, value)
+		}
+	}
+}
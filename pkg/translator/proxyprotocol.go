@@ -0,0 +1,34 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// proxyProtocolAnnotation is the annotation of a Gateway behind a load balancer that
+// prepends the PROXY protocol header to its connections. The TCP listeners of the Gateway
+// decode the header, so that the address of the client is recovered.
+const proxyProtocolAnnotation = "gateway.xds/proxy-protocol"
+
+// parseProxyProtocolAnnotation returns whether the annotations of a Gateway enable the PROXY
+// protocol.
+func parseProxyProtocolAnnotation(annotations map[string]string) (bool, error) {
+	value, ok := annotations[proxyProtocolAnnotation]
+	if !ok {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("annotation %s: %w", proxyProtocolAnnotation, err)
+	}
+	return enabled, nil
+}
+
+// usesProxyProtocol returns whether the Gateway is annotated for the PROXY protocol. Invalid
+// annotations are reported by the translation of the Gateway.
+func usesProxyProtocol(gateway *gatewayv1.Gateway) bool {
+	enabled, _ := parseProxyProtocolAnnotation(gateway.Annotations)
+	return enabled
+}
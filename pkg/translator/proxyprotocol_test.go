@@ -0,0 +1,77 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+)
+
+// listenerFilterNames returns the names of the listener filters of the listener.
+func listenerFilterNames(listener *listenerv3.Listener) []string {
+	var names []string
+	for _, filter := range listener.ListenerFilters {
+		names = append(names, filter.Name)
+	}
+	return names
+}
+
+// TestTranslateProxyProtocol checks that the listeners of an annotated Gateway decode the
+// PROXY protocol header before inspecting TLS, and that the HTTP connection managers trust
+// the address it carries.
+func TestTranslateProxyProtocol(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80), tcpListener("db", 5432))
+	gw.Annotations = map[string]string{proxyProtocolAnnotation: "true"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)),
+		testService("postgres", 5432), testTCPRoute("db", "gw", testBackendRef("postgres", 5432)))
+	resources := translateGateway(t, tl, gw)
+
+	for _, name := range []string{"listener-80", "listener-5432"} {
+		filters := listenerFilterNames(findListener(t, resources, name))
+		if len(filters) == 0 || filters[0] != wellknown.ProxyProtocol {
+			t.Errorf("%s filters = %v, want %s first", name, filters, wellknown.ProxyProtocol)
+		}
+	}
+	if manager := listenerHCM(t, findListener(t, resources, "listener-80")); !manager.GetUseRemoteAddress().GetValue() {
+		t.Error("HTTP connection manager doesn't use the remote address recovered from the PROXY protocol")
+	}
+}
+
+func TestTranslateWithoutProxyProtocol(t *testing.T) {
+	for _, annotations := range []map[string]string{nil, {proxyProtocolAnnotation: "false"}} {
+		gw := testGateway("gw", httpListener("http", 80))
+		gw.Annotations = annotations
+		tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+			testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+		resources := translateGateway(t, tl, gw)
+
+		listener := findListener(t, resources, "listener-80")
+		for _, name := range listenerFilterNames(listener) {
+			if name == wellknown.ProxyProtocol {
+				t.Errorf("annotations %v: listener has the %s filter", annotations, wellknown.ProxyProtocol)
+			}
+		}
+		if listenerHCM(t, listener).GetUseRemoteAddress().GetValue() {
+			t.Errorf("annotations %v: HTTP connection manager uses the remote address", annotations)
+		}
+	}
+}
+
+func TestTranslateProxyProtocolInvalid(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	gw.Annotations = map[string]string{proxyProtocolAnnotation: "sometimes"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources, _, _, err := tl.buildEnvoyResourcesForGateway(gw)
+	if err == nil || !strings.Contains(err.Error(), proxyProtocolAnnotation) {
+		t.Fatalf("buildEnvoyResourcesForGateway() error = %v, want an error about %s", err, proxyProtocolAnnotation)
+	}
+	// The invalid annotation leaves the PROXY protocol disabled rather than failing the Gateway.
+	for _, name := range listenerFilterNames(findListener(t, resources, "listener-80")) {
+		if name == wellknown.ProxyProtocol {
+			t.Errorf("listener has the %s filter", wellknown.ProxyProtocol)
+		}
+	}
+}
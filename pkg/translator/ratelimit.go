@@ -0,0 +1,129 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	ratelimitconfv3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// rateLimitClusterName is the name of the cluster of the external rate limit service.
+const rateLimitClusterName = "rate-limit-service"
+
+// rateLimitDescriptorsAnnotation is the annotation of an HTTPRoute that defines the descriptors
+// sent to the rate limit service for each request of its routes. Descriptors are separated by
+// semicolons and consist of comma-separated actions, which are one of:
+//
+//	remote_address                            the client's address
+//	request_header=<header>:<descriptor key>  the value of a request header
+//	generic_key=<value>                       a fixed value
+const rateLimitDescriptorsAnnotation = "gateway.xds/rate-limit-descriptors"
+
+// parseRateLimitAnnotations reads the rate limit descriptors from the annotations of an
+// HTTPRoute. It returns nil if the HTTPRoute is not rate limited by the rate limit service.
+func parseRateLimitAnnotations(annotations map[string]string) ([]*routev3.RateLimit, error) {
+	value, ok := annotations[rateLimitDescriptorsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var rateLimits []*routev3.RateLimit
+	for _, descriptor := range strings.Split(value, ";") {
+		rateLimit := &routev3.RateLimit{}
+		for _, action := range splitList(descriptor) {
+			rateLimitAction, err := parseRateLimitAction(action)
+			if err != nil {
+				return nil, fmt.Errorf("annotation %s: %w", rateLimitDescriptorsAnnotation, err)
+			}
+			rateLimit.Actions = append(rateLimit.Actions, rateLimitAction)
+		}
+		if len(rateLimit.Actions) == 0 {
+			return nil, fmt.Errorf("annotation %s: descriptor %q has no actions", rateLimitDescriptorsAnnotation, descriptor)
+		}
+		rateLimits = append(rateLimits, rateLimit)
+	}
+	return rateLimits, nil
+}
+
+func parseRateLimitAction(action string) (*routev3.RateLimit_Action, error) {
+	kind, arg, _ := strings.Cut(action, "=")
+	switch kind {
+	case "remote_address":
+		return &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_RemoteAddress_{
+				RemoteAddress: &routev3.RateLimit_Action_RemoteAddress{},
+			},
+		}, nil
+	case "request_header":
+		header, descriptorKey, ok := strings.Cut(arg, ":")
+		if !ok || header == "" || descriptorKey == "" {
+			return nil, fmt.Errorf("action %q must have the form request_header=<header>:<descriptor key>", action)
+		}
+		return &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_RequestHeaders_{
+				RequestHeaders: &routev3.RateLimit_Action_RequestHeaders{
+					HeaderName:    header,
+					DescriptorKey: descriptorKey,
+				},
+			},
+		}, nil
+	case "generic_key":
+		if arg == "" {
+			return nil, fmt.Errorf("action %q must have the form generic_key=<value>", action)
+		}
+		return &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_GenericKey_{
+				GenericKey: &routev3.RateLimit_Action_GenericKey{DescriptorValue: arg},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit action %q", action)
+	}
+}
+
+// buildRateLimitCluster builds the cluster of the rate limit service, whose address is
+// resolved through DNS. The service is reached over gRPC, so the cluster speaks HTTP/2.
+func buildRateLimitCluster(address string) (*clusterv3.Cluster, error) {
+	cluster, err := buildStrictDNSCluster(rateLimitClusterName, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := enableHTTP2(cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// buildRateLimitFilter builds the rate limit filter of an HTTP connection manager, which asks
+// the rate limit service whether a request exceeds the limits of its descriptors. Requests are
+// allowed if the service is unavailable.
+func buildRateLimitFilter(domain string) (*anypb.Any, error) {
+	return anypb.New(&ratelimitv3.RateLimit{
+		Domain: domain,
+		RateLimitService: &ratelimitconfv3.RateLimitServiceConfig{
+			GrpcService: &corev3.GrpcService{
+				TargetSpecifier: &corev3.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &corev3.GrpcService_EnvoyGrpc{ClusterName: rateLimitClusterName},
+				},
+			},
+			TransportApiVersion: corev3.ApiVersion_V3,
+		},
+	})
+}
+
+// routesUseRateLimits reports whether any route of the virtual hosts sends descriptors to
+// the rate limit service.
+func routesUseRateLimits(virtualHosts []*routev3.VirtualHost) bool {
+	for _, vh := range virtualHosts {
+		for _, route := range vh.Routes {
+			if len(route.GetRoute().GetRateLimits()) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTranslateHTTPRouteRateLimitDescriptors(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Annotations = map[string]string{rateLimitDescriptorsAnnotation: "request_header=x-api-key:api_key"}
+	options := Options{RateLimitService: "ratelimit.default.svc:8081", RateLimitDomain: "gateway"}
+	tl := newTestTranslator(t, options, testGatewayClass(), gw, testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	// The route sends the value of the header to the rate limit service.
+	envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+	want := []*routev3.RateLimit{{Actions: []*routev3.RateLimit_Action{{
+		ActionSpecifier: &routev3.RateLimit_Action_RequestHeaders_{
+			RequestHeaders: &routev3.RateLimit_Action_RequestHeaders{HeaderName: "x-api-key", DescriptorKey: "api_key"},
+		},
+	}}}}
+	if rateLimits := envoyRoute.GetRoute().GetRateLimits(); !slices.EqualFunc(rateLimits, want, func(a, b *routev3.RateLimit) bool { return proto.Equal(a, b) }) {
+		t.Errorf("rate limits = %v, want %v", rateLimits, want)
+	}
+
+	// The filter asks the rate limit service, which is reached through a cluster of its own.
+	manager := listenerHCM(t, findListener(t, resources, "listener-80"))
+	filters := httpFilterNames(manager)
+	i := slices.Index(filters, wellknown.HTTPRateLimit)
+	if i < 0 || i > slices.Index(filters, wellknown.Router) {
+		t.Fatalf("HTTP filters = %v, want %s before the router", filters, wellknown.HTTPRateLimit)
+	}
+	rateLimit := &ratelimitv3.RateLimit{}
+	if err := manager.HttpFilters[i].GetTypedConfig().UnmarshalTo(rateLimit); err != nil {
+		t.Fatal(err)
+	}
+	if rateLimit.Domain != "gateway" || rateLimit.GetRateLimitService().GetGrpcService().GetEnvoyGrpc().GetClusterName() != rateLimitClusterName {
+		t.Errorf("rate limit filter = %v, want domain gateway and cluster %s", rateLimit, rateLimitClusterName)
+	}
+	cluster := findCluster(t, resources, rateLimitClusterName)
+	if cluster.GetType() != clusterv3.Cluster_STRICT_DNS {
+		t.Errorf("rate limit cluster type = %v, want STRICT_DNS", cluster.GetType())
+	}
+}
+
+func TestTranslateWithoutRateLimitDescriptors(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	options := Options{RateLimitService: "ratelimit.default.svc:8081", RateLimitDomain: "gateway"}
+	tl := newTestTranslator(t, options, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	if filters := httpFilterNames(listenerHCM(t, findListener(t, resources, "listener-80"))); slices.Contains(filters, wellknown.HTTPRateLimit) {
+		t.Errorf("HTTP filters = %v, want no %s without descriptors", filters, wellknown.HTTPRateLimit)
+	}
+}
+
+func TestParseRateLimitAnnotationsInvalid(t *testing.T) {
+	for _, value := range []string{"request_header=x-api-key", "generic_key=", "remote_address;", "client_ip"} {
+		t.Run(value, func(t *testing.T) {
+			annotations := map[string]string{rateLimitDescriptorsAnnotation: value}
+			if rateLimits, err := parseRateLimitAnnotations(annotations); err == nil {
+				t.Errorf("parseRateLimitAnnotations(%q) = %v, want an error", value, rateLimits)
+			}
+		})
+	}
+}
@@ -1,25 +1,26 @@
 package translator
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/klog/v2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayv1beta1listers "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
 )
 
 // isCrossNamespaceRefAllowed checks if a cross-namespace reference from a 'from' object
 // to a 'to' object is permitted by a ReferenceGrant in the 'to' object's namespace.
+// It returns an error if the ReferenceGrants can't be listed.
 func isCrossNamespaceRefAllowed(
 	from gatewayv1beta1.ReferenceGrantFrom, // Describes the referencing object (e.g., an HTTPRoute)
 	to gatewayv1beta1.ReferenceGrantTo, // Describes the referenced object (e.g., a Service)
 	toNamespace string, // The namespace of the referenced object
 	referenceGrantLister gatewayv1beta1listers.ReferenceGrantLister,
-) bool {
+) (bool, error) {
 	// List all ReferenceGrants in the target namespace.
 	grants, err := referenceGrantLister.ReferenceGrants(toNamespace).List(labels.Everything())
 	if err != nil {
-		klog.Errorf("Failed to list ReferenceGrants in namespace %s: %v", toNamespace, err)
-		return false
+		return false, fmt.Errorf("failed to list ReferenceGrants in namespace %s: %w", toNamespace, err)
 	}
 
 	for _, grant := range grants {
@@ -56,10 +57,10 @@ func isCrossNamespaceRefAllowed(
 
 		if toAllowed {
 			// We found a grant that explicitly allows this cross-namespace reference.
-			return true
+			return true, nil
 		}
 	}
 
 	// No grant was found that allows this reference.
-	return false
+	return false, nil
 }
@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"testing"
+
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// testReferenceGrant returns a ReferenceGrant in namespace that lets the objects of fromKind
+// in testNamespace reference the objects of toKind.
+func testReferenceGrant(namespace string, fromKind, toKind gatewayv1.Kind) *gatewayv1beta1.ReferenceGrant {
+	group := gatewayv1.Group(gatewayv1.GroupName)
+	return &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: namespace},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{Group: group, Kind: fromKind, Namespace: testNamespace}},
+			To:   []gatewayv1beta1.ReferenceGrantTo{{Group: "", Kind: toKind}},
+		},
+	}
+}
+
+func TestTranslateHTTPRouteCrossNamespaceBackend(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		namespace   string
+		grant       *gatewayv1beta1.ReferenceGrant
+		wantCluster string
+		wantReason  gatewayv1.RouteConditionReason
+	}{
+		{
+			name:        "same namespace",
+			namespace:   testNamespace,
+			wantCluster: clusterName("backend", 80),
+			wantReason:  gatewayv1.RouteReasonResolvedRefs,
+		},
+		{
+			name:        "allowed by a grant",
+			namespace:   "apps",
+			grant:       testReferenceGrant("apps", "HTTPRoute", "Service"),
+			wantCluster: "apps_backend_core_Service_80",
+			wantReason:  gatewayv1.RouteReasonResolvedRefs,
+		},
+		{
+			name:       "no grant",
+			namespace:  "apps",
+			wantReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+		{
+			name:       "grant for another kind",
+			namespace:  "apps",
+			grant:      testReferenceGrant("apps", "GRPCRoute", "Service"),
+			wantReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			backendRef := testBackendRef("backend", 80)
+			backendRef.Namespace = ptr(gatewayv1.Namespace(tc.namespace))
+			route := testHTTPRoute("web", "gw", backendRef)
+			service := testService("backend", 80)
+			service.Namespace = tc.namespace
+			objs := []runtime.Object{testGatewayClass(), gw, route, service}
+			if tc.grant != nil {
+				objs = append(objs, tc.grant)
+			}
+			tl := newTestTranslator(t, Options{}, objs...)
+			resources, _, routeStatuses, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+			clusters := resourceNames(resources, resourcev3.ClusterType)
+			if tc.wantCluster == "" && len(clusters) != 0 {
+				t.Errorf("clusters = %v, want none", clusters)
+			}
+			if tc.wantCluster != "" && (len(clusters) != 1 || clusters[0] != tc.wantCluster) {
+				t.Errorf("clusters = %v, want %s", clusters, tc.wantCluster)
+			}
+			parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "web"}]
+			if len(parents) != 1 {
+				t.Fatalf("got %d parent statuses, want 1", len(parents))
+			}
+			condition := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+			if condition == nil || condition.Reason != string(tc.wantReason) {
+				t.Errorf("ResolvedRefs = %v, want reason %s", condition, tc.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"fmt"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// resolutionAnnotation is the annotation of a Service that selects how Envoy resolves the
+// endpoints of its clusters: resolutionEDS, resolutionDNS or resolutionOriginalDst.
+const resolutionAnnotation = "gateway.xds/resolution"
+
+// The endpoint resolutions of a Service.
+const (
+	// resolutionEDS serves the addresses of the Service's EndpointSlices over EDS.
+	resolutionEDS = "eds"
+	// resolutionDNS makes Envoy resolve the DNS name of the Service instead, so that the
+	// individual pods are not tracked.
+	resolutionDNS = "dns"
+	// resolutionOriginalDst makes Envoy forward connections to their original destination
+	// address, as redirected to Envoy by a transparent proxy, instead of to the Service.
+	resolutionOriginalDst = "original-dst"
+)
+
+// DefaultClusterDomain is the default DNS domain of the Kubernetes cluster.
+const DefaultClusterDomain = "cluster.local"
+
+// serviceResolution returns how the endpoints of the Service's clusters are resolved
+// according to its annotation. They are served over EDS by default.
+func serviceResolution(service *corev1.Service) (string, error) {
+	switch resolution := service.Annotations[resolutionAnnotation]; resolution {
+	case "", resolutionEDS:
+		return resolutionEDS, nil
+	case resolutionDNS, resolutionOriginalDst:
+		return resolution, nil
+	default:
+		return "", fmt.Errorf("annotation %s of service %s/%s: unsupported resolution %q, must be %s, %s or %s",
+			resolutionAnnotation, service.Namespace, service.Name, resolution, resolutionEDS, resolutionDNS, resolutionOriginalDst)
+	}
+}
+
+// serviceDNSName returns the DNS name of the Service in the cluster domain.
+func serviceDNSName(service *corev1.Service, clusterDomain string) string {
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", service.Name, service.Namespace, clusterDomain)
+}
+
+// serviceDNSLookupFamily returns the address families that Envoy resolves the DNS name of the
+// Service to, according to the IP families of the Service. A dual-stack Service resolves to
+// the addresses of both families.
+func serviceDNSLookupFamily(service *corev1.Service) clusterv3.Cluster_DnsLookupFamily {
+	hasIPv4, hasIPv6 := false, false
+	for _, family := range service.Spec.IPFamilies {
+		switch family {
+		case corev1.IPv4Protocol:
+			hasIPv4 = true
+		case corev1.IPv6Protocol:
+			hasIPv6 = true
+		}
+	}
+	switch {
+	case hasIPv4 && hasIPv6:
+		return clusterv3.Cluster_ALL
+	case hasIPv6:
+		return clusterv3.Cluster_V6_ONLY
+	case hasIPv4:
+		return clusterv3.Cluster_V4_ONLY
+	default:
+		// The families are unknown, so Envoy prefers IPv6 and falls back to IPv4.
+		return clusterv3.Cluster_AUTO
+	}
+}
+
+// serviceDNSPort returns the port that the DNS name of the Service is reached on for the
+// given Service port. The DNS name of a headless Service resolves to the addresses of its
+// pods, which listen on the target port, so the target port must be a number.
+func serviceDNSPort(service *corev1.Service, port int32) (uint32, error) {
+	if service.Spec.ClusterIP != corev1.ClusterIPNone {
+		return uint32(port), nil
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port != port {
+			continue
+		}
+		switch {
+		case servicePort.TargetPort.Type == intstr.Int && servicePort.TargetPort.IntVal == 0:
+			// The target port defaults to the port.
+			return uint32(port), nil
+		case servicePort.TargetPort.Type == intstr.Int:
+			return uint32(servicePort.TargetPort.IntVal), nil
+		default:
+			return 0, fmt.Errorf("service %s/%s: the named target port %s of port %d cannot be resolved through DNS",
+				service.Namespace, service.Name, servicePort.TargetPort.StrVal, port)
+		}
+	}
+	return 0, fmt.Errorf("could not find port %d in service %s/%s", port, service.Namespace, service.Name)
+}
@@ -0,0 +1,89 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestTranslateDNSResolution(t *testing.T) {
+	headless := testService("backend", 80)
+	headless.Spec.ClusterIP = corev1.ClusterIPNone
+	headless.Spec.Ports[0].TargetPort = intstr.FromInt32(8080)
+
+	for _, tc := range []struct {
+		name    string
+		options Options
+		service *corev1.Service
+		want    string
+	}{
+		{name: "ClusterIP Service", service: testService("backend", 80), want: "backend.default.svc.cluster.local:80"},
+		// The pods behind a headless Service listen on the target port.
+		{name: "headless Service", service: headless, want: "backend.default.svc.cluster.local:8080"},
+		{name: "cluster domain", options: Options{ClusterDomain: "corp.example"}, service: testService("backend", 80), want: "backend.default.svc.corp.example:80"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := tc.service.DeepCopy()
+			service.Annotations = map[string]string{resolutionAnnotation: resolutionDNS}
+			cluster := translateServiceCluster(t, tc.options, service)
+
+			if cluster.GetType() != clusterv3.Cluster_STRICT_DNS || cluster.EdsClusterConfig != nil {
+				t.Errorf("cluster type = %v, want STRICT_DNS without EDS", cluster.GetType())
+			}
+			if localities := cluster.GetLoadAssignment().GetEndpoints(); len(localities) != 1 || !slices.Equal(lbEndpointAddresses(localities[0]), []string{tc.want}) {
+				t.Errorf("cluster load assignment = %v, want %s", cluster.LoadAssignment, tc.want)
+			}
+		})
+	}
+}
+
+func TestTranslateEDSResolution(t *testing.T) {
+	cluster := translateServiceCluster(t, Options{}, testService("backend", 80))
+	if cluster.GetType() != clusterv3.Cluster_EDS || cluster.EdsClusterConfig == nil {
+		t.Errorf("cluster type = %v, want EDS", cluster.GetType())
+	}
+}
+
+func TestServiceResolutionInvalid(t *testing.T) {
+	service := testService("backend", 80)
+	service.Annotations = map[string]string{resolutionAnnotation: "static"}
+	if resolution, err := serviceResolution(service); err == nil {
+		t.Errorf("serviceResolution() = %q, want an error", resolution)
+	}
+}
+
+func TestServiceDNSPortNamedTargetPort(t *testing.T) {
+	service := testService("backend", 80)
+	service.Spec.ClusterIP = corev1.ClusterIPNone
+	service.Spec.Ports[0].TargetPort = intstr.FromString("http")
+	if port, err := serviceDNSPort(service, 80); err == nil {
+		t.Errorf("serviceDNSPort() = %d, want an error", port)
+	}
+}
+
+func TestTranslateDNSLookupFamily(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		ipFamilies []corev1.IPFamily
+		want       clusterv3.Cluster_DnsLookupFamily
+	}{
+		{name: "unknown families", want: clusterv3.Cluster_AUTO},
+		{name: "IPv4", ipFamilies: []corev1.IPFamily{corev1.IPv4Protocol}, want: clusterv3.Cluster_V4_ONLY},
+		{name: "IPv6", ipFamilies: []corev1.IPFamily{corev1.IPv6Protocol}, want: clusterv3.Cluster_V6_ONLY},
+		{name: "dual-stack", ipFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}, want: clusterv3.Cluster_ALL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := testService("backend", 80)
+			service.Spec.IPFamilies = tc.ipFamilies
+			service.Annotations = map[string]string{resolutionAnnotation: resolutionDNS}
+			cluster := translateServiceCluster(t, Options{}, service)
+
+			if got := cluster.GetDnsLookupFamily(); got != tc.want {
+				t.Errorf("DNS lookup family = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
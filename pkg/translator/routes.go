@@ -1,28 +1,66 @@
 package translator
 
 import (
+	"fmt"
+	"log/slog"
+	"sort"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	corev1listers "k8s.io/client-go/listers/core/v1"
-	"k8s.io/klog/v2"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
+// sortRoutesByAge orders routes from oldest to newest, breaking ties by namespace/name.
+// This is the order in which the Gateway API resolves conflicts between routes.
+func sortRoutesByAge[T metav1.Object](routes []T) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		timestampI := routes[i].GetCreationTimestamp()
+		timestampJ := routes[j].GetCreationTimestamp()
+		if !timestampI.Equal(&timestampJ) {
+			return timestampI.Before(&timestampJ)
+		}
+		if routes[i].GetNamespace() != routes[j].GetNamespace() {
+			return routes[i].GetNamespace() < routes[j].GetNamespace()
+		}
+		return routes[i].GetName() < routes[j].GetName()
+	})
+}
+
 // isAllowedByListener checks if a given route is allowed to attach to a listener
 // based on the listener's `allowedRoutes` specification for namespaces and kinds.
-func isAllowedByListener(gateway *gatewayv1.Gateway, listener gatewayv1.Listener, route metav1.Object, namespaceLister corev1listers.NamespaceLister) bool {
+// Routes that can't be checked are denied and logged to logger.
+func isAllowedByListener(gateway *gatewayv1.Gateway, listener gatewayv1.Listener, route metav1.Object, namespaceLister corev1listers.NamespaceLister, logger *slog.Logger) bool {
+	routeNamespace := route.GetNamespace()
+	gatewayNamespace := gateway.GetNamespace()
+
+	// The route's kind must be supported by the listener's protocol and allowed by its allowedRoutes.
+	routeGroup, routeKind, ok := routeGroupKind(route)
+	if !ok {
+		logger.Warn("cannot determine the GroupKind of route", "type", fmt.Sprintf("%T", route), "namespace", routeNamespace, "name", route.GetName())
+		return false
+	}
+	kindAllowed := false
+	supportedKinds, _ := getSupportedKinds(listener)
+	for _, supportedKind := range supportedKinds {
+		if string(supportedKind.Kind) == routeKind && string(*supportedKind.Group) == routeGroup {
+			kindAllowed = true
+			break
+		}
+	}
+	if !kindAllowed {
+		return false
+	}
+
 	allowed := listener.AllowedRoutes
 	if allowed == nil {
 		// If AllowedRoutes is not set, only routes in the same namespace are allowed.
-		return route.GetNamespace() == gateway.GetNamespace()
+		return routeNamespace == gatewayNamespace
 	}
 
-	routeNamespace := route.GetNamespace()
-	gatewayNamespace := gateway.GetNamespace()
-
 	// Check if the route's namespace is allowed.
 	namespaceAllowed := false
 	effectiveFrom := gatewayv1.NamespacesFromSame
@@ -37,64 +75,51 @@ func isAllowedByListener(gateway *gatewayv1.Gateway, listener gatewayv1.Listener
 		namespaceAllowed = (routeNamespace == gatewayNamespace)
 	case gatewayv1.NamespacesFromSelector:
 		if allowed.Namespaces.Selector == nil {
-			klog.Errorf("Invalid AllowedRoutes: Namespaces.From is 'Selector' but Namespaces.Selector is nil for Gateway %s/%s, Listener %s", gatewayNamespace, gateway.GetName(), listener.Name)
+			logger.Error("invalid AllowedRoutes: Namespaces.From is Selector but Namespaces.Selector is nil",
+				"namespace", gatewayNamespace, "gateway", gateway.GetName(), "listener", listener.Name)
 			return false
 		}
 		if namespaceLister == nil {
-			klog.Warningf("Namespace selection using 'Selector' requires a Namespace Lister, but none was provided. Denying route %s/%s.", routeNamespace, route.GetName())
+			logger.Warn("namespace selection requires a Namespace lister, denying route", "namespace", routeNamespace, "name", route.GetName())
 			return false
 		}
 		selector, err := metav1.LabelSelectorAsSelector(allowed.Namespaces.Selector)
 		if err != nil {
-			klog.Errorf("Failed to parse label selector for Gateway %s/%s, Listener %s: %v", gatewayNamespace, gateway.GetName(), listener.Name, err)
+			logger.Error("failed to parse the AllowedRoutes label selector",
+				"namespace", gatewayNamespace, "gateway", gateway.GetName(), "listener", listener.Name, "err", err)
 			return false
 		}
 		routeNsObj, err := namespaceLister.Get(routeNamespace)
 		if err != nil {
-			klog.Warningf("Failed to get namespace %s for route %s/%s: %v", routeNamespace, routeNamespace, route.GetName(), err)
+			logger.Warn("failed to get the namespace of route", "namespace", routeNamespace, "name", route.GetName(), "err", err)
 			return false
 		}
 		namespaceAllowed = selector.Matches(labels.Set(routeNsObj.GetLabels()))
 	default:
-		klog.Errorf("Unknown 'From' value %q in AllowedRoutes.Namespaces for Gateway %s/%s, Listener %s", effectiveFrom, gatewayNamespace, gateway.GetName(), listener.Name)
+		logger.Error("unknown From value in AllowedRoutes.Namespaces", "from", effectiveFrom,
+			"namespace", gatewayNamespace, "gateway", gateway.GetName(), "listener", listener.Name)
 		return false
 	}
 
-	if !namespaceAllowed {
-		return false
-	}
-
-	// If namespaces are allowed, check if the route's kind is allowed.
-	if len(allowed.Kinds) == 0 {
-		// No kinds specified, so all kinds are allowed.
-		return true
-	}
+	return namespaceAllowed
+}
 
-	var routeGroup, routeKind string
+// routeGroupKind returns the API group and kind of a route object.
+func routeGroupKind(route metav1.Object) (string, string, bool) {
 	switch route.(type) {
 	case *gatewayv1.HTTPRoute:
-		routeGroup = gatewayv1.GroupName
-		routeKind = "HTTPRoute"
+		return gatewayv1.GroupName, "HTTPRoute", true
 	case *gatewayv1.GRPCRoute:
-		routeGroup = gatewayv1.GroupName
-		routeKind = "GRPCRoute"
+		return gatewayv1.GroupName, "GRPCRoute", true
+	case *gatewayv1alpha2.TCPRoute:
+		return gatewayv1.GroupName, "TCPRoute", true
+	case *gatewayv1alpha2.TLSRoute:
+		return gatewayv1.GroupName, "TLSRoute", true
+	case *gatewayv1alpha2.UDPRoute:
+		return gatewayv1.GroupName, "UDPRoute", true
 	default:
-		klog.Warningf("Cannot determine GroupKind for route object type %T for route %s/%s", route, routeNamespace, route.GetName())
-		return false
-	}
-
-	for _, allowedKind := range allowed.Kinds {
-		allowedGroup := gatewayv1.Group(gatewayv1.GroupName)
-		if allowedKind.Group != nil && *allowedKind.Group != "" {
-			allowedGroup = *allowedKind.Group
-		}
-		if routeKind == string(allowedKind.Kind) && routeGroup == string(allowedGroup) {
-			return true
-		}
+		return "", "", false
 	}
-
-	// The route's kind is not in the allowed list.
-	return false
 }
 
 // isAllowedByHostname checks if a route is allowed to attach to a listener
@@ -112,6 +137,8 @@ func isAllowedByHostname(listener gatewayv1.Listener, route metav1.Object) bool
 		routeHostnames = r.Spec.Hostnames
 	case *gatewayv1.GRPCRoute:
 		routeHostnames = r.Spec.Hostnames
+	case *gatewayv1alpha2.TLSRoute:
+		routeHostnames = r.Spec.Hostnames
 	default:
 		// Not a type with hostnames, so no hostname check needed.
 		return true
@@ -167,9 +194,9 @@ func getIntersectingHostnames(listener gatewayv1.Listener, routeHostnames []gate
 		if isHostnameSubset(routeHostname, listenerHostname) {
 			// A valid intersection was found. Now, determine the most specific
 			// hostname to use for the configuration.
-			if strings.HasPrefix(routeHostname, "*") && !strings.HasPrefix(listenerHostname, "*") {
-				// If the route is a wildcard and the listener is specific, the listener's
-				// specific hostname is the most restrictive result.
+			if strings.HasPrefix(routeHostname, "*") && (!strings.HasPrefix(listenerHostname, "*") || len(listenerHostname) > len(routeHostname)) {
+				// If the route is a wildcard and the listener is specific or a narrower
+				// wildcard, the listener's hostname is the most restrictive result.
 				intersection.Insert(listenerHostname)
 			} else {
 				// In all other valid cases (exact match, specific route on a wildcard listener),
@@ -180,7 +207,7 @@ func getIntersectingHostnames(listener gatewayv1.Listener, routeHostnames []gate
 	}
 
 	// Return the unique set of resulting hostnames.
-	return intersection.UnsortedList()
+	return sets.List(intersection)
 }
 
 // isHostnameSubset checks if a route hostname is a valid subset of a listener hostname,
@@ -196,11 +223,11 @@ func isHostnameSubset(routeHostname, listenerHostname string) bool {
 		// Use the part of the string including the dot as the suffix.
 		listenerSuffix := listenerHostname[1:] // e.g., ".example.com"
 
-		// Case 2a: Route also has a wildcard (e.g., "*.foo.example.com").
-		// The route's suffix must be identical to or a sub-suffix of the listener's.
+		// Case 2a: Route also has a wildcard (e.g., "*.foo.example.com" or "*.com").
+		// The wildcards intersect if either suffix contains the other.
 		if strings.HasPrefix(routeHostname, "*.") {
 			routeSuffix := routeHostname[1:] // e.g., ".foo.example.com"
-			return strings.HasSuffix(routeSuffix, listenerSuffix)
+			return strings.HasSuffix(routeSuffix, listenerSuffix) || strings.HasSuffix(listenerSuffix, routeSuffix)
 		}
 
 		// Case 2b: Route is specific (e.g., "foo.example.com").
@@ -210,17 +237,13 @@ func isHostnameSubset(routeHostname, listenerHostname string) bool {
 		return strings.HasSuffix(routeHostname, listenerSuffix)
 	}
 
-	// Rule 3: Route has a wildcard (e.g., "*.example.com").
+	// Rule 3: Route has a wildcard (e.g., "*.example.com") and the listener is specific.
 	if strings.HasPrefix(routeHostname, "*.") {
 		routeSuffix := routeHostname[1:] // e.g., ".example.com"
-		routeDomain := routeHostname[2:] // e.g., "example.com"
 
-		// The listener must be more specific (not a wildcard).
-		if !strings.HasPrefix(listenerHostname, "*.") {
-			// The listener hostname must be the parent domain or a subdomain.
-			// e.g., "example.com" or "foo.example.com" are subsets of "*.example.com".
-			return listenerHostname == routeDomain || strings.HasSuffix(listenerHostname, routeSuffix)
-		}
+		// The listener hostname must be a subdomain, e.g. "foo.example.com" is a subset of
+		// "*.example.com". A wildcard never matches the parent domain "example.com" itself.
+		return strings.HasSuffix(listenerHostname, routeSuffix)
 	}
 
 	return false
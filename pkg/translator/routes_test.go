@@ -0,0 +1,183 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateHTTPRouteVirtualHosts(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	api := testHTTPRoute("api", "gw", testBackendRef("backend", 80))
+	api.Spec.Hostnames = []gatewayv1.Hostname{"api.example.com"}
+	wildcard := testHTTPRoute("wildcard", "gw", testBackendRef("backend", 80))
+	wildcard.Spec.Hostnames = []gatewayv1.Hostname{"*.example.com"}
+	catchAll := testHTTPRoute("any", "gw", testBackendRef("backend", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), api, wildcard, catchAll)
+	resources := translateGateway(t, tl, gw)
+	routeConfiguration := findRouteConfiguration(t, resources, "route-80")
+
+	// Each hostname gets a virtual host of its own, with the wildcard kept as is, and routes
+	// without hostnames attach to the catch-all virtual host.
+	for _, tc := range []struct {
+		domain string
+		route  string
+	}{
+		{domain: "api.example.com", route: "default-api-rule0-match0"},
+		{domain: "*.example.com", route: "default-wildcard-rule0-match0"},
+		{domain: "*", route: "default-any-rule0-match0"},
+	} {
+		vh := findVirtualHost(t, routeConfiguration, tc.domain)
+		if !slices.Equal(vh.Domains, []string{tc.domain}) {
+			t.Errorf("virtual host %s domains = %v, want [%s]", vh.Name, vh.Domains, tc.domain)
+		}
+		if names, want := routeNames(vh), []string{tc.route, vh.Name + "-not-found"}; !slices.Equal(names, want) {
+			t.Errorf("virtual host %s routes = %v, want %v", vh.Name, names, want)
+		}
+	}
+	if len(routeConfiguration.VirtualHosts) != 3 {
+		t.Errorf("route-80 has %d virtual hosts, want 3", len(routeConfiguration.VirtualHosts))
+	}
+}
+
+func TestGetIntersectingHostnames(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		listenerHostname gatewayv1.Hostname
+		routeHostnames   []gatewayv1.Hostname
+		want             []string
+	}{
+		{name: "no hostnames", want: []string{"*"}},
+		{name: "route hostname only", routeHostnames: []gatewayv1.Hostname{"*.example.com"}, want: []string{"*.example.com"}},
+		{name: "listener hostname only", listenerHostname: "*.example.com", want: []string{"*.example.com"}},
+		{name: "specific route on wildcard listener", listenerHostname: "*.example.com", routeHostnames: []gatewayv1.Hostname{"api.example.com"}, want: []string{"api.example.com"}},
+		{name: "wildcard route on specific listener", listenerHostname: "api.example.com", routeHostnames: []gatewayv1.Hostname{"*.example.com"}, want: []string{"api.example.com"}},
+		{name: "narrower wildcard route", listenerHostname: "*.example.com", routeHostnames: []gatewayv1.Hostname{"*.api.example.com"}, want: []string{"*.api.example.com"}},
+		{name: "non-overlapping", listenerHostname: "*.example.com", routeHostnames: []gatewayv1.Hostname{"api.example.org"}},
+		{name: "partially overlapping", listenerHostname: "*.example.com", routeHostnames: []gatewayv1.Hostname{"api.example.org", "www.example.com"}, want: []string{"www.example.com"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listener := httpListener("http", 80)
+			if tc.listenerHostname != "" {
+				listener.Hostname = &tc.listenerHostname
+			}
+			if got := getIntersectingHostnames(listener, tc.routeHostnames); !slices.Equal(got, tc.want) {
+				t.Errorf("getIntersectingHostnames(%q, %v) = %v, want %v", tc.listenerHostname, tc.routeHostnames, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTranslateWildcardListenerHostname(t *testing.T) {
+	listener := httpListener("http", 80)
+	listener.Hostname = ptr(gatewayv1.Hostname("*.example.com"))
+	gw := testGateway("gw", listener)
+	api := testHTTPRoute("api", "gw", testBackendRef("backend", 80))
+	api.Spec.Hostnames = []gatewayv1.Hostname{"api.example.com"}
+	other := testHTTPRoute("other", "gw", testBackendRef("backend", 80))
+	other.Spec.Hostnames = []gatewayv1.Hostname{"api.example.org"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), api, other)
+	resources, _, routeStatuses, err := tl.buildEnvoyResourcesForGateway(gw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The route hostname within the wildcard of the listener gets a virtual host of its own.
+	routeConfiguration := findRouteConfiguration(t, resources, "route-80")
+	vh := findVirtualHost(t, routeConfiguration, "api.example.com")
+	if names := routeNames(vh); !slices.Contains(names, "default-api-rule0-match0") {
+		t.Errorf("virtual host %s routes = %v, want default-api-rule0-match0", vh.Name, names)
+	}
+	if len(routeConfiguration.VirtualHosts) != 1 {
+		t.Errorf("route-80 has %d virtual hosts, want only api.example.com", len(routeConfiguration.VirtualHosts))
+	}
+
+	// The route without a hostname in common with the listener is not accepted.
+	for _, tc := range []struct {
+		route      string
+		wantStatus metav1.ConditionStatus
+		wantReason gatewayv1.RouteConditionReason
+	}{
+		{route: "api", wantStatus: metav1.ConditionTrue, wantReason: gatewayv1.RouteReasonAccepted},
+		{route: "other", wantStatus: metav1.ConditionFalse, wantReason: gatewayv1.RouteReasonNoMatchingListenerHostname},
+	} {
+		parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: tc.route}]
+		if len(parents) != 1 {
+			t.Fatalf("HTTPRoute %s parent statuses = %v, want 1", tc.route, parents)
+		}
+		condition := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+		if condition == nil || condition.Status != tc.wantStatus || condition.Reason != string(tc.wantReason) {
+			t.Errorf("HTTPRoute %s Accepted = %v, want %s/%s", tc.route, condition, tc.wantStatus, tc.wantReason)
+		}
+	}
+}
+
+func TestTranslateAllowedRoutesNamespaces(t *testing.T) {
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}}},
+	}
+	for _, tc := range []struct {
+		name           string
+		namespaces     *gatewayv1.RouteNamespaces
+		wantNamespaces []string
+	}{
+		{name: "same", namespaces: &gatewayv1.RouteNamespaces{From: ptr(gatewayv1.NamespacesFromSame)}, wantNamespaces: []string{testNamespace}},
+		{name: "all", namespaces: &gatewayv1.RouteNamespaces{From: ptr(gatewayv1.NamespacesFromAll)}, wantNamespaces: []string{testNamespace, "team-a", "team-b"}},
+		{
+			name: "selector",
+			namespaces: &gatewayv1.RouteNamespaces{
+				From:     ptr(gatewayv1.NamespacesFromSelector),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+			wantNamespaces: []string{"team-a"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listener := httpListener("http", 80)
+			listener.AllowedRoutes = &gatewayv1.AllowedRoutes{Namespaces: tc.namespaces}
+			gw := testGateway("gw", listener)
+			objs := []runtime.Object{testGatewayClass(), gw}
+			for _, namespace := range namespaces {
+				route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+				route.Namespace = namespace.Name
+				route.Spec.ParentRefs[0].Namespace = ptr(gatewayv1.Namespace(testNamespace))
+				service := testService("backend", 80)
+				service.Namespace = namespace.Name
+				objs = append(objs, namespace, route, service)
+			}
+			tl := newTestTranslator(t, Options{}, objs...)
+			resources, _, routeStatuses, err := tl.buildEnvoyResourcesForGateway(gw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+			for _, namespace := range namespaces {
+				allowed := slices.Contains(tc.wantNamespaces, namespace.Name)
+				if got := slices.Contains(routeNames(vh), namespace.Name+"-web-rule0-match0"); got != allowed {
+					t.Errorf("route of namespace %s in virtual host = %v, want %v", namespace.Name, got, allowed)
+				}
+				wantStatus, wantReason := metav1.ConditionTrue, gatewayv1.RouteReasonAccepted
+				if !allowed {
+					wantStatus, wantReason = metav1.ConditionFalse, gatewayv1.RouteReasonNotAllowedByListeners
+				}
+				parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: namespace.Name, Name: "web"}]
+				if len(parents) != 1 {
+					t.Fatalf("HTTPRoute %s/web parent statuses = %v, want 1", namespace.Name, parents)
+				}
+				condition := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+				if condition == nil || condition.Status != wantStatus || condition.Reason != string(wantReason) {
+					t.Errorf("HTTPRoute %s/web Accepted = %v, want %s/%s", namespace.Name, condition, wantStatus, wantReason)
+				}
+			}
+		})
+	}
+}
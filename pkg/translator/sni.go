@@ -0,0 +1,119 @@
+package translator
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// buildCertificateFilterChains splits an HTTPS listener with several certificateRefs into one
+// filter chain per certificate, matched by the DNS names of the certificate, so that Envoy
+// selects the certificate by SNI. The filter chain of the listener with all certificates is
+// kept for the other server names. Names in reservedServerNames, such as the hostnames of
+// the listeners on the port, are left to the filter chains that already match them.
+func (t *Translator) buildCertificateFilterChains(
+	gateway *gatewayv1.Gateway,
+	lis gatewayv1.Listener,
+	filterChain *listenerv3.FilterChain,
+	reservedServerNames sets.Set[string],
+	envoySecrets map[string]envoyproxytypes.Resource,
+) ([]*listenerv3.FilterChain, error) {
+	if lis.TLS == nil || len(lis.TLS.CertificateRefs) < 2 {
+		return nil, nil
+	}
+	// A listener for a single hostname can't tell its certificates apart by SNI.
+	listenerHostname := ""
+	if lis.Hostname != nil {
+		listenerHostname = string(*lis.Hostname)
+	}
+	if listenerHostname != "" && !strings.HasPrefix(listenerHostname, "*.") {
+		return nil, nil
+	}
+
+	claimedServerNames := reservedServerNames.Clone()
+	var filterChains []*listenerv3.FilterChain
+	for _, certRef := range lis.TLS.CertificateRefs {
+		secretNamespace := gateway.Namespace
+		if certRef.Namespace != nil {
+			secretNamespace = string(*certRef.Namespace)
+		}
+		// The TLS context of the certificate is built first, which verifies that the
+		// certificateRef is permitted and resolves.
+		certListener := *lis.DeepCopy()
+		certListener.TLS.CertificateRefs = []gatewayv1.SecretObjectReference{certRef}
+		tlsContext, err := t.buildDownstreamTLSContext(context.Background(), gateway, certListener, envoySecrets)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := t.secretLister.Secrets(secretNamespace).Get(string(certRef.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", secretNamespace, certRef.Name, err)
+		}
+		dnsNames, err := certificateDNSNames(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		var serverNames []string
+		for _, name := range dnsNames {
+			if listenerHostname != "" && !isHostnameSubset(name, listenerHostname) {
+				continue
+			}
+			if claimedServerNames.Has(name) {
+				continue
+			}
+			claimedServerNames.Insert(name)
+			serverNames = append(serverNames, name)
+		}
+		if len(serverNames) == 0 {
+			continue
+		}
+		sort.Strings(serverNames)
+
+		certFilterChain := proto.Clone(filterChain).(*listenerv3.FilterChain)
+		certFilterChain.FilterChainMatch = &listenerv3.FilterChainMatch{ServerNames: serverNames}
+		certFilterChain.TransportSocket = &corev3.TransportSocket{
+			Name: "envoy.transport_sockets.tls",
+			ConfigType: &corev3.TransportSocket_TypedConfig{
+				TypedConfig: tlsContext,
+			},
+		}
+		filterChains = append(filterChains, certFilterChain)
+	}
+	return filterChains, nil
+}
+
+// listenerHostnames returns the hostnames of the listeners.
+func listenerHostnames(listeners []gatewayv1.Listener) sets.Set[string] {
+	hostnames := sets.New[string]()
+	for _, lis := range listeners {
+		if lis.Hostname != nil && *lis.Hostname != "" {
+			hostnames.Insert(string(*lis.Hostname))
+		}
+	}
+	return hostnames
+}
+
+// certificateDNSNames returns the DNS names of the leaf certificate in a TLS Secret.
+func certificateDNSNames(secret *corev1.Secret) ([]string, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return nil, fmt.Errorf("secret %s/%s key %s does not contain a valid PEM-encoded certificate chain", secret.Namespace, secret.Name, corev1.TLSCertKey)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the certificate of secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return certificate.DNSNames, nil
+}
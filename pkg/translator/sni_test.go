@@ -0,0 +1,81 @@
+package translator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testCertificateSecret returns a TLS Secret with a self-signed certificate for the DNS names.
+func testCertificateSecret(t *testing.T, name string, dnsNames ...string) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
+
+func TestTranslateCertificateFilterChains(t *testing.T) {
+	gw := testGateway("gw", httpsListener("https", 443, "api-cert", "www-cert"))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testCertificateSecret(t, "api-cert", "api.example.com"),
+		testCertificateSecret(t, "www-cert", "www.example.com"),
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	wantSecrets := map[string]string{
+		"api.example.com": "default/api-cert",
+		"www.example.com": "default/www-cert",
+	}
+	listener := findListener(t, resources, "listener-443")
+	for serverName, wantSecret := range wantSecrets {
+		i := slices.IndexFunc(listener.FilterChains, func(fc *listenerv3.FilterChain) bool {
+			return slices.Equal(fc.GetFilterChainMatch().GetServerNames(), []string{serverName})
+		})
+		if i < 0 {
+			t.Errorf("no filter chain matching server name %s", serverName)
+			continue
+		}
+		sdsConfigs := filterChainTLSContext(t, listener.FilterChains[i]).CommonTlsContext.TlsCertificateSdsSecretConfigs
+		if len(sdsConfigs) != 1 || sdsConfigs[0].Name != wantSecret {
+			t.Errorf("filter chain of %s references secrets %v, want only %s", serverName, sdsConfigs, wantSecret)
+		}
+	}
+	if len(listener.FilterChains) != len(wantSecrets)+1 {
+		t.Errorf("listener has %d filter chains, want one per certificate and one for other server names", len(listener.FilterChains))
+	}
+}
@@ -0,0 +1,47 @@
+package translator
+
+import (
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+)
+
+// The Linux socket option levels and names of TCP keepalive. They are spelled out instead of
+// taken from the syscall package, as Envoy may run on another platform than the generator.
+const (
+	solSocket       = 1
+	soKeepalive     = 9
+	ipprotoTCP      = 6
+	tcpKeepIdle     = 4
+	tcpKeepInterval = 5
+	tcpKeepCount    = 6
+)
+
+// buildListenerSocketOptions builds the socket options of a TCP listener that enable TCP
+// keepalive on its downstream connections, which inherit them from the listening socket. It
+// returns nil if keepalive is not enabled.
+func buildListenerSocketOptions(keepalive TCPKeepalive) []*corev3.SocketOption {
+	if keepalive == (TCPKeepalive{}) {
+		return nil
+	}
+	socketOption := func(description string, level, name, value int64) *corev3.SocketOption {
+		return &corev3.SocketOption{
+			Description: description,
+			Level:       level,
+			Name:        name,
+			Value:       &corev3.SocketOption_IntValue{IntValue: value},
+			State:       corev3.SocketOption_STATE_PREBIND,
+		}
+	}
+	socketOptions := []*corev3.SocketOption{socketOption("SO_KEEPALIVE", solSocket, soKeepalive, 1)}
+	if keepalive.Time != 0 {
+		socketOptions = append(socketOptions, socketOption("TCP_KEEPIDLE", ipprotoTCP, tcpKeepIdle, int64(keepalive.Time/time.Second)))
+	}
+	if keepalive.Interval != 0 {
+		socketOptions = append(socketOptions, socketOption("TCP_KEEPINTVL", ipprotoTCP, tcpKeepInterval, int64(keepalive.Interval/time.Second)))
+	}
+	if keepalive.Probes != 0 {
+		socketOptions = append(socketOptions, socketOption("TCP_KEEPCNT", ipprotoTCP, tcpKeepCount, int64(keepalive.Probes)))
+	}
+	return socketOptions
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTranslateListenerTCPKeepalive(t *testing.T) {
+	keepaliveOption := func(description string, level, name, value int64) *corev3.SocketOption {
+		return &corev3.SocketOption{
+			Description: description,
+			Level:       level,
+			Name:        name,
+			Value:       &corev3.SocketOption_IntValue{IntValue: value},
+			State:       corev3.SocketOption_STATE_PREBIND,
+		}
+	}
+	for _, tc := range []struct {
+		name      string
+		keepalive TCPKeepalive
+		want      []*corev3.SocketOption
+	}{
+		{name: "disabled"},
+		{
+			name:      "all settings",
+			keepalive: TCPKeepalive{Time: time.Minute, Interval: 10 * time.Second, Probes: 3},
+			want: []*corev3.SocketOption{
+				keepaliveOption("SO_KEEPALIVE", 1, 9, 1),
+				keepaliveOption("TCP_KEEPIDLE", 6, 4, 60),
+				keepaliveOption("TCP_KEEPINTVL", 6, 5, 10),
+				keepaliveOption("TCP_KEEPCNT", 6, 6, 3),
+			},
+		},
+		{
+			name:      "probes only",
+			keepalive: TCPKeepalive{Probes: 5},
+			want: []*corev3.SocketOption{
+				keepaliveOption("SO_KEEPALIVE", 1, 9, 1),
+				keepaliveOption("TCP_KEEPCNT", 6, 6, 5),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80), tcpListener("db", 5432))
+			tl := newTestTranslator(t, Options{ListenerTCPKeepalive: tc.keepalive}, testGatewayClass(), gw,
+				testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)),
+				testService("postgres", 5432), testTCPRoute("db", "gw", testBackendRef("postgres", 5432)))
+			resources := translateGateway(t, tl, gw)
+
+			for _, name := range []string{"listener-80", "listener-5432"} {
+				got := findListener(t, resources, name).SocketOptions
+				if len(got) != len(tc.want) {
+					t.Fatalf("%s socket options = %v, want %v", name, got, tc.want)
+				}
+				for i := range got {
+					if !proto.Equal(got[i], tc.want[i]) {
+						t.Errorf("%s socket option %d = %v, want %v", name, i, got[i], tc.want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateListenerTCPKeepaliveInvalid(t *testing.T) {
+	for _, keepalive := range []TCPKeepalive{
+		{Time: 1500 * time.Millisecond},
+		{Interval: -time.Second},
+	} {
+		options := Options{ListenerTCPKeepalive: keepalive}
+		if err := options.Validate(); err == nil {
+			t.Errorf("Validate() of listener keepalive %+v = nil, want an error", keepalive)
+		}
+	}
+}
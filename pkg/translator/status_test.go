@@ -0,0 +1,199 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+)
+
+// gatewayStatusPatches returns the status patches of the Gateways sent through the client.
+func gatewayStatusPatches(client *gatewayfake.Clientset) []k8stesting.PatchAction {
+	var patches []k8stesting.PatchAction
+	for _, action := range client.Actions() {
+		if patch, ok := action.(k8stesting.PatchAction); ok && action.GetResource().Resource == "gateways" && action.GetSubresource() == "status" {
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+func TestTranslateGatewayStatus(t *testing.T) {
+	gw := testGateway("gw", httpsListener("valid", 443, "cert"), httpsListener("invalid", 8443, "missing"))
+	client := newFakeGatewayClient(t, gw)
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testTLSSecret("cert"))
+	tl.gwClient = client
+	if _, err := tl.TranslateGatewayToXDS(context.Background(), gw); err != nil {
+		t.Fatal(err)
+	}
+
+	patches := gatewayStatusPatches(client)
+	if len(patches) != 1 {
+		t.Fatalf("got %d Gateway status patches, want 1", len(patches))
+	}
+	patched := &gatewayv1.Gateway{}
+	if err := json.Unmarshal(patches[0].GetPatch(), patched); err != nil {
+		t.Fatal(err)
+	}
+	listeners := make(map[gatewayv1.SectionName][]metav1.Condition)
+	for _, listenerStatus := range patched.Status.Listeners {
+		listeners[listenerStatus.Name] = listenerStatus.Conditions
+	}
+
+	for _, tc := range []struct {
+		listener gatewayv1.SectionName
+		status   metav1.ConditionStatus
+		reason   gatewayv1.ListenerConditionReason
+	}{
+		{listener: "valid", status: metav1.ConditionTrue, reason: gatewayv1.ListenerReasonResolvedRefs},
+		{listener: "invalid", status: metav1.ConditionFalse, reason: gatewayv1.ListenerReasonInvalidCertificateRef},
+	} {
+		t.Run(string(tc.listener), func(t *testing.T) {
+			conditions, ok := listeners[tc.listener]
+			if !ok {
+				t.Fatalf("patch has no status for listener %s: %s", tc.listener, patches[0].GetPatch())
+			}
+			condition := meta.FindStatusCondition(conditions, string(gatewayv1.ListenerConditionResolvedRefs))
+			if condition == nil {
+				t.Fatalf("listener %s has no ResolvedRefs condition", tc.listener)
+			}
+			if condition.Status != tc.status || condition.Reason != string(tc.reason) {
+				t.Errorf("ResolvedRefs = %s/%s, want %s/%s", condition.Status, condition.Reason, tc.status, tc.reason)
+			}
+		})
+	}
+
+	programmed := meta.FindStatusCondition(patched.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	if programmed == nil || programmed.Status != metav1.ConditionFalse {
+		t.Errorf("Programmed = %v, want False as a listener is not programmed", programmed)
+	}
+}
+
+// A Gateway whose translation failed is not programmed, even if its listeners are.
+func TestTranslateGatewayStatusError(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	gw.Annotations = map[string]string{proxyProtocolAnnotation: "sometimes"}
+	client := newFakeGatewayClient(t, gw)
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw)
+	tl.gwClient = client
+	if _, err := tl.TranslateGatewayToXDS(context.Background(), gw); err == nil {
+		t.Fatal("TranslateGatewayToXDS() error = nil, want the invalid annotation")
+	}
+
+	patches := gatewayStatusPatches(client)
+	if len(patches) != 1 {
+		t.Fatalf("got %d Gateway status patches, want 1", len(patches))
+	}
+	patched := &gatewayv1.Gateway{}
+	if err := json.Unmarshal(patches[0].GetPatch(), patched); err != nil {
+		t.Fatal(err)
+	}
+	programmed := meta.FindStatusCondition(patched.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	if programmed == nil || programmed.Status != metav1.ConditionFalse || programmed.Reason != "ReconciliationError" {
+		t.Errorf("Programmed = %v, want False/ReconciliationError", programmed)
+	}
+}
+
+func TestUpdateRouteStatuses(t *testing.T) {
+	listener := httpListener("http", 80)
+	listener.Hostname = ptr(gatewayv1.Hostname("*.example.com"))
+	gw := testGateway("gw", listener)
+	missingBackend := testHTTPRoute("missing-backend", "gw", testBackendRef("missing", 80))
+	otherHostname := testHTTPRoute("other-hostname", "gw", testBackendRef("backend", 80))
+	otherHostname.Spec.Hostnames = []gatewayv1.Hostname{"www.example.org"}
+	// The status written by another controller for a parent of its own is preserved.
+	foreignParent := gatewayv1.RouteParentStatus{
+		ParentRef:      gatewayv1.ParentReference{Name: "other-gw"},
+		ControllerName: "example.com/other-controller",
+		Conditions: []metav1.Condition{{
+			Type:               string(gatewayv1.RouteConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gatewayv1.RouteReasonAccepted),
+			LastTransitionTime: metav1.Now(),
+		}},
+	}
+	otherHostname.Status.Parents = []gatewayv1.RouteParentStatus{foreignParent}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), missingBackend, otherHostname)
+	_, _, routeStatuses, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+	client := newFakeGatewayClient(t, missingBackend, otherHostname)
+	if err := UpdateRouteStatuses(context.Background(), client, routeStatuses); err != nil {
+		t.Fatalf("UpdateRouteStatuses() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		route         string
+		conditionType gatewayv1.RouteConditionType
+		wantReason    gatewayv1.RouteConditionReason
+		wantParents   int
+	}{
+		{route: "missing-backend", conditionType: gatewayv1.RouteConditionResolvedRefs, wantReason: gatewayv1.RouteReasonBackendNotFound, wantParents: 1},
+		{route: "other-hostname", conditionType: gatewayv1.RouteConditionAccepted, wantReason: gatewayv1.RouteReasonNoMatchingListenerHostname, wantParents: 2},
+	} {
+		t.Run(tc.route, func(t *testing.T) {
+			route, err := client.GatewayV1().HTTPRoutes(testNamespace).Get(context.Background(), tc.route, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(route.Status.Parents) != tc.wantParents {
+				t.Fatalf("got %d parent statuses, want %d: %v", len(route.Status.Parents), tc.wantParents, route.Status.Parents)
+			}
+			var parent *gatewayv1.RouteParentStatus
+			for i := range route.Status.Parents {
+				if route.Status.Parents[i].ParentRef.Name == "gw" {
+					parent = &route.Status.Parents[i]
+				} else if route.Status.Parents[i].ControllerName != foreignParent.ControllerName {
+					t.Errorf("unexpected parent status %v", route.Status.Parents[i])
+				}
+			}
+			if parent == nil {
+				t.Fatalf("no parent status for Gateway gw in %v", route.Status.Parents)
+			}
+			if parent.ControllerName != DefaultControllerName {
+				t.Errorf("controller name = %q, want %q", parent.ControllerName, DefaultControllerName)
+			}
+			condition := meta.FindStatusCondition(parent.Conditions, string(tc.conditionType))
+			if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != string(tc.wantReason) {
+				t.Errorf("%s = %v, want False/%s", tc.conditionType, condition, tc.wantReason)
+			}
+		})
+	}
+}
+
+// TestTranslateAttachedRoutes checks that the routes rejected for their hostname or for a
+// backend that no ReferenceGrant permits don't count towards the attached routes.
+func TestTranslateAttachedRoutes(t *testing.T) {
+	listener := httpListener("http", 80)
+	listener.Hostname = ptr(gatewayv1.Hostname("*.example.com"))
+	gw := testGateway("gw", listener)
+	notPermittedBackend := testBackendRef("backend", 80)
+	notPermittedBackend.Namespace = ptr(gatewayv1.Namespace("other"))
+	notPermitted := testHTTPRoute("not-permitted", "gw", notPermittedBackend)
+	otherHostname := testHTTPRoute("other-hostname", "gw", testBackendRef("backend", 80))
+	otherHostname.Spec.Hostnames = []gatewayv1.Hostname{"www.example.org"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)), testHTTPRoute("api", "gw", testBackendRef("backend", 80)),
+		notPermitted, otherHostname)
+	_, listenerStatuses, routeStatuses, err := tl.buildEnvoyResourcesForGateway(gw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listenerStatuses) != 1 || listenerStatuses[0].AttachedRoutes != 2 {
+		t.Errorf("listener statuses = %v, want 2 attached routes", listenerStatuses)
+	}
+	parents := routeStatuses.HTTPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "not-permitted"}]
+	if len(parents) != 1 {
+		t.Fatalf("got %d parent statuses of not-permitted, want 1", len(parents))
+	}
+	resolvedRefs := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+	if resolvedRefs == nil || resolvedRefs.Reason != string(gatewayv1.RouteReasonRefNotPermitted) {
+		t.Errorf("ResolvedRefs of not-permitted = %v, want RefNotPermitted", resolvedRefs)
+	}
+}
@@ -0,0 +1,109 @@
+package translator
+
+import (
+	"errors"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tcpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/anypb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewaylistersv1beta1 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
+)
+
+// translateTCPRoute translates a TCPRoute into an Envoy TcpProxy config.
+// TCPRoutes have no match criteria, so the backends of all rules are combined into
+// a single destination, weighted if more than one backend is referenced.
+func translateTCPRoute(
+	tcpRoute *gatewayv1alpha2.TCPRoute,
+	statPrefix string,
+	serviceLister corev1listers.ServiceLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+) (*tcpproxyv3.TcpProxy, []gatewayv1.BackendRef, metav1.Condition) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range tcpRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	return buildTCPProxy("TCPRoute", tcpRoute.Namespace, tcpRoute.Generation, statPrefix, backendRefs, serviceLister, referenceGrantLister)
+}
+
+// buildTCPProxy resolves the backends of an L4 route into an Envoy TcpProxy config.
+// The routeKind is the kind of the referencing route and is used for ReferenceGrant checks.
+func buildTCPProxy(
+	routeKind gatewayv1.Kind,
+	namespace string,
+	generation int64,
+	statPrefix string,
+	backendRefs []gatewayv1.BackendRef,
+	serviceLister corev1listers.ServiceLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+) (*tcpproxyv3.TcpProxy, []gatewayv1.BackendRef, metav1.Condition) {
+	routeAction, validBackendRefs, err := buildHTTPRouteAction(
+		routeKind,
+		namespace,
+		backendRefs,
+		clusterVariant{},
+		serviceLister,
+		referenceGrantLister,
+	)
+	condition := createSuccessCondition(generation)
+	var controllerErr *ControllerError
+	if errors.As(err, &controllerErr) {
+		condition = createFailureCondition(gatewayv1.RouteConditionReason(controllerErr.Reason), controllerErr.Message, generation)
+	}
+	if errors.Is(err, errAllBackendsZeroWeight) {
+		// No connections may be forwarded, so no proxy is configured for the route.
+		return nil, validBackendRefs, condition
+	}
+	if routeAction == nil {
+		return nil, nil, condition
+	}
+
+	return routeActionToTCPProxy(statPrefix, routeAction), validBackendRefs, condition
+}
+
+// routeActionToTCPProxy converts the cluster specifier of an HTTP route action into
+// the equivalent TcpProxy config.
+func routeActionToTCPProxy(statPrefix string, routeAction *routev3.RouteAction) *tcpproxyv3.TcpProxy {
+	tcpProxy := &tcpproxyv3.TcpProxy{
+		StatPrefix: statPrefix,
+	}
+
+	if weightedClusters := routeAction.GetWeightedClusters(); weightedClusters != nil {
+		tcpWeightedClusters := &tcpproxyv3.TcpProxy_WeightedCluster{}
+		for _, clusterWeight := range weightedClusters.Clusters {
+			// TcpProxy requires a weight of at least 1, so backends without traffic are left out.
+			if clusterWeight.GetWeight().GetValue() == 0 {
+				continue
+			}
+			tcpWeightedClusters.Clusters = append(tcpWeightedClusters.Clusters, &tcpproxyv3.TcpProxy_WeightedCluster_ClusterWeight{
+				Name:   clusterWeight.Name,
+				Weight: clusterWeight.GetWeight().GetValue(),
+			})
+		}
+		tcpProxy.ClusterSpecifier = &tcpproxyv3.TcpProxy_WeightedClusters{WeightedClusters: tcpWeightedClusters}
+	} else {
+		tcpProxy.ClusterSpecifier = &tcpproxyv3.TcpProxy_Cluster{Cluster: routeAction.GetCluster()}
+	}
+	return tcpProxy
+}
+
+// buildTCPProxyFilterChain wraps a TcpProxy config into a listener filter chain.
+func buildTCPProxyFilterChain(tcpProxy *tcpproxyv3.TcpProxy) (*listenerv3.FilterChain, error) {
+	tcpProxyAny, err := anypb.New(tcpProxy)
+	if err != nil {
+		return nil, err
+	}
+	return &listenerv3.FilterChain{
+		Filters: []*listenerv3.Filter{{
+			Name: wellknown.TCPProxy,
+			ConfigType: &listenerv3.Filter_TypedConfig{
+				TypedConfig: tcpProxyAny,
+			},
+		}},
+	}, nil
+}
@@ -0,0 +1,58 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func tcpListener(name string, port gatewayv1.PortNumber) gatewayv1.Listener {
+	return gatewayv1.Listener{Name: gatewayv1.SectionName(name), Port: port, Protocol: gatewayv1.TCPProtocolType}
+}
+
+func TestTranslateTCPRoute(t *testing.T) {
+	gw := testGateway("gw", tcpListener("db", 5432))
+	route := testTCPRoute("db", "gw", testBackendRef("postgres", 5432))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("postgres", 5432), route)
+	resources := translateGateway(t, tl, gw)
+
+	listener := findListener(t, resources, "listener-5432")
+	if len(listener.FilterChains) != 1 {
+		t.Fatalf("got %d filter chains, want 1", len(listener.FilterChains))
+	}
+	for _, filter := range listener.FilterChains[0].Filters {
+		if filter.Name == wellknown.HTTPConnectionManager {
+			t.Errorf("TCP listener has an HTTP connection manager")
+		}
+	}
+	tcpProxy := filterChainTCPProxy(t, listener.FilterChains[0])
+	if got, want := tcpProxy.GetCluster(), clusterName("postgres", 5432); got != want {
+		t.Errorf("TCP proxy cluster = %q, want %q", got, want)
+	}
+	findCluster(t, resources, clusterName("postgres", 5432))
+}
+
+func TestTranslateTCPRouteWeightedBackends(t *testing.T) {
+	gw := testGateway("gw", tcpListener("db", 5432))
+	primary, replica := testBackendRef("primary", 5432), testBackendRef("replica", 5432)
+	primary.Weight, replica.Weight = ptr(int32(3)), ptr(int32(1))
+	route := testTCPRoute("db", "gw", primary, replica)
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("primary", 5432), testService("replica", 5432), route)
+	resources := translateGateway(t, tl, gw)
+
+	tcpProxy := filterChainTCPProxy(t, findListener(t, resources, "listener-5432").FilterChains[0])
+	weights := make(map[string]uint32)
+	for _, cluster := range tcpProxy.GetWeightedClusters().GetClusters() {
+		weights[cluster.Name] = cluster.Weight
+	}
+	want := map[string]uint32{clusterName("primary", 5432): 3, clusterName("replica", 5432): 1}
+	if len(weights) != len(want) {
+		t.Fatalf("weighted clusters = %v, want %v", weights, want)
+	}
+	for name, weight := range want {
+		if weights[name] != weight {
+			t.Errorf("weight of cluster %s = %d, want %d", name, weights[name], weight)
+		}
+	}
+}
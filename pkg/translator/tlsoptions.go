@@ -0,0 +1,113 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The TLS options of a listener that override the TLS versions of the Options.
+const (
+	tlsMinVersionOption = "gateway.xds/tls-min-version"
+	tlsMaxVersionOption = "gateway.xds/tls-max-version"
+)
+
+// DefaultTLSMinVersion is the minimum TLS version of listeners that terminate TLS.
+const DefaultTLSMinVersion = "1.2"
+
+var tlsVersions = map[string]tlsv3.TlsParameters_TlsProtocol{
+	"1.0": tlsv3.TlsParameters_TLSv1_0,
+	"1.1": tlsv3.TlsParameters_TLSv1_1,
+	"1.2": tlsv3.TlsParameters_TLSv1_2,
+	"1.3": tlsv3.TlsParameters_TLSv1_3,
+}
+
+// parseTLSVersion parses a TLS version such as "1.2". An empty version is left to Envoy.
+func parseTLSVersion(version string) (tlsv3.TlsParameters_TlsProtocol, error) {
+	if version == "" {
+		return tlsv3.TlsParameters_TLS_AUTO, nil
+	}
+	protocol, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q, must be one of 1.0, 1.1, 1.2 or 1.3", version)
+	}
+	return protocol, nil
+}
+
+// buildTLSParameters builds the TLS parameters of a listener from the given minimum and
+// maximum TLS versions. It returns nil if both are left to Envoy.
+func buildTLSParameters(minVersion, maxVersion string) (*tlsv3.TlsParameters, error) {
+	minProtocol, err := parseTLSVersion(minVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minimum TLS version: %w", err)
+	}
+	maxProtocol, err := parseTLSVersion(maxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maximum TLS version: %w", err)
+	}
+	if minProtocol != tlsv3.TlsParameters_TLS_AUTO && maxProtocol != tlsv3.TlsParameters_TLS_AUTO && minProtocol > maxProtocol {
+		return nil, fmt.Errorf("minimum TLS version %s is greater than maximum TLS version %s", minVersion, maxVersion)
+	}
+	if minProtocol == tlsv3.TlsParameters_TLS_AUTO && maxProtocol == tlsv3.TlsParameters_TLS_AUTO {
+		return nil, nil
+	}
+	return &tlsv3.TlsParameters{
+		TlsMinimumProtocolVersion: minProtocol,
+		TlsMaximumProtocolVersion: maxProtocol,
+	}, nil
+}
+
+// tlsMinVersion returns the minimum TLS version of the options, or DefaultTLSMinVersion.
+func (o Options) tlsMinVersion() string {
+	if o.TLSMinVersion == "" {
+		return DefaultTLSMinVersion
+	}
+	return o.TLSMinVersion
+}
+
+// listenerTLSParameters returns the TLS parameters of a listener that terminates TLS. The
+// TLS versions of the options can be overridden by the TLS options of the listener, and the
+// minimum version defaults to DefaultTLSMinVersion.
+func listenerTLSParameters(lis gatewayv1.Listener, options Options) (*tlsv3.TlsParameters, error) {
+	minVersion := options.tlsMinVersion()
+	maxVersion := options.TLSMaxVersion
+	if value, ok := lis.TLS.Options[tlsMinVersionOption]; ok {
+		minVersion = string(value)
+	}
+	if value, ok := lis.TLS.Options[tlsMaxVersionOption]; ok {
+		maxVersion = string(value)
+	}
+	return buildTLSParameters(minVersion, maxVersion)
+}
+
+// alpnProtocolsOption is the TLS option of a listener that overrides the comma-separated
+// ALPN protocols offered when terminating TLS, e.g. "http/1.1" to disable HTTP/2.
+const alpnProtocolsOption = "gateway.xds/alpn-protocols"
+
+// defaultALPNProtocols are the ALPN protocols of HTTPS listeners, which serve both HTTP/2
+// and HTTP/1.1.
+var defaultALPNProtocols = []string{"h2", "http/1.1"}
+
+// listenerALPNProtocols returns the ALPN protocols of a listener that terminates TLS. Only
+// HTTPS listeners offer ALPN protocols by default.
+func listenerALPNProtocols(lis gatewayv1.Listener) ([]string, error) {
+	value, ok := lis.TLS.Options[alpnProtocolsOption]
+	if !ok {
+		if lis.Protocol == gatewayv1.HTTPSProtocolType {
+			return defaultALPNProtocols, nil
+		}
+		return nil, nil
+	}
+	protocols := splitList(string(value))
+	if len(protocols) == 0 {
+		return nil, fmt.Errorf("TLS option %s: no ALPN protocols specified", alpnProtocolsOption)
+	}
+	for _, protocol := range protocols {
+		if strings.ContainsAny(protocol, " \t") {
+			return nil, fmt.Errorf("TLS option %s: invalid ALPN protocol %q", alpnProtocolsOption, protocol)
+		}
+	}
+	return protocols, nil
+}
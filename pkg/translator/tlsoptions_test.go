@@ -0,0 +1,104 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// translateTLSContext translates a Gateway with the HTTPS listener on port 443, which
+// terminates TLS with the web-cert Secret, and returns the TLS context of the listener.
+func translateTLSContext(t *testing.T, options Options, listener gatewayv1.Listener) *tlsv3.DownstreamTlsContext {
+	t.Helper()
+	gw := testGateway("gw", listener)
+	tl := newTestTranslator(t, options, testGatewayClass(), gw, testTLSSecret("web-cert"),
+		testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+	return filterChainTLSContext(t, findListener(t, resources, "listener-443").FilterChains[0])
+}
+
+func TestTranslateALPNProtocols(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		options map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue
+		want    []string
+	}{
+		{name: "default", want: []string{"h2", "http/1.1"}},
+		{name: "HTTP/1.1 only", options: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{alpnProtocolsOption: "http/1.1"}, want: []string{"http/1.1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listener := httpsListener("https", 443, "web-cert")
+			listener.TLS.Options = tc.options
+			tlsContext := translateTLSContext(t, Options{}, listener)
+			if got := tlsContext.CommonTlsContext.AlpnProtocols; !slices.Equal(got, tc.want) {
+				t.Errorf("ALPN protocols = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListenerALPNProtocols(t *testing.T) {
+	// Listeners that terminate TLS for TCP connections don't negotiate HTTP versions.
+	tlsListener := httpsListener("tls", 443, "web-cert")
+	tlsListener.Protocol = gatewayv1.TLSProtocolType
+	if protocols, err := listenerALPNProtocols(tlsListener); err != nil || protocols != nil {
+		t.Errorf("listenerALPNProtocols() of a TLS listener = %v, %v, want none", protocols, err)
+	}
+
+	for _, value := range []gatewayv1.AnnotationValue{"", " , ", "h2 ,http 1.1"} {
+		listener := httpsListener("https", 443, "web-cert")
+		listener.TLS.Options = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{alpnProtocolsOption: value}
+		if protocols, err := listenerALPNProtocols(listener); err == nil {
+			t.Errorf("listenerALPNProtocols() of option %q = %v, want an error", value, protocols)
+		}
+	}
+}
+
+func TestTranslateTLSVersions(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		options    Options
+		tlsOptions map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue
+		wantMin    tlsv3.TlsParameters_TlsProtocol
+		wantMax    tlsv3.TlsParameters_TlsProtocol
+	}{
+		{name: "default", wantMin: tlsv3.TlsParameters_TLSv1_2, wantMax: tlsv3.TlsParameters_TLS_AUTO},
+		{name: "options", options: Options{TLSMinVersion: "1.3"}, wantMin: tlsv3.TlsParameters_TLSv1_3, wantMax: tlsv3.TlsParameters_TLS_AUTO},
+		{
+			name:       "listener options",
+			options:    Options{TLSMinVersion: "1.2"},
+			tlsOptions: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{tlsMinVersionOption: "1.3", tlsMaxVersionOption: "1.3"},
+			wantMin:    tlsv3.TlsParameters_TLSv1_3,
+			wantMax:    tlsv3.TlsParameters_TLSv1_3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listener := httpsListener("https", 443, "web-cert")
+			listener.TLS.Options = tc.tlsOptions
+			params := translateTLSContext(t, tc.options, listener).CommonTlsContext.TlsParams
+			if params == nil {
+				t.Fatal("TLS context has no TLS parameters")
+			}
+			if params.TlsMinimumProtocolVersion != tc.wantMin || params.TlsMaximumProtocolVersion != tc.wantMax {
+				t.Errorf("TLS versions = %v-%v, want %v-%v", params.TlsMinimumProtocolVersion, params.TlsMaximumProtocolVersion, tc.wantMin, tc.wantMax)
+			}
+		})
+	}
+}
+
+func TestBuildTLSParametersInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		minVersion, maxVersion string
+	}{
+		{minVersion: "1.4"},
+		{minVersion: "TLSv1.2"},
+		{minVersion: "1.2", maxVersion: "2"},
+		{minVersion: "1.3", maxVersion: "1.2"},
+	} {
+		if params, err := buildTLSParameters(tc.minVersion, tc.maxVersion); err == nil {
+			t.Errorf("buildTLSParameters(%q, %q) = %v, want an error", tc.minVersion, tc.maxVersion, params)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package translator
+
+import (
+	"sort"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	tcpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewaylistersv1beta1 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
+)
+
+// translateTLSRoute translates a TLSRoute into an Envoy TcpProxy config. Like TCPRoutes,
+// TLSRoutes have no match criteria besides SNI, so the backends of all rules are combined.
+func translateTLSRoute(
+	tlsRoute *gatewayv1alpha2.TLSRoute,
+	statPrefix string,
+	serviceLister corev1listers.ServiceLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+) (*tcpproxyv3.TcpProxy, []gatewayv1.BackendRef, metav1.Condition) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range tlsRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	return buildTCPProxy("TLSRoute", tlsRoute.Namespace, tlsRoute.Generation, statPrefix, backendRefs, serviceLister, referenceGrantLister)
+}
+
+// isTLSPassthrough reports whether a listener passes TLS through to the backends
+// instead of terminating it.
+func isTLSPassthrough(listener gatewayv1.Listener) bool {
+	return listener.Protocol == gatewayv1.TLSProtocolType &&
+		listener.TLS != nil &&
+		listener.TLS.Mode != nil &&
+		*listener.TLS.Mode == gatewayv1.TLSModePassthrough
+}
+
+// buildTLSPassthroughFilterChain builds a filter chain that forwards the still-encrypted
+// connection to the route's backends when the client's SNI matches one of the server names.
+// Server names already claimed by another filter chain on the same listener are skipped,
+// since Envoy rejects listeners with duplicate filter chain matches. It returns nil if no
+// server names are left for the route.
+func buildTLSPassthroughFilterChain(tcpProxy *tcpproxyv3.TcpProxy, serverNames []string, claimedServerNames sets.Set[string]) (*listenerv3.FilterChain, error) {
+	var unclaimed []string
+	catchAll := false
+	for _, serverName := range serverNames {
+		if claimedServerNames.Has(serverName) {
+			continue
+		}
+		claimedServerNames.Insert(serverName)
+		if serverName == "*" {
+			// A universal hostname is expressed as a filter chain without server names.
+			catchAll = true
+			continue
+		}
+		unclaimed = append(unclaimed, serverName)
+	}
+	if len(unclaimed) == 0 && !catchAll {
+		return nil, nil
+	}
+	sort.Strings(unclaimed)
+
+	filterChain, err := buildTCPProxyFilterChain(tcpProxy)
+	if err != nil {
+		return nil, err
+	}
+	if !catchAll {
+		filterChain.FilterChainMatch = &listenerv3.FilterChainMatch{
+			ServerNames: unclaimed,
+		}
+	}
+	return filterChain, nil
+}
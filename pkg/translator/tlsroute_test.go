@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func tlsPassthroughListener(name string, port gatewayv1.PortNumber) gatewayv1.Listener {
+	return gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(name),
+		Port:     port,
+		Protocol: gatewayv1.TLSProtocolType,
+		TLS:      &gatewayv1.ListenerTLSConfig{Mode: ptr(gatewayv1.TLSModePassthrough)},
+	}
+}
+
+func TestTranslateTLSRoutePassthrough(t *testing.T) {
+	gw := testGateway("gw", tlsPassthroughListener("tls", 443))
+	routeA := testTLSRoute("a", "gw", []gatewayv1.Hostname{"a.example.com"}, testBackendRef("backend-a", 8443))
+	routeB := testTLSRoute("b", "gw", []gatewayv1.Hostname{"b.example.com"}, testBackendRef("backend-b", 8443))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend-a", 8443), testService("backend-b", 8443), routeA, routeB)
+	resources := translateGateway(t, tl, gw)
+
+	listener := findListener(t, resources, "listener-443")
+	hasTLSInspector := slices.ContainsFunc(listener.ListenerFilters, func(filter *listenerv3.ListenerFilter) bool {
+		return filter.Name == wellknown.TlsInspector
+	})
+	if !hasTLSInspector {
+		t.Errorf("listener filters = %v, want %s", listener.ListenerFilters, wellknown.TlsInspector)
+	}
+
+	// Each TLSRoute gets a filter chain of its own, which forwards the connection without
+	// terminating TLS.
+	clustersByServerName := make(map[string]string)
+	for _, filterChain := range listener.FilterChains {
+		if filterChain.TransportSocket != nil {
+			t.Errorf("filter chain %v terminates TLS", filterChain.GetFilterChainMatch().GetServerNames())
+		}
+		cluster := filterChainTCPProxy(t, filterChain).GetCluster()
+		for _, serverName := range filterChain.GetFilterChainMatch().GetServerNames() {
+			clustersByServerName[serverName] = cluster
+		}
+	}
+	want := map[string]string{
+		"a.example.com": clusterName("backend-a", 8443),
+		"b.example.com": clusterName("backend-b", 8443),
+	}
+	if len(listener.FilterChains) != 2 || len(clustersByServerName) != len(want) {
+		t.Fatalf("filter chains by server name = %v, want %v", clustersByServerName, want)
+	}
+	for serverName, cluster := range want {
+		if clustersByServerName[serverName] != cluster {
+			t.Errorf("cluster of server name %s = %q, want %q", serverName, clustersByServerName[serverName], cluster)
+		}
+	}
+}
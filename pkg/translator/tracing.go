@@ -0,0 +1,70 @@
+package translator
+
+import (
+	"fmt"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tracev3 "github.com/envoyproxy/go-control-plane/envoy/config/trace/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// tracingClusterName is the name of the cluster of the OpenTelemetry collector.
+	tracingClusterName = "tracing-collector"
+	// openTelemetryTracerName is the name of Envoy's OpenTelemetry tracer.
+	openTelemetryTracerName = "envoy.tracers.opentelemetry"
+)
+
+// DefaultTracingSampling is the percentage of requests that are traced by default.
+const DefaultTracingSampling = 100.0
+
+// validateTracingSampling reports whether the sampling percentage is between 0 and 100.
+func validateTracingSampling(sampling float64) error {
+	if sampling < 0 || sampling > 100 {
+		return fmt.Errorf("sampling percentage %v must be between 0 and 100", sampling)
+	}
+	return nil
+}
+
+// buildTracingCluster builds the cluster of the OpenTelemetry collector, whose address is
+// resolved through DNS. Spans are exported over gRPC, so the cluster speaks HTTP/2.
+func buildTracingCluster(address string) (*clusterv3.Cluster, error) {
+	cluster, err := buildStrictDNSCluster(tracingClusterName, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := enableHTTP2(cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// buildTracing builds the tracing config of an HTTP connection manager of the Gateway, which
+// exports the spans of the sampled requests to the OpenTelemetry collector. The spans are
+// attributed to a service named after the Gateway.
+func buildTracing(gateway *gatewayv1.Gateway, options Options) (*hcm.HttpConnectionManager_Tracing, error) {
+	tracerAny, err := anypb.New(&tracev3.OpenTelemetryConfig{
+		GrpcService: &corev3.GrpcService{
+			TargetSpecifier: &corev3.GrpcService_EnvoyGrpc_{
+				EnvoyGrpc: &corev3.GrpcService_EnvoyGrpc{ClusterName: tracingClusterName},
+			},
+		},
+		ServiceName: fmt.Sprintf("%s/%s", gateway.Namespace, gateway.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hcm.HttpConnectionManager_Tracing{
+		RandomSampling: &typev3.Percent{Value: options.TracingSampling},
+		Provider: &tracev3.Tracing_Http{
+			Name: openTelemetryTracerName,
+			ConfigType: &tracev3.Tracing_Http_TypedConfig{
+				TypedConfig: tracerAny,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,79 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tracev3 "github.com/envoyproxy/go-control-plane/envoy/config/trace/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+)
+
+func TestTranslateTracing(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	options := Options{TracingCollector: "otel-collector.observability:4317", TracingSampling: 25}
+	tl := newTestTranslator(t, options, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	tracing := listenerHCM(t, findListener(t, resources, "listener-80")).Tracing
+	if tracing == nil {
+		t.Fatal("HTTP connection manager has no tracing config")
+	}
+	if got := tracing.GetRandomSampling().GetValue(); got != 25 {
+		t.Errorf("random sampling = %v, want 25", got)
+	}
+	if tracing.Provider.GetName() != openTelemetryTracerName {
+		t.Errorf("tracer = %s, want %s", tracing.Provider.GetName(), openTelemetryTracerName)
+	}
+	tracer := &tracev3.OpenTelemetryConfig{}
+	if err := tracing.Provider.GetTypedConfig().UnmarshalTo(tracer); err != nil {
+		t.Fatal(err)
+	}
+	if got := tracer.GetGrpcService().GetEnvoyGrpc().GetClusterName(); got != tracingClusterName {
+		t.Errorf("tracer cluster = %s, want %s", got, tracingClusterName)
+	}
+	if tracer.ServiceName != "default/gw" {
+		t.Errorf("tracer service name = %s, want default/gw", tracer.ServiceName)
+	}
+
+	cluster := findCluster(t, resources, tracingClusterName)
+	if cluster.GetType() != clusterv3.Cluster_STRICT_DNS {
+		t.Errorf("cluster %s discovery type = %v, want STRICT_DNS", tracingClusterName, cluster.GetType())
+	}
+	if got := lbEndpointAddresses(cluster.LoadAssignment.Endpoints[0]); !slices.Equal(got, []string{"otel-collector.observability:4317"}) {
+		t.Errorf("cluster %s endpoints = %v, want otel-collector.observability:4317", tracingClusterName, got)
+	}
+	if _, ok := cluster.TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"]; !ok {
+		t.Errorf("cluster %s does not enable HTTP/2", tracingClusterName)
+	}
+}
+
+func TestTranslateWithoutTracing(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), testHTTPRoute("web", "gw", testBackendRef("backend", 80)))
+	resources := translateGateway(t, tl, gw)
+
+	if tracing := listenerHCM(t, findListener(t, resources, "listener-80")).Tracing; tracing != nil {
+		t.Errorf("HTTP connection manager tracing = %v, want none", tracing)
+	}
+	if cluster := findResource(resources, resourcev3.ClusterType, tracingClusterName); cluster != nil {
+		t.Errorf("cluster %s is emitted without a tracing collector", tracingClusterName)
+	}
+}
+
+func TestValidateTracingOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		options Options
+	}{
+		{name: "collector without port", options: Options{TracingCollector: "otel-collector", TracingSampling: 100}},
+		{name: "negative sampling", options: Options{TracingCollector: "otel-collector:4317", TracingSampling: -1}},
+		{name: "sampling above 100", options: Options{TracingCollector: "otel-collector:4317", TracingSampling: 101}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.options.Validate(); err == nil {
+				t.Errorf("Validate() = nil, want an error")
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package translator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"slices"
+	"sort"
 	"time"
 
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
@@ -12,10 +16,11 @@ import (
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	httpv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
-	"github.com/sanity-io/litter"
-	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/anypb"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -25,11 +30,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/client-go/util/retry"
-	"k8s.io/klog/v2"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+	gatewaylistersv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1alpha2"
 	gatewaylistersv1beta1 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
 )
 
@@ -43,16 +50,169 @@ func (e *ControllerError) Error() string {
 	return e.Message
 }
 
+// DefaultControllerName is the controller name of GatewayClasses whose Gateways are translated.
+const DefaultControllerName = "gateway.xds/gateway-xds-generator"
+
+// Options configures the optional behavior of the Translator.
+type Options struct {
+	// ControllerName is the controller name of GatewayClasses whose Gateways are translated.
+	// It defaults to DefaultControllerName.
+	ControllerName string
+	// HTTPSRedirect makes HTTP listeners redirect all requests to HTTPS if an HTTPS
+	// listener of the same Gateway has the same hostname.
+	HTTPSRedirect bool
+	// AccessLog enables access logging to stdout on every HTTP connection manager.
+	AccessLog bool
+	// AccessLogFormat is the format of the access log entries, AccessLogFormatJSON or
+	// AccessLogFormatText. It defaults to JSON.
+	AccessLogFormat string
+	// AccessLogFormatString overrides the default format of the access log entries.
+	AccessLogFormatString string
+	// CircuitBreakers are the default circuit breaking thresholds of all clusters. They
+	// can be overridden per Service with annotations.
+	CircuitBreakers CircuitBreakers
+	// DefaultLBPolicy is the load balancing policy of all clusters, e.g. LEAST_REQUEST. It
+	// can be overridden per Service with an annotation and defaults to ROUND_ROBIN.
+	DefaultLBPolicy string
+	// RateLimitService is the host:port address of the external gRPC rate limit service,
+	// which enforces the rate limits of HTTPRoutes that define rate limit descriptors.
+	RateLimitService string
+	// RateLimitDomain is the domain of the rate limits in the rate limit service.
+	RateLimitDomain string
+	// ExtAuthzService is the host:port address of the external HTTP authorization service,
+	// which authorizes all requests unless an HTTPRoute opts out.
+	ExtAuthzService string
+	// ExtAuthzPathPrefix is prepended to the path of the requests to the authorization service.
+	ExtAuthzPathPrefix string
+	// ExtAuthzFailureModeAllow allows requests if the authorization service is unavailable.
+	ExtAuthzFailureModeAllow bool
+	// Compression is the algorithm that the responses of the allowed content types are
+	// compressed with, CompressionGzip or CompressionBrotli. Responses are not compressed if
+	// it is empty, and HTTPRoutes can opt out with an annotation.
+	Compression string
+	// BufferMaxRequestBytes buffers all requests up to the given number of bytes before they
+	// are forwarded, larger requests are answered with a 413. Requests are streamed if it is 0.
+	// HTTPRoutes can set their own limit with an annotation.
+	BufferMaxRequestBytes uint32
+	// WebSockets lets the requests of all HTTPRoutes upgrade their connections to WebSockets.
+	// Otherwise, HTTPRoutes can enable them with an annotation.
+	WebSockets bool
+	// NotFoundStatus is the status of the responses to requests that match no route of a
+	// virtual host. It defaults to DefaultNotFoundStatus.
+	NotFoundStatus uint32
+	// NotFoundBody is the body of the responses to requests that match no route of a
+	// virtual host. The responses have no body if it is empty.
+	NotFoundBody string
+	// TLSMinVersion is the minimum TLS version of listeners that terminate TLS, e.g. "1.3".
+	// It defaults to DefaultTLSMinVersion and can be overridden by the TLS options of a listener.
+	TLSMinVersion string
+	// TLSMaxVersion is the maximum TLS version of listeners that terminate TLS. Envoy's
+	// default applies if it is empty. It can be overridden by the TLS options of a listener.
+	TLSMaxVersion string
+	// TracingCollector is the host:port address of the OpenTelemetry collector that the spans
+	// of traced requests are exported to over gRPC. Requests are only traced if it is set.
+	TracingCollector string
+	// TracingSampling is the percentage of requests that are traced.
+	TracingSampling float64
+	// ConnectTimeout is the timeout of new connections to the backends. It defaults to
+	// DefaultConnectTimeout and can be overridden per Service with an annotation.
+	ConnectTimeout time.Duration
+	// TCPKeepalive are the TCP keepalive settings of the connections to the backends. They
+	// can be overridden per Service with annotations.
+	TCPKeepalive TCPKeepalive
+	// RouteConcurrency is the number of HTTPRoutes of a listener that are translated in
+	// parallel. They are translated one at a time if it is 0.
+	RouteConcurrency int
+	// ListenerTCPKeepalive are the TCP keepalive settings of the downstream connections of the
+	// TCP listeners, which are set as socket options of the listeners. Keepalive is only
+	// enabled if a setting is non-zero.
+	ListenerTCPKeepalive TCPKeepalive
+	// DefaultBackend is the namespace/name:port of the Service that the requests matching no
+	// route are forwarded to. They are answered with NotFoundStatus if it is empty.
+	DefaultBackend string
+	// ClusterDomain is the DNS domain of the Kubernetes cluster, which the DNS names of
+	// Services resolved through DNS end in. It defaults to DefaultClusterDomain.
+	ClusterDomain string
+	// Logger logs the messages of the translation, e.g. skipped routes and backends.
+	// It defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Validate reports whether the options are valid.
+func (o Options) Validate() error {
+	if _, err := parseLBPolicy(o.DefaultLBPolicy); err != nil {
+		return fmt.Errorf("invalid default load balancing policy: %w", err)
+	}
+	if o.RateLimitService != "" {
+		if _, _, err := parseServiceAddress(o.RateLimitService); err != nil {
+			return fmt.Errorf("invalid rate limit service: %w", err)
+		}
+		if o.RateLimitDomain == "" {
+			return errors.New("the rate limit service requires a rate limit domain")
+		}
+	}
+	if o.ExtAuthzService != "" {
+		if _, _, err := parseServiceAddress(o.ExtAuthzService); err != nil {
+			return fmt.Errorf("invalid external authorization service: %w", err)
+		}
+	}
+	if err := validateCompression(o.Compression); err != nil {
+		return fmt.Errorf("invalid compression: %w", err)
+	}
+	if o.DefaultBackend != "" {
+		if _, err := parseDefaultBackend(o.DefaultBackend); err != nil {
+			return fmt.Errorf("invalid default backend: %w", err)
+		}
+	}
+	if err := validateNotFoundStatus(o.NotFoundStatus); err != nil {
+		return fmt.Errorf("invalid not found status: %w", err)
+	}
+	if o.ConnectTimeout < 0 {
+		return fmt.Errorf("invalid connect timeout %s: must be positive", o.ConnectTimeout)
+	}
+	if err := o.TCPKeepalive.validate(); err != nil {
+		return err
+	}
+	if err := o.ListenerTCPKeepalive.validate(); err != nil {
+		return fmt.Errorf("invalid listener keepalive: %w", err)
+	}
+	if _, err := buildTLSParameters(o.tlsMinVersion(), o.TLSMaxVersion); err != nil {
+		return err
+	}
+	if o.TracingCollector != "" {
+		if _, _, err := parseServiceAddress(o.TracingCollector); err != nil {
+			return fmt.Errorf("invalid tracing collector: %w", err)
+		}
+		if err := validateTracingSampling(o.TracingSampling); err != nil {
+			return fmt.Errorf("invalid tracing sampling: %w", err)
+		}
+	}
+	if err := validateRouteConcurrency(o.RouteConcurrency); err != nil {
+		return fmt.Errorf("invalid route concurrency: %w", err)
+	}
+	_, err := buildAccessLogs(o)
+	return err
+}
+
 // Translator holds the xDS cache and version for generating snapshots.
 type Translator struct {
-	client               kubernetes.Interface
-	gwClient             gatewayclient.Interface
-	namespaceLister      corev1listers.NamespaceLister
-	serviceLister        corev1listers.ServiceLister
-	secretLister         corev1listers.SecretLister
-	gatewayLister        gatewaylisters.GatewayLister
-	httprouteLister      gatewaylisters.HTTPRouteLister
-	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister
+	client                 kubernetes.Interface
+	gwClient               gatewayclient.Interface
+	namespaceLister        corev1listers.NamespaceLister
+	serviceLister          corev1listers.ServiceLister
+	endpointSliceLister    discoverylisters.EndpointSliceLister
+	secretLister           corev1listers.SecretLister
+	configMapLister        corev1listers.ConfigMapLister
+	gatewayLister          gatewaylisters.GatewayLister
+	gatewayClassLister     gatewaylisters.GatewayClassLister
+	httprouteLister        gatewaylisters.HTTPRouteLister
+	grpcrouteLister        gatewaylisters.GRPCRouteLister
+	tcprouteLister         gatewaylistersv1alpha2.TCPRouteLister
+	tlsrouteLister         gatewaylistersv1alpha2.TLSRouteLister
+	udprouteLister         gatewaylistersv1alpha2.UDPRouteLister
+	referenceGrantLister   gatewaylistersv1beta1.ReferenceGrantLister
+	backendTLSPolicyLister gatewaylisters.BackendTLSPolicyLister
+	options                Options
 }
 
 func New(
@@ -60,47 +220,250 @@ func New(
 	gwClient gatewayclient.Interface,
 	namespaceLister corev1listers.NamespaceLister,
 	serviceLister corev1listers.ServiceLister,
+	endpointSliceLister discoverylisters.EndpointSliceLister,
 	secretLister corev1listers.SecretLister,
+	configMapLister corev1listers.ConfigMapLister,
 	gatewayLister gatewaylisters.GatewayLister,
+	gatewayClassLister gatewaylisters.GatewayClassLister,
 	httpRouteLister gatewaylisters.HTTPRouteLister,
-	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister) *Translator {
+	grpcRouteLister gatewaylisters.GRPCRouteLister,
+	tcpRouteLister gatewaylistersv1alpha2.TCPRouteLister,
+	tlsRouteLister gatewaylistersv1alpha2.TLSRouteLister,
+	udpRouteLister gatewaylistersv1alpha2.UDPRouteLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+	backendTLSPolicyLister gatewaylisters.BackendTLSPolicyLister,
+	options Options) *Translator {
 	return &Translator{
 		client,
 		gwClient,
 		namespaceLister,
 		serviceLister,
+		endpointSliceLister,
 		secretLister,
+		configMapLister,
 		gatewayLister,
+		gatewayClassLister,
 		httpRouteLister,
+		grpcRouteLister,
+		tcpRouteLister,
+		tlsRouteLister,
+		udpRouteLister,
 		referenceGrantLister,
+		backendTLSPolicyLister,
+		options,
 	}
 }
 
-// TranslateGatewayToXDS translates Gateway and HTTPRoute resources into Envoy xDS resources.
+// TranslateGatewayToXDS translates a Gateway and the routes attached to it into Envoy xDS resources.
+// Resources that cannot be built are left out and their errors joined into the returned error,
+// along with the resources that could be built.
 func (t *Translator) TranslateGatewayToXDS(ctx context.Context, gw *gatewayv1.Gateway) (map[resourcev3.Type][]envoyproxytypes.Resource, error) {
 	// Get the desired state
-	envoyResources, listenerStatus, routeStatus := t.buildEnvoyResourcesForGateway(gw)
+	envoyResources, listenerStatus, routeStatuses, err := t.buildEnvoyResourcesForGateway(gw)
+
+	t.logger().Debug("translated Gateway", "namespace", gw.Namespace, "name", gw.Name,
+		"listenerStatuses", listenerStatus, "routeStatuses", routeStatuses)
+
+	if t.gwClient != nil {
+		if err := t.updateGatewayStatus(ctx, gw, listenerStatus, err); err != nil {
+			t.logger().Error("failed to update Gateway status", "namespace", gw.Namespace, "name", gw.Name, "err", err)
+		}
+		if err := UpdateRouteStatuses(ctx, t.gwClient, routeStatuses); err != nil {
+			t.logger().Error("failed to update route statuses", "namespace", gw.Namespace, "name", gw.Name, "err", err)
+		}
+	}
+	return envoyResources, err
+}
+
+// controllerName returns the controller name of the GatewayClasses managed by the Translator.
+func (t *Translator) controllerName() string {
+	if t.options.ControllerName == "" {
+		return DefaultControllerName
+	}
+	return t.options.ControllerName
+}
+
+// logger returns the logger of the Translator.
+func (t *Translator) logger() *slog.Logger {
+	if t.options.Logger == nil {
+		return slog.Default()
+	}
+	return t.options.Logger
+}
+
+// logRouteCondition logs the ResolvedRefs condition of a route if the route has
+// references that could not be resolved.
+func (t *Translator) logRouteCondition(kind string, key types.NamespacedName, condition metav1.Condition) {
+	if condition.Status != metav1.ConditionFalse {
+		return
+	}
+	t.logger().Warn("route has unresolved references", "kind", kind, "namespace", key.Namespace,
+		"name", key.Name, "reason", condition.Reason, "message", condition.Message)
+}
+
+// managesGateway returns the GatewayClass of the Gateway if it is managed by the Translator.
+// If not, it returns nil and the reason.
+func (t *Translator) managesGateway(gw *gatewayv1.Gateway) (*gatewayv1.GatewayClass, string) {
+	gatewayClass, err := t.gatewayClassLister.Get(string(gw.Spec.GatewayClassName))
+	if err != nil {
+		return nil, fmt.Sprintf("could not get GatewayClass %s: %v", gw.Spec.GatewayClassName, err)
+	}
+	if string(gatewayClass.Spec.ControllerName) != t.controllerName() {
+		return nil, fmt.Sprintf("GatewayClass %s is managed by controller %s, not %s", gatewayClass.Name, gatewayClass.Spec.ControllerName, t.controllerName())
+	}
+	return gatewayClass, ""
+}
+
+// TranslateGatewaysToXDS translates several Gateways into a single set of Envoy xDS resources.
+// Gateways whose GatewayClass is managed by another controller are skipped. The resources of
+// the Gateways are merged by MergeGatewayResources. Errors do not stop the translation: they
+// are joined into the returned error, along with the resources that could be built.
+func (t *Translator) TranslateGatewaysToXDS(ctx context.Context, gws []*gatewayv1.Gateway) (map[resourcev3.Type][]envoyproxytypes.Resource, error) {
+	resourcesByGateway := make([]map[resourcev3.Type][]envoyproxytypes.Resource, len(gws))
+	var errs []error
+	for i, gw := range gws {
+		resources, err := t.TranslateManagedGatewayToXDS(ctx, gw)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		resourcesByGateway[i] = resources
+	}
+	mergedResources, err := MergeGatewayResources(gws, resourcesByGateway)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return mergedResources, errors.Join(errs...)
+}
+
+// TranslateManagedGatewayToXDS translates the Gateway like TranslateGatewayToXDS if its
+// GatewayClass is managed by the Translator, with the defaults of the parametersRef of the
+// class. It returns no resources for Gateways of other classes.
+func (t *Translator) TranslateManagedGatewayToXDS(ctx context.Context, gw *gatewayv1.Gateway) (map[resourcev3.Type][]envoyproxytypes.Resource, error) {
+	gatewayClass, reason := t.managesGateway(gw)
+	if gatewayClass == nil {
+		t.logger().Info("skipping Gateway", "namespace", gw.Namespace, "name", gw.Name, "reason", reason)
+		return nil, nil
+	}
+	classTranslator, err := t.forGatewayClass(gatewayClass)
+	if err != nil {
+		return nil, fmt.Errorf("Gateway %s/%s: %w", gw.Namespace, gw.Name, err)
+	}
+	return classTranslator.TranslateGatewayToXDS(ctx, gw)
+}
 
-	litter.Dump(listenerStatus)
-	litter.Dump(routeStatus)
-	return envoyResources, nil
+// MergeGatewayResources merges the resources of the Gateways, resourcesByGateway[i] being
+// the resources of gws[i]. Clusters shared by the Gateways are only included once, so a
+// cluster shared by Gateways of classes with different defaults is the first one's. Listeners
+// of different Gateways that bind the same port are reported as a conflict, in which case the
+// first Gateway's listener is kept.
+func MergeGatewayResources(gws []*gatewayv1.Gateway, resourcesByGateway []map[resourcev3.Type][]envoyproxytypes.Resource) (map[resourcev3.Type][]envoyproxytypes.Resource, error) {
+	mergedResources := make(map[resourcev3.Type][]envoyproxytypes.Resource)
+	// owners records which Gateway produced each resource, keyed by type and name.
+	owners := make(map[resourcev3.Type]map[string]*gatewayv1.Gateway)
+	var errs []error
+
+	for i, gw := range gws {
+		for typeURL, typeResources := range resourcesByGateway[i] {
+			if owners[typeURL] == nil {
+				owners[typeURL] = make(map[string]*gatewayv1.Gateway)
+			}
+			for _, res := range typeResources {
+				name := cachev3.GetResourceName(res)
+				owner, ok := owners[typeURL][name]
+				if !ok {
+					owners[typeURL][name] = gw
+					mergedResources[typeURL] = append(mergedResources[typeURL], res)
+					continue
+				}
+				// Listeners and their route configs are named after the port, so a name
+				// collision between Gateways means they bind the same port.
+				if typeURL == resourcev3.ListenerType && owner != gw {
+					errs = append(errs, fmt.Errorf("listener %s of Gateway %s/%s conflicts with Gateway %s/%s", name, gw.Namespace, gw.Name, owner.Namespace, owner.Name))
+				}
+			}
+		}
+	}
+	return mergedResources, errors.Join(errs...)
 }
 
 var (
 	SupportedKinds = sets.New[gatewayv1.Kind](
 		"HTTPRoute",
+		"GRPCRoute",
+		"TCPRoute",
+		"TLSRoute",
+		"UDPRoute",
 	)
+
+	// supportedKindsByProtocol lists the route kinds that may attach to a listener of a given protocol.
+	supportedKindsByProtocol = map[gatewayv1.ProtocolType][]gatewayv1.Kind{
+		gatewayv1.HTTPProtocolType:  {"HTTPRoute", "GRPCRoute"},
+		gatewayv1.HTTPSProtocolType: {"HTTPRoute", "GRPCRoute"},
+		gatewayv1.TCPProtocolType:   {"TCPRoute"},
+		gatewayv1.TLSProtocolType:   {"TLSRoute"},
+		gatewayv1.UDPProtocolType:   {"UDPRoute"},
+	}
 )
 
+// RouteStatuses holds the RouteParentStatuses computed for each route kind, keyed by route.
+type RouteStatuses struct {
+	HTTPRoutes map[types.NamespacedName][]gatewayv1.RouteParentStatus
+	GRPCRoutes map[types.NamespacedName][]gatewayv1.RouteParentStatus
+	TCPRoutes  map[types.NamespacedName][]gatewayv1.RouteParentStatus
+	TLSRoutes  map[types.NamespacedName][]gatewayv1.RouteParentStatus
+	UDPRoutes  map[types.NamespacedName][]gatewayv1.RouteParentStatus
+}
+
+// LogValue logs the statuses grouped by route kind, with the routes keyed by namespace/name.
+func (s RouteStatuses) LogValue() slog.Value {
+	var kinds []slog.Attr
+	for _, kind := range []struct {
+		name     string
+		statuses map[types.NamespacedName][]gatewayv1.RouteParentStatus
+	}{
+		{"HTTPRoutes", s.HTTPRoutes},
+		{"GRPCRoutes", s.GRPCRoutes},
+		{"TCPRoutes", s.TCPRoutes},
+		{"TLSRoutes", s.TLSRoutes},
+		{"UDPRoutes", s.UDPRoutes},
+	} {
+		if len(kind.statuses) == 0 {
+			continue
+		}
+		routes := make([]slog.Attr, 0, len(kind.statuses))
+		for key, parents := range kind.statuses {
+			routes = append(routes, slog.Any(key.String(), parents))
+		}
+		sort.Slice(routes, func(i, j int) bool { return routes[i].Key < routes[j].Key })
+		kinds = append(kinds, slog.Attr{Key: kind.name, Value: slog.GroupValue(routes...)})
+	}
+	return slog.GroupValue(kinds...)
+}
+
+func newRouteStatuses() RouteStatuses {
+	return RouteStatuses{
+		HTTPRoutes: make(map[types.NamespacedName][]gatewayv1.RouteParentStatus),
+		GRPCRoutes: make(map[types.NamespacedName][]gatewayv1.RouteParentStatus),
+		TCPRoutes:  make(map[types.NamespacedName][]gatewayv1.RouteParentStatus),
+		TLSRoutes:  make(map[types.NamespacedName][]gatewayv1.RouteParentStatus),
+		UDPRoutes:  make(map[types.NamespacedName][]gatewayv1.RouteParentStatus),
+	}
+}
+
 // Main State Calculation Function
 func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 	map[resourcev3.Type][]envoyproxytypes.Resource,
 	[]gatewayv1.ListenerStatus,
-	map[types.NamespacedName][]gatewayv1.RouteParentStatus, // HTTPRoutes
+	RouteStatuses,
+	error,
 ) {
 
-	httpRouteStatuses := make(map[types.NamespacedName][]gatewayv1.RouteParentStatus)
+	routeStatuses := newRouteStatuses()
 	routesByListener := make(map[gatewayv1.SectionName][]*gatewayv1.HTTPRoute)
+	grpcRoutesByListener := make(map[gatewayv1.SectionName][]*gatewayv1.GRPCRoute)
+	tcpRoutesByListener := make(map[gatewayv1.SectionName][]*gatewayv1alpha2.TCPRoute)
+	tlsRoutesByListener := make(map[gatewayv1.SectionName][]*gatewayv1alpha2.TLSRoute)
+	udpRoutesByListener := make(map[gatewayv1.SectionName][]*gatewayv1alpha2.UDPRoute)
 
 	// Validate all HTTPRoutes against this Gateway
 	allHTTPRoutesForGateway := t.getHTTPRoutesForGateway(gateway)
@@ -110,7 +473,7 @@ func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 
 		// Store the definitive status for the route.
 		if len(parentStatuses) > 0 {
-			httpRouteStatuses[key] = parentStatuses
+			routeStatuses.HTTPRoutes[key] = parentStatuses
 		}
 		// If the route was accepted, associate it with the listeners that accepted it.
 		if len(acceptingListeners) > 0 {
@@ -126,10 +489,130 @@ func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 		}
 	}
 
+	// Validate all GRPCRoutes against this Gateway
+	for _, grpcRoute := range t.getGRPCRoutesForGateway(gateway) {
+		key := types.NamespacedName{Name: grpcRoute.Name, Namespace: grpcRoute.Namespace}
+		parentStatuses, acceptingListeners := t.validateGRPCRoute(gateway, grpcRoute)
+		if len(parentStatuses) > 0 {
+			routeStatuses.GRPCRoutes[key] = parentStatuses
+		}
+		processedListeners := make(map[gatewayv1.SectionName]bool)
+		for _, listener := range acceptingListeners {
+			if _, ok := processedListeners[listener.Name]; !ok {
+				grpcRoutesByListener[listener.Name] = append(grpcRoutesByListener[listener.Name], grpcRoute)
+				processedListeners[listener.Name] = true
+			}
+		}
+	}
+
+	// Validate all TCPRoutes against this Gateway
+	for _, tcpRoute := range t.getTCPRoutesForGateway(gateway) {
+		key := types.NamespacedName{Name: tcpRoute.Name, Namespace: tcpRoute.Namespace}
+		parentStatuses, acceptingListeners := t.validateTCPRoute(gateway, tcpRoute)
+		if len(parentStatuses) > 0 {
+			routeStatuses.TCPRoutes[key] = parentStatuses
+		}
+		processedListeners := make(map[gatewayv1.SectionName]bool)
+		for _, listener := range acceptingListeners {
+			if _, ok := processedListeners[listener.Name]; !ok {
+				tcpRoutesByListener[listener.Name] = append(tcpRoutesByListener[listener.Name], tcpRoute)
+				processedListeners[listener.Name] = true
+			}
+		}
+	}
+
+	// Validate all TLSRoutes against this Gateway
+	for _, tlsRoute := range t.getTLSRoutesForGateway(gateway) {
+		key := types.NamespacedName{Name: tlsRoute.Name, Namespace: tlsRoute.Namespace}
+		parentStatuses, acceptingListeners := t.validateTLSRoute(gateway, tlsRoute)
+		if len(parentStatuses) > 0 {
+			routeStatuses.TLSRoutes[key] = parentStatuses
+		}
+		processedListeners := make(map[gatewayv1.SectionName]bool)
+		for _, listener := range acceptingListeners {
+			if _, ok := processedListeners[listener.Name]; !ok {
+				tlsRoutesByListener[listener.Name] = append(tlsRoutesByListener[listener.Name], tlsRoute)
+				processedListeners[listener.Name] = true
+			}
+		}
+	}
+
+	// Validate all UDPRoutes against this Gateway
+	for _, udpRoute := range t.getUDPRoutesForGateway(gateway) {
+		key := types.NamespacedName{Name: udpRoute.Name, Namespace: udpRoute.Namespace}
+		parentStatuses, acceptingListeners := t.validateUDPRoute(gateway, udpRoute)
+		if len(parentStatuses) > 0 {
+			routeStatuses.UDPRoutes[key] = parentStatuses
+		}
+		processedListeners := make(map[gatewayv1.SectionName]bool)
+		for _, listener := range acceptingListeners {
+			if _, ok := processedListeners[listener.Name]; !ok {
+				udpRoutesByListener[listener.Name] = append(udpRoutesByListener[listener.Name], udpRoute)
+				processedListeners[listener.Name] = true
+			}
+		}
+	}
+
 	// Build Envoy config using only the pre-validated and accepted routes
 	envoyRoutes := []envoyproxytypes.Resource{}
 	envoyClusters := make(map[string]envoyproxytypes.Resource)
+	envoyEndpoints := make(map[string]envoyproxytypes.Resource)
+	backendClusters := newClusterCache(t)
+	envoySecrets := make(map[string]envoyproxytypes.Resource)
 	allListenerStatuses := make(map[gatewayv1.SectionName]gatewayv1.ListenerStatus)
+	// clusterErrs are the errors of the clusters that could not be built. Their routes are
+	// still translated, so that the other routes are not affected.
+	var clusterErrs []error
+	// matchErrs are the errors of the route matches that were skipped for an invalid
+	// regular expression.
+	var matchErrs []error
+	// proxyProtocolErr reports an invalid PROXY protocol annotation, which leaves it disabled.
+	_, proxyProtocolErr := parseProxyProtocolAnnotation(gateway.Annotations)
+	if proxyProtocolErr != nil {
+		proxyProtocolErr = fmt.Errorf("Gateway %s/%s: %w", gateway.Namespace, gateway.Name, proxyProtocolErr)
+	}
+
+	if t.options.RateLimitService != "" {
+		rateLimitCluster, err := buildRateLimitCluster(t.options.RateLimitService)
+		if err != nil {
+			t.logger().Error("failed to build the rate limit service cluster", "err", err)
+		} else {
+			envoyClusters[rateLimitCluster.Name] = rateLimitCluster
+		}
+	}
+	if t.options.ExtAuthzService != "" {
+		extAuthzCluster, err := buildStrictDNSCluster(extAuthzClusterName, t.options.ExtAuthzService)
+		if err != nil {
+			t.logger().Error("failed to build the external authorization service cluster", "err", err)
+		} else {
+			envoyClusters[extAuthzCluster.Name] = extAuthzCluster
+		}
+	}
+	if t.options.TracingCollector != "" {
+		tracingCluster, err := buildTracingCluster(t.options.TracingCollector)
+		if err != nil {
+			t.logger().Error("failed to build the tracing collector cluster", "err", err)
+		} else {
+			envoyClusters[tracingCluster.Name] = tracingCluster
+		}
+	}
+	// defaultBackendCluster is the cluster that the requests matching no route are forwarded
+	// to. They are answered as not found if there is no default backend, or if its cluster
+	// could not be built.
+	var defaultBackendCluster string
+	if t.options.DefaultBackend != "" {
+		defaultBackend, err := parseDefaultBackend(t.options.DefaultBackend)
+		if err == nil {
+			var clusters []*clusterv3.Cluster
+			clusters, err = ensureClusters(backendClusters, envoyClusters, envoyEndpoints, string(*defaultBackend.Namespace), []gatewayv1.BackendRef{defaultBackend}, clusterVariant{})
+			if len(clusters) == 1 {
+				defaultBackendCluster = clusters[0].Name
+			}
+		}
+		if err != nil {
+			clusterErrs = append(clusterErrs, fmt.Errorf("Gateway %s/%s: default backend: %w", gateway.Namespace, gateway.Name, err))
+		}
+	}
 	// Aggregate Listeners by Port
 	listenersByPort := make(map[gatewayv1.PortNumber][]gatewayv1.Listener)
 	for _, listener := range gateway.Spec.Listeners {
@@ -144,7 +627,10 @@ func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 	for port, listeners := range listenersByPort {
 		// This slice will hold the filter chains.
 		var filterChains []*listenerv3.FilterChain
-		// Prepare to collect ALL virtual hosts for this port into a single list.
+		// UDP listeners have no filter chains, so they are tracked separately.
+		var udpListener *listenerv3.Listener
+		// Prepare to collect the virtual hosts of all HTTP listeners on this port into a
+		// single route config. HTTPS listeners get a route config of their own, see below.
 		virtualHostsForPort := make(map[string]*routev3.VirtualHost)
 		routeName := fmt.Sprintf("route-%d", port)
 
@@ -190,71 +676,251 @@ func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 				})
 			}
 
+			var filterChain *listenerv3.FilterChain
+			// passthroughFilterChains holds the per-SNI filter chains of TLS passthrough listeners.
+			var passthroughFilterChains []*listenerv3.FilterChain
+			// certificateFilterChains holds the per-certificate filter chains of HTTPS listeners.
+			var certificateFilterChains []*listenerv3.FilterChain
+			// listenerRouteConfig is the route config of an HTTPS listener.
+			var listenerRouteConfig *routev3.RouteConfiguration
+			var err error
 			switch listener.Protocol {
 			case gatewayv1.HTTPProtocolType, gatewayv1.HTTPSProtocolType:
-				// Process HTTPRoutes
-				// Get the routes that were pre-validated for this specific listener.
-				for _, httpRoute := range routesByListener[listener.Name] {
-					routes, validBackendRefs, resolvedRefsCondition := translateHTTPRouteToEnvoyRoutes(httpRoute, t.serviceLister, t.referenceGrantLister)
+				// HTTPS listeners sharing a port are told apart by SNI and terminate TLS with
+				// their own certificates, so each one gets its own route config. Otherwise a
+				// request could reach the routes of another listener through its Host header.
+				virtualHosts := virtualHostsForPort
+				listenerRouteName := routeName
+				if listener.Protocol == gatewayv1.HTTPSProtocolType {
+					virtualHosts = make(map[string]*routev3.VirtualHost)
+					listenerRouteName = fmt.Sprintf("route-%d-%s", port, listener.Name)
+				}
 
-					key := types.NamespacedName{Name: httpRoute.Name, Namespace: httpRoute.Namespace}
-					currentParentStatuses := httpRouteStatuses[key]
-					for i := range currentParentStatuses {
-						// Only add the ResolvedRefs condition if the parent was Accepted.
-						if meta.IsStatusConditionTrue(currentParentStatuses[i].Conditions, string(gatewayv1.RouteConditionAccepted)) {
-							meta.SetStatusCondition(&currentParentStatuses[i].Conditions, resolvedRefsCondition)
-						}
+				// With HTTPS redirects enabled, an HTTP listener whose hostname is also served
+				// over HTTPS only redirects, so its routes are not translated.
+				if t.options.HTTPSRedirect && listener.Protocol == gatewayv1.HTTPProtocolType && hasHTTPSListenerForHostname(gateway, listener.Hostname) {
+					vh := buildHTTPSRedirectVirtualHost(gateway, port, listener.Hostname)
+					virtualHosts[vh.Domains[0]] = vh
+					attachedRoutes = int32(len(routesByListener[listener.Name]) + len(grpcRoutesByListener[listener.Name]))
+					filterChain, err = t.translateListenerToFilterChain(gateway, listener, nil, routeName, envoySecrets)
+					break
+				}
+
+				// Process HTTPRoutes
+				// Get the routes that were pre-validated for this specific listener. They are
+				// processed from oldest to newest, so that routes with the same precedence keep
+				// the order required by the Gateway API once the virtual hosts are sorted.
+				sortRoutesByAge(routesByListener[listener.Name])
+				httpRouteTranslations := t.translateHTTPRoutes(routesByListener[listener.Name])
+				for i, httpRoute := range routesByListener[listener.Name] {
+					routes, validBackendRefs := httpRouteTranslations[i].routes, httpRouteTranslations[i].validBackendRefs
+					resolvedRefsCondition, regexErr := httpRouteTranslations[i].resolvedRefsCondition, httpRouteTranslations[i].regexErr
+					if regexErr != nil {
+						matchErrs = append(matchErrs, fmt.Errorf("Gateway %s/%s: %w", gateway.Namespace, gateway.Name, regexErr))
+					}
+					if _, ok := httpRoute.Annotations[rateLimitDescriptorsAnnotation]; ok && t.options.RateLimitService == "" {
+						t.logger().Warn("HTTPRoute defines rate limit descriptors, but no rate limit service is configured",
+							"kind", "HTTPRoute", "namespace", httpRoute.Namespace, "name", httpRoute.Name)
 					}
-					httpRouteStatuses[key] = currentParentStatuses
 
 					// Create the necessary Envoy Cluster resources from the valid backends.
-					for _, backendRef := range validBackendRefs {
-						cluster, err := t.translateBackendRefToCluster(httpRoute.Namespace, backendRef)
-						if err == nil && cluster != nil {
-							if _, exists := envoyClusters[cluster.Name]; !exists {
-								envoyClusters[cluster.Name] = cluster
-							}
-						}
+					_, err := ensureClusters(backendClusters, envoyClusters, envoyEndpoints, httpRoute.Namespace, validBackendRefs, httpRouteClusterVariant(httpRoute))
+					if err != nil {
+						clusterErrs = append(clusterErrs, fmt.Errorf("Gateway %s/%s: HTTPRoute %s/%s: %w", gateway.Namespace, gateway.Name, httpRoute.Namespace, httpRoute.Name, err))
+					}
+					if dropMissingClusters(routes, envoyClusters) && resolvedRefsCondition.Status != metav1.ConditionFalse {
+						resolvedRefsCondition = missingClustersCondition(err, httpRoute.Generation)
 					}
 
+					key := types.NamespacedName{Name: httpRoute.Name, Namespace: httpRoute.Namespace}
+					setRouteResolvedRefs(routeStatuses.HTTPRoutes, key, resolvedRefsCondition)
+					t.logRouteCondition("HTTPRoute", key, resolvedRefsCondition)
+
 					// Aggregate Envoy routes into VirtualHosts.
 					if routes != nil {
-						attachedRoutes++
+						if countsAsAttached(resolvedRefsCondition) {
+							attachedRoutes++
+						}
 						// Get the domain for this listener's VirtualHost.
 						vhostDomains := getIntersectingHostnames(listener, httpRoute.Spec.Hostnames)
 						for _, domain := range vhostDomains {
-							vh, ok := virtualHostsForPort[domain]
+							vh, ok := virtualHosts[domain]
 							if !ok {
 								vh = &routev3.VirtualHost{
 									Name:    fmt.Sprintf("%s-vh-%d-%s", gateway.Name, port, domain),
 									Domains: []string{domain},
 								}
-								virtualHostsForPort[domain] = vh
+								virtualHosts[domain] = vh
 							}
 							vh.Routes = append(vh.Routes, routes...)
-							klog.V(4).Infof("created VirtualHost %s for listener %s with domain %s", vh.Name, listener.
-								Name, domain)
-							if klog.V(4).Enabled() {
-								for _, route := range routes {
-									klog.Infof("adding route %s to VirtualHost %s", route.Name, vh.Name)
+							for _, route := range routes {
+								t.logger().Debug("added route to VirtualHost", "route", route.Name, "virtualHost", vh.Name,
+									"listener", listener.Name, "domain", domain)
+							}
+						}
+					}
+				}
+
+				// Process GRPCRoutes
+				sortRoutesByAge(grpcRoutesByListener[listener.Name])
+				for _, grpcRoute := range grpcRoutesByListener[listener.Name] {
+					routes, validBackendRefs, resolvedRefsCondition, regexErr := translateGRPCRoute(grpcRoute, t.serviceLister, t.referenceGrantLister)
+					if regexErr != nil {
+						matchErrs = append(matchErrs, fmt.Errorf("Gateway %s/%s: %w", gateway.Namespace, gateway.Name, regexErr))
+					}
+
+					// gRPC backends must be reached over HTTP/2, so they get clusters of their
+					// own with HTTP/2 protocol options.
+					_, err := ensureClusters(backendClusters, envoyClusters, envoyEndpoints, grpcRoute.Namespace, validBackendRefs, clusterVariant{http2: true})
+					if err != nil {
+						clusterErrs = append(clusterErrs, fmt.Errorf("Gateway %s/%s: GRPCRoute %s/%s: %w", gateway.Namespace, gateway.Name, grpcRoute.Namespace, grpcRoute.Name, err))
+					}
+					if dropMissingClusters(routes, envoyClusters) && resolvedRefsCondition.Status != metav1.ConditionFalse {
+						resolvedRefsCondition = missingClustersCondition(err, grpcRoute.Generation)
+					}
+
+					key := types.NamespacedName{Name: grpcRoute.Name, Namespace: grpcRoute.Namespace}
+					setRouteResolvedRefs(routeStatuses.GRPCRoutes, key, resolvedRefsCondition)
+					t.logRouteCondition("GRPCRoute", key, resolvedRefsCondition)
+
+					if routes != nil {
+						if countsAsAttached(resolvedRefsCondition) {
+							attachedRoutes++
+						}
+						vhostDomains := getIntersectingHostnames(listener, grpcRoute.Spec.Hostnames)
+						for _, domain := range vhostDomains {
+							vh, ok := virtualHosts[domain]
+							if !ok {
+								vh = &routev3.VirtualHost{
+									Name:    fmt.Sprintf("%s-vh-%d-%s", gateway.Name, port, domain),
+									Domains: []string{domain},
 								}
+								virtualHosts[domain] = vh
 							}
+							vh.Routes = append(vh.Routes, routes...)
 						}
 					}
 				}
 
-				// TODO: Process GRPCRoutes
+				if listener.Protocol == gatewayv1.HTTPSProtocolType {
+					listenerRouteConfig = t.buildRouteConfiguration(listenerRouteName, virtualHosts, defaultBackendCluster)
+					filterChain, err = t.translateListenerToFilterChain(gateway, listener, listenerRouteConfig.VirtualHosts, listenerRouteName, envoySecrets)
+					if err == nil {
+						certificateFilterChains, err = t.buildCertificateFilterChains(gateway, listener, filterChain, listenerHostnames(listeners), envoySecrets)
+					}
+				} else {
+					// The filter chains of HTTP listeners are replaced by a single shared one once
+					// all listeners on the port have been processed.
+					filterChain, err = t.translateListenerToFilterChain(gateway, listener, nil, routeName, envoySecrets)
+				}
+
+			case gatewayv1.TCPProtocolType:
+				// Envoy's tcp_proxy forwards every connection to a single destination, so
+				// only the oldest TCPRoute attached to the listener can be honored.
+				tcpRoutes := tcpRoutesByListener[listener.Name]
+				if len(tcpRoutes) == 0 {
+					break
+				}
+				sort.SliceStable(tcpRoutes, func(i, j int) bool {
+					return tcpRoutes[i].CreationTimestamp.Before(&tcpRoutes[j].CreationTimestamp)
+				})
+				if len(tcpRoutes) > 1 {
+					t.logger().Warn("listener has several TCPRoutes attached, only the oldest one is used", "listener", listener.Name,
+						"routes", len(tcpRoutes), "kind", "TCPRoute", "namespace", tcpRoutes[0].Namespace, "name", tcpRoutes[0].Name)
+				}
+				tcpRoute := tcpRoutes[0]
+				tcpProxy, validBackendRefs, resolvedRefsCondition := translateTCPRoute(tcpRoute, string(listener.Name), t.serviceLister, t.referenceGrantLister)
+
+				key := types.NamespacedName{Name: tcpRoute.Name, Namespace: tcpRoute.Namespace}
+				setRouteResolvedRefs(routeStatuses.TCPRoutes, key, resolvedRefsCondition)
+				t.logRouteCondition("TCPRoute", key, resolvedRefsCondition)
+				if _, clusterErr := ensureClusters(backendClusters, envoyClusters, envoyEndpoints, tcpRoute.Namespace, validBackendRefs, clusterVariant{}); clusterErr != nil {
+					clusterErrs = append(clusterErrs, fmt.Errorf("Gateway %s/%s: TCPRoute %s/%s: %w", gateway.Namespace, gateway.Name, tcpRoute.Namespace, tcpRoute.Name, clusterErr))
+				}
 
-			default:
-				klog.Warningf("Unsupported listener protocol for route processing: %s", listener.Protocol)
-			}
+				if tcpProxy != nil {
+					if countsAsAttached(resolvedRefsCondition) {
+						attachedRoutes++
+					}
+					filterChain, err = buildTCPProxyFilterChain(tcpProxy)
+				}
+
+			case gatewayv1.TLSProtocolType:
+				if !isTLSPassthrough(listener) {
+					filterChain, err = t.translateListenerToFilterChain(gateway, listener, nil, routeName, envoySecrets)
+					break
+				}
+				// Each TLSRoute gets its own filter chain, selected by the client's SNI.
+				tlsRoutes := tlsRoutesByListener[listener.Name]
+				sortRoutesByAge(tlsRoutes)
+				claimedServerNames := sets.New[string]()
+				for _, tlsRoute := range tlsRoutes {
+					tcpProxy, validBackendRefs, resolvedRefsCondition := translateTLSRoute(tlsRoute, string(listener.Name), t.serviceLister, t.referenceGrantLister)
+
+					key := types.NamespacedName{Name: tlsRoute.Name, Namespace: tlsRoute.Namespace}
+					setRouteResolvedRefs(routeStatuses.TLSRoutes, key, resolvedRefsCondition)
+					t.logRouteCondition("TLSRoute", key, resolvedRefsCondition)
+					if tcpProxy == nil {
+						continue
+					}
+					if _, clusterErr := ensureClusters(backendClusters, envoyClusters, envoyEndpoints, tlsRoute.Namespace, validBackendRefs, clusterVariant{}); clusterErr != nil {
+						clusterErrs = append(clusterErrs, fmt.Errorf("Gateway %s/%s: TLSRoute %s/%s: %w", gateway.Namespace, gateway.Name, tlsRoute.Namespace, tlsRoute.Name, clusterErr))
+					}
+
+					serverNames := getIntersectingHostnames(listener, tlsRoute.Spec.Hostnames)
+					passthroughFilterChain, chainErr := buildTLSPassthroughFilterChain(tcpProxy, serverNames, claimedServerNames)
+					if chainErr != nil {
+						err = chainErr
+						break
+					}
+					if passthroughFilterChain != nil {
+						if countsAsAttached(resolvedRefsCondition) {
+							attachedRoutes++
+						}
+						passthroughFilterChains = append(passthroughFilterChains, passthroughFilterChain)
+					}
+				}
+
+			case gatewayv1.UDPProtocolType:
+				// Like TCP, a UDP listener forwards all datagrams to a single destination,
+				// so only the oldest UDPRoute attached to the listener can be honored.
+				udpRoutes := udpRoutesByListener[listener.Name]
+				if len(udpRoutes) == 0 {
+					break
+				}
+				sort.SliceStable(udpRoutes, func(i, j int) bool {
+					return udpRoutes[i].CreationTimestamp.Before(&udpRoutes[j].CreationTimestamp)
+				})
+				if len(udpRoutes) > 1 {
+					t.logger().Warn("listener has several UDPRoutes attached, only the oldest one is used", "listener", listener.Name,
+						"routes", len(udpRoutes), "kind", "UDPRoute", "namespace", udpRoutes[0].Namespace, "name", udpRoutes[0].Name)
+				}
+				if udpListener != nil {
+					err = fmt.Errorf("port %d is already served by another UDP listener", port)
+					break
+				}
+				udpRoute := udpRoutes[0]
+				udpProxy, validBackendRefs, resolvedRefsCondition := translateUDPRoute(udpRoute, string(listener.Name), t.serviceLister, t.referenceGrantLister)
+
+				key := types.NamespacedName{Name: udpRoute.Name, Namespace: udpRoute.Namespace}
+				setRouteResolvedRefs(routeStatuses.UDPRoutes, key, resolvedRefsCondition)
+				t.logRouteCondition("UDPRoute", key, resolvedRefsCondition)
+				if _, clusterErr := ensureClusters(backendClusters, envoyClusters, envoyEndpoints, udpRoute.Namespace, validBackendRefs, clusterVariant{}); clusterErr != nil {
+					clusterErrs = append(clusterErrs, fmt.Errorf("Gateway %s/%s: UDPRoute %s/%s: %w", gateway.Namespace, gateway.Name, udpRoute.Namespace, udpRoute.Name, clusterErr))
+				}
+
+				if udpProxy != nil {
+					if countsAsAttached(resolvedRefsCondition) {
+						attachedRoutes++
+					}
+					udpListener, err = buildUDPListener(uint32(port), udpProxy)
+				}
 
-			vhSlice := make([]*routev3.VirtualHost, 0, len(virtualHostsForPort))
-			for _, vh := range virtualHostsForPort {
-				vhSlice = append(vhSlice, vh)
+			default:
+				t.logger().Warn("unsupported listener protocol for route processing", "listener", listener.Name, "protocol", listener.Protocol)
+				filterChain, err = t.translateListenerToFilterChain(gateway, listener, nil, routeName, envoySecrets)
 			}
 
-			filterChain, err := t.translateListenerToFilterChain(gateway, listener, vhSlice, routeName)
 			if err != nil {
 				meta.SetStatusCondition(&listenerStatus.Conditions, metav1.Condition{
 					Type:               string(gatewayv1.ListenerConditionProgrammed),
@@ -272,7 +938,14 @@ func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 					ObservedGeneration: gateway.Generation,
 				})
 
-				filterChains = append(filterChains, filterChain)
+				if filterChain != nil {
+					filterChains = append(filterChains, filterChain)
+				}
+				filterChains = append(filterChains, passthroughFilterChains...)
+				filterChains = append(filterChains, certificateFilterChains...)
+				if listenerRouteConfig != nil {
+					envoyRoutes = append(envoyRoutes, listenerRouteConfig)
+				}
 			}
 
 			listenerStatus.AttachedRoutes = attachedRoutes
@@ -286,65 +959,104 @@ func (t *Translator) buildEnvoyResourcesForGateway(gateway *gatewayv1.Gateway) (
 			allListenerStatuses[listener.Name] = listenerStatus
 		}
 
-		allVirtualHosts := make([]*routev3.VirtualHost, 0, len(virtualHostsForPort))
-		for _, vh := range virtualHostsForPort {
-			sortRoutes(vh.Routes)
-			allVirtualHosts = append(allVirtualHosts, vh)
-		}
-
-		// now aggregate all the listeners on the same port
-		routeConfig := &routev3.RouteConfiguration{
-			Name:                     routeName,
-			VirtualHosts:             allVirtualHosts,
-			IgnorePortInHostMatching: true, // tricky to figure out thanks to howardjohn
-		}
-		envoyRoutes = append(envoyRoutes, routeConfig)
-
 		if len(filterChains) > 0 {
 			envoyListener := &listenerv3.Listener{
 				Name:            fmt.Sprintf("listener-%d", port),
 				Address:         createEnvoyAddress(uint32(port)),
 				FilterChains:    filterChains,
-				ListenerFilters: createListenerFilters(),
+				ListenerFilters: createListenerFilters(gateway),
+				SocketOptions:   buildListenerSocketOptions(t.options.ListenerTCPKeepalive),
 			}
 			// If this is plain HTTP, we must now create exactly ONE default filter chain.
 			// Use first listener as a template
 			// For HTTPS, we create one filter chain per listener because they have unique
 			// SNI matches and TLS settings.
 			if listeners[0].Protocol == gatewayv1.HTTPProtocolType {
-				filterChain, _ := t.translateListenerToFilterChain(gateway, listeners[0], allVirtualHosts, routeName)
+				// now aggregate all the listeners on the same port
+				routeConfig := t.buildRouteConfiguration(routeName, virtualHostsForPort, defaultBackendCluster)
+				envoyRoutes = append(envoyRoutes, routeConfig)
+				filterChain, _ := t.translateListenerToFilterChain(gateway, listeners[0], routeConfig.VirtualHosts, routeName, envoySecrets)
 				envoyListener.FilterChains = []*listenerv3.FilterChain{filterChain}
 			}
 			finalEnvoyListeners = append(finalEnvoyListeners, envoyListener)
 		}
+
+		if udpListener != nil {
+			finalEnvoyListeners = append(finalEnvoyListeners, udpListener)
+		}
 	}
 
+	originalDstErr := addOriginalDstListenerFilters(finalEnvoyListeners, envoyRoutes, envoyClusters)
+
 	clustersSlice := make([]envoyproxytypes.Resource, 0, len(envoyClusters))
 	for _, cluster := range envoyClusters {
 		clustersSlice = append(clustersSlice, cluster)
 	}
+	endpointsSlice := make([]envoyproxytypes.Resource, 0, len(envoyEndpoints))
+	for _, cla := range envoyEndpoints {
+		endpointsSlice = append(endpointsSlice, cla)
+	}
+	secretsSlice := make([]envoyproxytypes.Resource, 0, len(envoySecrets))
+	for _, secret := range envoySecrets {
+		secretsSlice = append(secretsSlice, secret)
+	}
 
-	orderedStatuses := make([]gatewayv1.ListenerStatus, len(gateway.Spec.Listeners))
-	for i, listener := range gateway.Spec.Listeners {
-		orderedStatuses[i] = allListenerStatuses[listener.Name]
+	// The listener statuses are keyed by name, so listeners sharing a name are reported once.
+	orderedStatuses := make([]gatewayv1.ListenerStatus, 0, len(gateway.Spec.Listeners))
+	reportedListeners := sets.New[gatewayv1.SectionName]()
+	for _, listener := range gateway.Spec.Listeners {
+		if reportedListeners.Has(listener.Name) {
+			continue
+		}
+		reportedListeners.Insert(listener.Name)
+		orderedStatuses = append(orderedStatuses, allListenerStatuses[listener.Name])
 	}
 
 	return map[resourcev3.Type][]envoyproxytypes.Resource{
 			resourcev3.ListenerType: finalEnvoyListeners,
 			resourcev3.RouteType:    envoyRoutes,
 			resourcev3.ClusterType:  clustersSlice,
+			resourcev3.EndpointType: endpointsSlice,
+			resourcev3.SecretType:   secretsSlice,
 		}, orderedStatuses,
-		httpRouteStatuses
+		routeStatuses,
+		errors.Join(append(append(matchErrs, clusterErrs...), proxyProtocolErr, originalDstErr)...)
+}
+
+// buildRouteConfiguration builds a route config from the given virtual hosts. The virtual
+// hosts are sorted by name and their routes by precedence, so the output is stable. Each
+// virtual host ends with a catch-all route for the requests that match none of its routes,
+// which forwards them to defaultBackendCluster, or answers them as not found if it is empty.
+func (t *Translator) buildRouteConfiguration(name string, virtualHosts map[string]*routev3.VirtualHost, defaultBackendCluster string) *routev3.RouteConfiguration {
+	vhSlice := make([]*routev3.VirtualHost, 0, len(virtualHosts))
+	for _, vh := range virtualHosts {
+		sortRoutes(vh.Routes)
+		if defaultBackendCluster != "" {
+			vh.Routes = append(vh.Routes, buildDefaultBackendRoute(vh, defaultBackendCluster))
+		} else {
+			vh.Routes = append(vh.Routes, buildNotFoundRoute(vh, t.options))
+		}
+		vhSlice = append(vhSlice, vh)
+	}
+	sort.Slice(vhSlice, func(i, j int) bool {
+		return vhSlice[i].Name < vhSlice[j].Name
+	})
+	return &routev3.RouteConfiguration{
+		Name:                     name,
+		VirtualHosts:             vhSlice,
+		IgnorePortInHostMatching: true, // tricky to figure out thanks to howardjohn
+	}
 }
 
 func getSupportedKinds(listener gatewayv1.Listener) ([]gatewayv1.RouteGroupKind, bool) {
 	supportedKinds := []gatewayv1.RouteGroupKind{}
 	allKindsValid := true
 	groupName := gatewayv1.Group(gatewayv1.GroupName)
+	kindsForProtocol := sets.New(supportedKindsByProtocol[listener.Protocol]...)
 
 	if listener.AllowedRoutes != nil && len(listener.AllowedRoutes.Kinds) > 0 {
 		for _, kind := range listener.AllowedRoutes.Kinds {
-			if (kind.Group == nil || *kind.Group == groupName) && SupportedKinds.Has(kind.Kind) {
+			if (kind.Group == nil || *kind.Group == groupName) && SupportedKinds.Has(kind.Kind) && kindsForProtocol.Has(kind.Kind) {
 				supportedKinds = append(supportedKinds, gatewayv1.RouteGroupKind{
 					Group: &groupName,
 					Kind:  kind.Kind,
@@ -353,8 +1065,8 @@ func getSupportedKinds(listener gatewayv1.Listener) ([]gatewayv1.RouteGroupKind,
 				allKindsValid = false
 			}
 		}
-	} else if listener.Protocol == gatewayv1.HTTPProtocolType || listener.Protocol == gatewayv1.HTTPSProtocolType {
-		for _, kind := range SupportedKinds.UnsortedList() {
+	} else {
+		for _, kind := range supportedKindsByProtocol[listener.Protocol] {
 			supportedKinds = append(supportedKinds,
 				gatewayv1.RouteGroupKind{
 					Group: &groupName,
@@ -366,18 +1078,138 @@ func getSupportedKinds(listener gatewayv1.Listener) ([]gatewayv1.RouteGroupKind,
 
 	return supportedKinds, allKindsValid
 }
-func (t *Translator) updateRouteStatuses(
+
+// setRouteResolvedRefs records the ResolvedRefs condition on every parent that accepted the route.
+func setRouteResolvedRefs(statuses map[types.NamespacedName][]gatewayv1.RouteParentStatus, key types.NamespacedName, condition metav1.Condition) {
+	currentParentStatuses := statuses[key]
+	for i := range currentParentStatuses {
+		// Only add the ResolvedRefs condition if the parent was Accepted.
+		if meta.IsStatusConditionTrue(currentParentStatuses[i].Conditions, string(gatewayv1.RouteConditionAccepted)) {
+			meta.SetStatusCondition(&currentParentStatuses[i].Conditions, condition)
+		}
+	}
+	statuses[key] = currentParentStatuses
+}
+
+// countsAsAttached reports whether a translated route with the ResolvedRefs condition counts
+// towards the attachedRoutes of its listener. Routes with a backend that no ReferenceGrant
+// permits do not count, even if they still forward to their other backends.
+func countsAsAttached(resolvedRefsCondition metav1.Condition) bool {
+	return resolvedRefsCondition.Status != metav1.ConditionFalse ||
+		resolvedRefsCondition.Reason != string(gatewayv1.RouteReasonRefNotPermitted)
+}
+
+// ensureClusters creates the Envoy clusters for the given backends, reusing any cluster
+// that was already created for the same backend. Clusters are built through backendClusters,
+// so each backend is only resolved once. It returns the clusters of the given variant for the
+// backends.
+// Backends whose cluster cannot be built are skipped and their errors joined.
+func ensureClusters(backendClusters *clusterCache, envoyClusters, envoyEndpoints map[string]envoyproxytypes.Resource, namespace string, backendRefs []gatewayv1.BackendRef, variant clusterVariant) ([]*clusterv3.Cluster, error) {
+	var clusters []*clusterv3.Cluster
+	var errs []error
+	for _, backendRef := range backendRefs {
+		cluster, cla, err := backendClusters.get(namespace, backendRef, variant)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skipping cluster for backend %s: %w", backendRef.Name, err))
+			continue
+		}
+		if cluster == nil {
+			continue
+		}
+		if existing, exists := envoyClusters[cluster.Name]; exists {
+			cluster = existing.(*clusterv3.Cluster)
+		} else {
+			envoyClusters[cluster.Name] = cluster
+			// Only EDS clusters have separate endpoints.
+			if cla != nil {
+				envoyEndpoints[cla.ClusterName] = cla
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, errors.Join(errs...)
+}
+
+// dropMissingClusters removes the clusters that could not be built from the actions of the
+// routes, so that the routes only forward to clusters that exist: they are left out of
+// weighted clusters and request mirrors, and routes without any cluster left answer with a
+// 500. It reports whether any cluster was missing.
+func dropMissingClusters(routes []*routev3.Route, envoyClusters map[string]envoyproxytypes.Resource) bool {
+	dropped := false
+	exists := func(name string) bool {
+		if _, ok := envoyClusters[name]; ok {
+			return true
+		}
+		dropped = true
+		return false
+	}
+	for _, route := range routes {
+		routeAction := route.GetRoute()
+		if routeAction == nil {
+			continue
+		}
+		var mirrorPolicies []*routev3.RouteAction_RequestMirrorPolicy
+		for _, mirrorPolicy := range routeAction.RequestMirrorPolicies {
+			if exists(mirrorPolicy.Cluster) {
+				mirrorPolicies = append(mirrorPolicies, mirrorPolicy)
+			}
+		}
+		routeAction.RequestMirrorPolicies = mirrorPolicies
+
+		forwards := true
+		if weightedClusters := routeAction.GetWeightedClusters(); weightedClusters != nil {
+			var clusterWeights []*routev3.WeightedCluster_ClusterWeight
+			for _, clusterWeight := range weightedClusters.Clusters {
+				if exists(clusterWeight.Name) {
+					clusterWeights = append(clusterWeights, clusterWeight)
+				}
+			}
+			weightedClusters.Clusters = clusterWeights
+			forwards = len(clusterWeights) > 0
+		} else if cluster := routeAction.GetCluster(); cluster != "" {
+			forwards = exists(cluster)
+		}
+		if !forwards {
+			route.Action = &routev3.Route_DirectResponse{
+				DirectResponse: &routev3.DirectResponseAction{Status: 500},
+			}
+		}
+	}
+	return dropped
+}
+
+// missingClustersCondition returns the ResolvedRefs condition of a route whose routes had
+// clusters dropped, given the error of building the clusters. The reason of a ControllerError
+// is kept, any other cluster is reported as not found.
+func missingClustersCondition(clusterErr error, generation int64) metav1.Condition {
+	reason := gatewayv1.RouteReasonBackendNotFound
+	var controllerErr *ControllerError
+	if errors.As(clusterErr, &controllerErr) {
+		reason = gatewayv1.RouteConditionReason(controllerErr.Reason)
+	}
+	message := "the clusters of some backends could not be built"
+	if clusterErr != nil {
+		message = clusterErr.Error()
+	}
+	return createFailureCondition(reason, message, generation)
+}
+
+// UpdateRouteStatuses writes the computed parent statuses into the status of each route.
+// Statuses for parentRefs that were not computed, e.g. those owned by other controllers,
+// are preserved. The latest version of each route is read through gwClient so that
+// conflicting writes can be retried.
+func UpdateRouteStatuses(
 	ctx context.Context,
-	httpRouteStatuses map[types.NamespacedName][]gatewayv1.RouteParentStatus,
-	grpcRouteStatuses map[types.NamespacedName][]gatewayv1.RouteParentStatus,
+	gwClient gatewayclient.Interface,
+	routeStatuses RouteStatuses,
 ) error {
 	var errGroup []error
 
 	// --- Process HTTPRoutes ---
-	for key, desiredParentStatuses := range httpRouteStatuses {
+	for key, desiredParentStatuses := range routeStatuses.HTTPRoutes {
 		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			// GET the latest version of the route from the cache.
-			originalRoute, err := t.httprouteLister.HTTPRoutes(key.Namespace).Get(key.Name)
+			// GET the latest version of the route from the API server.
+			originalRoute, err := gwClient.GatewayV1().HTTPRoutes(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
 			if apierrors.IsNotFound(err) {
 				// Route has been deleted, nothing to do.
 				return nil
@@ -387,11 +1219,11 @@ func (t *Translator) updateRouteStatuses(
 
 			// Create a mutable copy to work with.
 			routeToUpdate := originalRoute.DeepCopy()
-			routeToUpdate.Status.Parents = desiredParentStatuses
+			routeToUpdate.Status.Parents = mergeRouteParentStatuses(originalRoute.Status.Parents, desiredParentStatuses)
 
 			// Only make an API call if the status has actually changed.
 			if !reflect.DeepEqual(originalRoute.Status, routeToUpdate.Status) {
-				_, updateErr := t.gwClient.GatewayV1().HTTPRoutes(routeToUpdate.Namespace).UpdateStatus(ctx, routeToUpdate, metav1.UpdateOptions{})
+				_, updateErr := gwClient.GatewayV1().HTTPRoutes(routeToUpdate.Namespace).UpdateStatus(ctx, routeToUpdate, metav1.UpdateOptions{})
 				return updateErr
 			}
 
@@ -404,17 +1236,138 @@ func (t *Translator) updateRouteStatuses(
 		}
 	}
 
-	// TODO: Process GRPCRoutes (repeat the same logic)
+	// --- Process GRPCRoutes ---
+	for key, desiredParentStatuses := range routeStatuses.GRPCRoutes {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			originalRoute, err := gwClient.GatewayV1().GRPCRoutes(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
 
-	return errors.Join(errGroup...)
-}
+			routeToUpdate := originalRoute.DeepCopy()
+			routeToUpdate.Status.Parents = mergeRouteParentStatuses(originalRoute.Status.Parents, desiredParentStatuses)
 
-// getHTTPRoutesForGateway returns all HTTPRoutes that have a ParentRef pointing to the specified Gateway.
-func (t *Translator) getHTTPRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1.HTTPRoute {
-	var matchingRoutes []*gatewayv1.HTTPRoute
-	allRoutes, err := t.httprouteLister.List(labels.Everything())
+			if !reflect.DeepEqual(originalRoute.Status, routeToUpdate.Status) {
+				_, updateErr := gwClient.GatewayV1().GRPCRoutes(routeToUpdate.Namespace).UpdateStatus(ctx, routeToUpdate, metav1.UpdateOptions{})
+				return updateErr
+			}
+			return nil
+		})
+
+		if err != nil {
+			errGroup = append(errGroup, fmt.Errorf("failed to update status for GRPCRoute %s: %w", key, err))
+		}
+	}
+
+	// --- Process TCPRoutes ---
+	for key, desiredParentStatuses := range routeStatuses.TCPRoutes {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			originalRoute, err := gwClient.GatewayV1alpha2().TCPRoutes(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			routeToUpdate := originalRoute.DeepCopy()
+			routeToUpdate.Status.Parents = mergeRouteParentStatuses(originalRoute.Status.Parents, desiredParentStatuses)
+
+			if !reflect.DeepEqual(originalRoute.Status, routeToUpdate.Status) {
+				_, updateErr := gwClient.GatewayV1alpha2().TCPRoutes(routeToUpdate.Namespace).UpdateStatus(ctx, routeToUpdate, metav1.UpdateOptions{})
+				return updateErr
+			}
+			return nil
+		})
+
+		if err != nil {
+			errGroup = append(errGroup, fmt.Errorf("failed to update status for TCPRoute %s: %w", key, err))
+		}
+	}
+
+	// --- Process TLSRoutes ---
+	for key, desiredParentStatuses := range routeStatuses.TLSRoutes {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			originalRoute, err := gwClient.GatewayV1alpha2().TLSRoutes(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			routeToUpdate := originalRoute.DeepCopy()
+			routeToUpdate.Status.Parents = mergeRouteParentStatuses(originalRoute.Status.Parents, desiredParentStatuses)
+
+			if !reflect.DeepEqual(originalRoute.Status, routeToUpdate.Status) {
+				_, updateErr := gwClient.GatewayV1alpha2().TLSRoutes(routeToUpdate.Namespace).UpdateStatus(ctx, routeToUpdate, metav1.UpdateOptions{})
+				return updateErr
+			}
+			return nil
+		})
+
+		if err != nil {
+			errGroup = append(errGroup, fmt.Errorf("failed to update status for TLSRoute %s: %w", key, err))
+		}
+	}
+
+	// --- Process UDPRoutes ---
+	for key, desiredParentStatuses := range routeStatuses.UDPRoutes {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			originalRoute, err := gwClient.GatewayV1alpha2().UDPRoutes(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			routeToUpdate := originalRoute.DeepCopy()
+			routeToUpdate.Status.Parents = mergeRouteParentStatuses(originalRoute.Status.Parents, desiredParentStatuses)
+
+			if !reflect.DeepEqual(originalRoute.Status, routeToUpdate.Status) {
+				_, updateErr := gwClient.GatewayV1alpha2().UDPRoutes(routeToUpdate.Namespace).UpdateStatus(ctx, routeToUpdate, metav1.UpdateOptions{})
+				return updateErr
+			}
+			return nil
+		})
+
+		if err != nil {
+			errGroup = append(errGroup, fmt.Errorf("failed to update status for UDPRoute %s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errGroup...)
+}
+
+// mergeRouteParentStatuses merges the desired parent statuses into the existing ones.
+// Conditions of an existing status for the same parentRef and controller are updated in
+// place, so their LastTransitionTime only changes when their status does.
+func mergeRouteParentStatuses(existing, desired []gatewayv1.RouteParentStatus) []gatewayv1.RouteParentStatus {
+	merged := make([]gatewayv1.RouteParentStatus, 0, len(existing)+len(desired))
+	for _, parentStatus := range existing {
+		merged = append(merged, *parentStatus.DeepCopy())
+	}
+	for _, desiredStatus := range desired {
+		i := slices.IndexFunc(merged, func(parentStatus gatewayv1.RouteParentStatus) bool {
+			return parentStatus.ControllerName == desiredStatus.ControllerName && reflect.DeepEqual(parentStatus.ParentRef, desiredStatus.ParentRef)
+		})
+		if i < 0 {
+			merged = append(merged, desiredStatus)
+			continue
+		}
+		for _, condition := range desiredStatus.Conditions {
+			meta.SetStatusCondition(&merged[i].Conditions, condition)
+		}
+	}
+	return merged
+}
+
+// getHTTPRoutesForGateway returns all HTTPRoutes that have a ParentRef pointing to the specified Gateway.
+func (t *Translator) getHTTPRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1.HTTPRoute {
+	var matchingRoutes []*gatewayv1.HTTPRoute
+	allRoutes, err := t.httprouteLister.List(labels.Everything())
 	if err != nil {
-		klog.Errorf("failed to list HTTPRoutes: %v", err)
+		t.logger().Error("failed to list HTTPRoutes", "err", err)
 		return matchingRoutes
 	}
 
@@ -434,6 +1387,102 @@ func (t *Translator) getHTTPRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1
 	return matchingRoutes
 }
 
+// getGRPCRoutesForGateway returns all GRPCRoutes that have a ParentRef pointing to the specified Gateway.
+func (t *Translator) getGRPCRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1.GRPCRoute {
+	var matchingRoutes []*gatewayv1.GRPCRoute
+	allRoutes, err := t.grpcrouteLister.List(labels.Everything())
+	if err != nil {
+		t.logger().Error("failed to list GRPCRoutes", "err", err)
+		return matchingRoutes
+	}
+
+	for _, route := range allRoutes {
+		for _, parentRef := range route.Spec.ParentRefs {
+			refNamespace := route.Namespace
+			if parentRef.Namespace != nil {
+				refNamespace = string(*parentRef.Namespace)
+			}
+			if parentRef.Name == gatewayv1.ObjectName(gw.Name) && refNamespace == gw.Namespace {
+				matchingRoutes = append(matchingRoutes, route)
+				break
+			}
+		}
+	}
+	return matchingRoutes
+}
+
+// getTCPRoutesForGateway returns all TCPRoutes that have a ParentRef pointing to the specified Gateway.
+func (t *Translator) getTCPRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1alpha2.TCPRoute {
+	var matchingRoutes []*gatewayv1alpha2.TCPRoute
+	allRoutes, err := t.tcprouteLister.List(labels.Everything())
+	if err != nil {
+		t.logger().Error("failed to list TCPRoutes", "err", err)
+		return matchingRoutes
+	}
+
+	for _, route := range allRoutes {
+		for _, parentRef := range route.Spec.ParentRefs {
+			refNamespace := route.Namespace
+			if parentRef.Namespace != nil {
+				refNamespace = string(*parentRef.Namespace)
+			}
+			if parentRef.Name == gatewayv1.ObjectName(gw.Name) && refNamespace == gw.Namespace {
+				matchingRoutes = append(matchingRoutes, route)
+				break
+			}
+		}
+	}
+	return matchingRoutes
+}
+
+// getTLSRoutesForGateway returns all TLSRoutes that have a ParentRef pointing to the specified Gateway.
+func (t *Translator) getTLSRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1alpha2.TLSRoute {
+	var matchingRoutes []*gatewayv1alpha2.TLSRoute
+	allRoutes, err := t.tlsrouteLister.List(labels.Everything())
+	if err != nil {
+		t.logger().Error("failed to list TLSRoutes", "err", err)
+		return matchingRoutes
+	}
+
+	for _, route := range allRoutes {
+		for _, parentRef := range route.Spec.ParentRefs {
+			refNamespace := route.Namespace
+			if parentRef.Namespace != nil {
+				refNamespace = string(*parentRef.Namespace)
+			}
+			if parentRef.Name == gatewayv1.ObjectName(gw.Name) && refNamespace == gw.Namespace {
+				matchingRoutes = append(matchingRoutes, route)
+				break
+			}
+		}
+	}
+	return matchingRoutes
+}
+
+// getUDPRoutesForGateway returns all UDPRoutes that have a ParentRef pointing to the specified Gateway.
+func (t *Translator) getUDPRoutesForGateway(gw *gatewayv1.Gateway) []*gatewayv1alpha2.UDPRoute {
+	var matchingRoutes []*gatewayv1alpha2.UDPRoute
+	allRoutes, err := t.udprouteLister.List(labels.Everything())
+	if err != nil {
+		t.logger().Error("failed to list UDPRoutes", "err", err)
+		return matchingRoutes
+	}
+
+	for _, route := range allRoutes {
+		for _, parentRef := range route.Spec.ParentRefs {
+			refNamespace := route.Namespace
+			if parentRef.Namespace != nil {
+				refNamespace = string(*parentRef.Namespace)
+			}
+			if parentRef.Name == gatewayv1.ObjectName(gw.Name) && refNamespace == gw.Namespace {
+				matchingRoutes = append(matchingRoutes, route)
+				break
+			}
+		}
+	}
+	return matchingRoutes
+}
+
 // validateHTTPRoute is the definitive validation function. It iterates through all
 // parentRefs of an HTTPRoute and generates a complete RouteParentStatus for each one
 // that targets the specified Gateway. It also returns a slice of all listeners
@@ -442,19 +1491,75 @@ func (t *Translator) validateHTTPRoute(
 	gateway *gatewayv1.Gateway,
 	httpRoute *gatewayv1.HTTPRoute,
 ) ([]gatewayv1.RouteParentStatus, []gatewayv1.Listener) {
-
-	var parentStatuses []gatewayv1.RouteParentStatus
-	// Use a map to collect a unique set of listeners that accepted the route.
-	acceptedListenerSet := make(map[gatewayv1.SectionName]gatewayv1.Listener)
-
 	// --- Determine the ResolvedRefs status for the entire Route first. ---
 	// This is a property of the route itself, independent of any parent.
+	resolvedRefsCondition := newResolvedRefsCondition(t.areBackendsValid(httpRoute), httpRoute.Generation)
+	return t.validateRouteParentRefs(gateway, httpRoute, httpRoute.Spec.ParentRefs, resolvedRefsCondition)
+}
+
+// validateGRPCRoute is the GRPCRoute counterpart of validateHTTPRoute.
+func (t *Translator) validateGRPCRoute(
+	gateway *gatewayv1.Gateway,
+	grpcRoute *gatewayv1.GRPCRoute,
+) ([]gatewayv1.RouteParentStatus, []gatewayv1.Listener) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range grpcRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	resolvedRefsCondition := newResolvedRefsCondition(t.backendRefsExist(grpcRoute.Namespace, backendRefs), grpcRoute.Generation)
+	return t.validateRouteParentRefs(gateway, grpcRoute, grpcRoute.Spec.ParentRefs, resolvedRefsCondition)
+}
+
+// validateTCPRoute is the TCPRoute counterpart of validateHTTPRoute.
+func (t *Translator) validateTCPRoute(
+	gateway *gatewayv1.Gateway,
+	tcpRoute *gatewayv1alpha2.TCPRoute,
+) ([]gatewayv1.RouteParentStatus, []gatewayv1.Listener) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range tcpRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	resolvedRefsCondition := newResolvedRefsCondition(t.backendRefsExist(tcpRoute.Namespace, backendRefs), tcpRoute.Generation)
+	return t.validateRouteParentRefs(gateway, tcpRoute, tcpRoute.Spec.ParentRefs, resolvedRefsCondition)
+}
+
+// validateTLSRoute is the TLSRoute counterpart of validateHTTPRoute.
+func (t *Translator) validateTLSRoute(
+	gateway *gatewayv1.Gateway,
+	tlsRoute *gatewayv1alpha2.TLSRoute,
+) ([]gatewayv1.RouteParentStatus, []gatewayv1.Listener) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range tlsRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	resolvedRefsCondition := newResolvedRefsCondition(t.backendRefsExist(tlsRoute.Namespace, backendRefs), tlsRoute.Generation)
+	return t.validateRouteParentRefs(gateway, tlsRoute, tlsRoute.Spec.ParentRefs, resolvedRefsCondition)
+}
+
+// validateUDPRoute is the UDPRoute counterpart of validateHTTPRoute.
+func (t *Translator) validateUDPRoute(
+	gateway *gatewayv1.Gateway,
+	udpRoute *gatewayv1alpha2.UDPRoute,
+) ([]gatewayv1.RouteParentStatus, []gatewayv1.Listener) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range udpRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	resolvedRefsCondition := newResolvedRefsCondition(t.backendRefsExist(udpRoute.Namespace, backendRefs), udpRoute.Generation)
+	return t.validateRouteParentRefs(gateway, udpRoute, udpRoute.Spec.ParentRefs, resolvedRefsCondition)
+}
+
+// newResolvedRefsCondition builds the route-level ResolvedRefs condition based on
+// whether all of the route's backends could be found.
+func newResolvedRefsCondition(backendsValid bool, generation int64) metav1.Condition {
 	resolvedRefsCondition := metav1.Condition{
 		Type:               string(gatewayv1.RouteConditionResolvedRefs),
-		ObservedGeneration: httpRoute.Generation,
+		ObservedGeneration: generation,
 		LastTransitionTime: metav1.Now(),
 	}
-	if t.areBackendsValid(httpRoute) {
+	if backendsValid {
 		resolvedRefsCondition.Status = metav1.ConditionTrue
 		resolvedRefsCondition.Reason = string(gatewayv1.RouteReasonResolvedRefs)
 		resolvedRefsCondition.Message = "All backend references have been resolved."
@@ -463,11 +1568,26 @@ func (t *Translator) validateHTTPRoute(
 		resolvedRefsCondition.Reason = string(gatewayv1.RouteReasonBackendNotFound)
 		resolvedRefsCondition.Message = "One or more backend references could not be found."
 	}
+	return resolvedRefsCondition
+}
+
+// validateRouteParentRefs generates a RouteParentStatus for each parentRef of a route
+// that targets the specified Gateway, and returns the listeners that accepted the route.
+func (t *Translator) validateRouteParentRefs(
+	gateway *gatewayv1.Gateway,
+	route metav1.Object,
+	parentRefs []gatewayv1.ParentReference,
+	resolvedRefsCondition metav1.Condition,
+) ([]gatewayv1.RouteParentStatus, []gatewayv1.Listener) {
+
+	var parentStatuses []gatewayv1.RouteParentStatus
+	// Use a map to collect a unique set of listeners that accepted the route.
+	acceptedListenerSet := make(map[gatewayv1.SectionName]gatewayv1.Listener)
 
-	// --- Iterate over EACH ParentRef in the HTTPRoute ---
-	for _, parentRef := range httpRoute.Spec.ParentRefs {
+	// --- Iterate over EACH ParentRef in the route ---
+	for _, parentRef := range parentRefs {
 		// We only care about refs that target our current Gateway.
-		refNamespace := httpRoute.Namespace
+		refNamespace := route.GetNamespace()
 		if parentRef.Namespace != nil {
 			refNamespace = string(*parentRef.Namespace)
 		}
@@ -486,11 +1606,11 @@ func (t *Translator) validateHTTPRoute(
 
 			if sectionNameMatches && portMatches {
 				// The listener matches the ref. Now check if the listener's policy (e.g., hostname) allows it.
-				if !isAllowedByListener(gateway, listener, httpRoute, t.namespaceLister) {
+				if !isAllowedByListener(gateway, listener, route, t.namespaceLister, t.logger()) {
 					rejectionReason = gatewayv1.RouteReasonNotAllowedByListeners
 					continue
 				}
-				if !isAllowedByHostname(listener, httpRoute) {
+				if !isAllowedByHostname(listener, route) {
 					rejectionReason = gatewayv1.RouteReasonNoMatchingListenerHostname
 					continue
 				}
@@ -501,25 +1621,27 @@ func (t *Translator) validateHTTPRoute(
 		// --- Build the final status for this ParentRef ---
 		status := gatewayv1.RouteParentStatus{
 			ParentRef:      parentRef,
-			ControllerName: "test",
+			ControllerName: gatewayv1.GatewayController(t.controllerName()),
 			Conditions:     []metav1.Condition{},
 		}
 
 		// Create the 'Accepted' condition based on the listener validation.
 		acceptedCondition := metav1.Condition{
 			Type:               string(gatewayv1.RouteConditionAccepted),
-			ObservedGeneration: httpRoute.Generation,
+			ObservedGeneration: route.GetGeneration(),
 			LastTransitionTime: metav1.Now(),
 		}
 
 		if len(listenersForThisRef) == 0 {
 			acceptedCondition.Status = metav1.ConditionFalse
 			acceptedCondition.Reason = string(rejectionReason)
-			acceptedCondition.Message = "No listener matched the parentRef."
-			if rejectionReason == gatewayv1.RouteReasonNotAllowedByListeners {
+			switch rejectionReason {
+			case gatewayv1.RouteReasonNotAllowedByListeners:
 				acceptedCondition.Message = "Route is not allowed by a listener's policy."
-			} else {
+			case gatewayv1.RouteReasonNoMatchingListenerHostname:
 				acceptedCondition.Message = "The route's hostnames do not match any listener hostnames."
+			default:
+				acceptedCondition.Message = "No listener matched the parentRef."
 			}
 		} else {
 			acceptedCondition.Status = metav1.ConditionTrue
@@ -545,18 +1667,32 @@ func (t *Translator) validateHTTPRoute(
 
 // areBackendsValid is a helper extracted from the original validate function.
 func (t *Translator) areBackendsValid(httpRoute *gatewayv1.HTTPRoute) bool {
+	var backendRefs []gatewayv1.BackendRef
 	for _, rule := range httpRoute.Spec.Rules {
 		if ruleHasRedirectFilter(rule) {
 			continue
 		}
 		for _, backendRef := range rule.BackendRefs {
-			ns := httpRoute.Namespace
-			if backendRef.Namespace != nil {
-				ns = string(*backendRef.Namespace)
-			}
-			if _, err := t.serviceLister.Services(ns).Get(string(backendRef.Name)); err != nil {
-				return false
-			}
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	return t.backendRefsExist(httpRoute.Namespace, backendRefs)
+}
+
+// backendRefsExist reports whether every referenced Service can be found and exposes the
+// referenced port.
+func (t *Translator) backendRefsExist(namespace string, backendRefs []gatewayv1.BackendRef) bool {
+	for _, backendRef := range backendRefs {
+		ns := namespace
+		if backendRef.Namespace != nil {
+			ns = string(*backendRef.Namespace)
+		}
+		service, err := t.serviceLister.Services(ns).Get(string(backendRef.Name))
+		if err != nil {
+			return false
+		}
+		if backendRef.Port != nil && !serviceHasPort(service, int32(*backendRef.Port)) {
+			return false
 		}
 	}
 	return true
@@ -572,79 +1708,179 @@ func ruleHasRedirectFilter(rule gatewayv1.HTTPRouteRule) bool {
 	return false
 }
 
-func (t *Translator) translateBackendRefToCluster(defaultNamespace string, backendRef gatewayv1.BackendRef) (*clusterv3.Cluster, error) {
+// translateBackendRefToCluster builds the cluster of a backend. The endpoints of the cluster
+// are only returned for EDS clusters; other clusters embed them.
+func (t *Translator) translateBackendRefToCluster(defaultNamespace string, backendRef gatewayv1.BackendRef, variant clusterVariant) (*clusterv3.Cluster, *endpointv3.ClusterLoadAssignment, error) {
 	ns := defaultNamespace
 	if backendRef.Namespace != nil {
 		ns = string(*backendRef.Namespace)
 	}
 	service, err := t.serviceLister.Services(ns).Get(string(backendRef.Name))
 	if err != nil {
-		return nil, fmt.Errorf("could not find service %s/%s: %w", ns, backendRef.Name, err)
+		return nil, nil, fmt.Errorf("could not find service %s/%s: %w", ns, backendRef.Name, err)
 	}
 
 	clusterName, err := backendRefToClusterName(defaultNamespace, backendRef)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	clusterName = variant.clusterName(clusterName)
+
+	connectTimeout, err := buildConnectTimeout(t.options.connectTimeout(), service)
+	if err != nil {
+		return nil, nil, err
+	}
+	upstreamConnectionOptions, err := buildUpstreamConnectionOptions(t.options.TCPKeepalive, service)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Create the base cluster configuration.
 	cluster := &clusterv3.Cluster{
-		Name:           clusterName,
-		ConnectTimeout: durationpb.New(5 * time.Second),
+		Name:                      clusterName,
+		ConnectTimeout:            connectTimeout,
+		UpstreamConnectionOptions: upstreamConnectionOptions,
 	}
 
-	if service.Spec.ClusterIP == corev1.ClusterIPNone {
-		// Use STRICT_DNS discovery and the service's FQDN.
+	resolution, err := serviceResolution(service)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		// ExternalName Services have no endpoints. Envoy resolves their external hostname
+		// through DNS instead, so that requests can be routed off-cluster.
 		cluster.ClusterDiscoveryType = &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STRICT_DNS}
-		// Construct the FQDN for the service.
-		fqdn := fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, service.Namespace)
-		// Get the port of the endpoints.
-		targetPort := 0
-		for _, port := range service.Spec.Ports {
-			if port.Port == int32(*backendRef.Port) {
-				targetPort = int(port.TargetPort.IntVal)
-				break
-			}
-		}
-		if targetPort == 0 {
-			return nil, fmt.Errorf("could not find port %d in service %s/%s", *backendRef.Port, service.Namespace, service.Name)
+		cluster.LoadAssignment = buildDNSLoadAssignment(clusterName, service.Spec.ExternalName, uint32(*backendRef.Port))
+	} else if resolution == resolutionOriginalDst {
+		// Connections are forwarded to the address they were originally destined to, which
+		// the original destination listener filter recovers. The hosts of the cluster are
+		// created on demand, so it has no endpoints.
+		cluster.ClusterDiscoveryType = &clusterv3.Cluster_Type{Type: clusterv3.Cluster_ORIGINAL_DST}
+	} else if resolution == resolutionDNS {
+		// Envoy resolves the Service's DNS name, which avoids tracking the individual pods.
+		port, err := serviceDNSPort(service, int32(*backendRef.Port))
+		if err != nil {
+			return nil, nil, err
 		}
-		cluster.LoadAssignment = createClusterLoadAssignment(clusterName, fqdn, uint32(targetPort))
+		cluster.ClusterDiscoveryType = &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STRICT_DNS}
+		cluster.LoadAssignment = buildDNSLoadAssignment(clusterName, serviceDNSName(service, t.options.ClusterDomain), port)
+		cluster.DnsLookupFamily = serviceDNSLookupFamily(service)
 	} else {
-		// Use STATIC discovery with the service's ClusterIP.
-		cluster.ClusterDiscoveryType = &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STATIC}
-		cluster.LoadAssignment = createClusterLoadAssignment(clusterName, service.Spec.ClusterIP, uint32(*backendRef.Port))
-	}
-
-	return cluster, nil
-}
-
-func createClusterLoadAssignment(clusterName, serviceHost string, servicePort uint32) *endpointv3.ClusterLoadAssignment {
-	return &endpointv3.ClusterLoadAssignment{
-		ClusterName: clusterName,
-		Endpoints: []*endpointv3.LocalityLbEndpoints{
-			{
-				LbEndpoints: []*endpointv3.LbEndpoint{
-					{
-						HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
-							Endpoint: &endpointv3.Endpoint{
-								Address: &corev3.Address{
-									Address: &corev3.Address_SocketAddress{
-										SocketAddress: &corev3.SocketAddress{
-											Address: serviceHost,
-											PortSpecifier: &corev3.SocketAddress_PortValue{
-												PortValue: servicePort,
-											},
-										},
-									},
-								},
-							},
-						},
-					},
+		// Endpoints are served over EDS, built from the Service's EndpointSlices. This load
+		// balances across the backend pods directly instead of relying on kube-proxy, for
+		// headless and ClusterIP Services alike.
+		cluster.ClusterDiscoveryType = &clusterv3.Cluster_Type{Type: clusterv3.Cluster_EDS}
+		cluster.EdsClusterConfig = &clusterv3.Cluster_EdsClusterConfig{
+			EdsConfig: &corev3.ConfigSource{
+				ResourceApiVersion: corev3.ApiVersion_V3,
+				ConfigSourceSpecifier: &corev3.ConfigSource_Ads{
+					Ads: &corev3.AggregatedConfigSource{},
+				},
+			},
+		}
+	}
+
+	if cluster.GetType() == clusterv3.Cluster_ORIGINAL_DST {
+		// The original destination load balancer, formerly ORIGINAL_DST_LB, is provided
+		// by the cluster itself.
+		cluster.LbPolicy = clusterv3.Cluster_CLUSTER_PROVIDED
+	} else if err := setLBPolicy(cluster, t.options.DefaultLBPolicy, service); err != nil {
+		return nil, nil, err
+	}
+	// Clusters that already balance the load by consistent hashing keep their policy.
+	if variant.sessionAffinity && cluster.LbPolicy != clusterv3.Cluster_RING_HASH &&
+		cluster.LbPolicy != clusterv3.Cluster_MAGLEV && cluster.LbPolicy != clusterv3.Cluster_CLUSTER_PROVIDED {
+		cluster.LbPolicy = clusterv3.Cluster_RING_HASH
+		cluster.LbConfig = nil
+	}
+
+	circuitBreakers, err := buildCircuitBreakers(t.options.CircuitBreakers, service)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster.CircuitBreakers = circuitBreakers
+
+	// Endpoints are ejected passively based on their responses. This needs the individual
+	// endpoints of the EDS cluster, so it is only configured for those.
+	if cluster.GetType() == clusterv3.Cluster_EDS {
+		outlierDetection, err := buildOutlierDetection(service)
+		if err != nil {
+			return nil, nil, err
+		}
+		cluster.OutlierDetection = outlierDetection
+	}
+
+	healthChecks, err := buildHealthChecks(service)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster.HealthChecks = healthChecks
+
+	commonLBConfig, err := buildCommonLBConfig(service)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster.CommonLbConfig = commonLBConfig
+	if service.Annotations[healthCheckAnnotation] == healthCheckTypeGRPC {
+		// gRPC health checks are sent over HTTP/2, which a gRPC backend speaks anyway.
+		if err := enableHTTP2(cluster); err != nil {
+			return nil, nil, err
+		}
+	}
+	if variant.http2 || usesH2C(service, int32(*backendRef.Port)) {
+		if err := enableHTTP2(cluster); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Connections to the backend are TLS-encrypted if a BackendTLSPolicy targets the Service.
+	policy, err := t.findBackendTLSPolicy(service, int32(*backendRef.Port))
+	if err != nil {
+		return nil, nil, err
+	}
+	if policy != nil {
+		transportSocket, err := t.buildUpstreamTLSTransportSocket(policy)
+		if err != nil {
+			return nil, nil, &ControllerError{
+				Reason:  string(routeReasonInvalidBackendTLSPolicy),
+				Message: fmt.Sprintf("invalid BackendTLSPolicy %s/%s for service %s/%s: %v", policy.Namespace, policy.Name, ns, service.Name, err),
+			}
+		}
+		cluster.TransportSocket = transportSocket
+	}
+
+	if cluster.GetType() != clusterv3.Cluster_EDS {
+		return cluster, nil, nil
+	}
+	cla, err := buildClusterLoadAssignment(clusterName, service, int32(*backendRef.Port), t.endpointSliceLister)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cluster, cla, nil
+}
+
+// enableHTTP2 configures the cluster to speak HTTP/2 to its upstream hosts.
+// Without TLS this results in cleartext HTTP/2 (h2c), which is what gRPC backends expect.
+func enableHTTP2(cluster *clusterv3.Cluster) error {
+	protocolOptions, err := anypb.New(&httpv3.HttpProtocolOptions{
+		UpstreamProtocolOptions: &httpv3.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &httpv3.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &httpv3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+					Http2ProtocolOptions: &corev3.Http2ProtocolOptions{},
 				},
 			},
 		},
+	})
+	if err != nil {
+		return err
+	}
+	if cluster.TypedExtensionProtocolOptions == nil {
+		cluster.TypedExtensionProtocolOptions = make(map[string]*anypb.Any)
 	}
+	cluster.TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"] = protocolOptions
+	return nil
 }
 
 // getRouteHostnames determines the effective hostnames for a route.
@@ -692,7 +1928,7 @@ func setGatewayConditions(newGw *gatewayv1.Gateway, listenerStatuses []gatewayv1
 			// If any listener failed, the Gateway as a whole is not fully programmed.
 			programmedCondition.Status = metav1.ConditionFalse
 			programmedCondition.Reason = "ListenersNotProgrammed"
-			programmedCondition.Message = fmt.Sprintf("%d out of %d listeners failed to be programmed", listenersProgrammed, len(listenerStatuses))
+			programmedCondition.Message = fmt.Sprintf("%d out of %d listeners failed to be programmed", len(listenerStatuses)-listenersProgrammed, len(listenerStatuses))
 		}
 	}
 	meta.SetStatusCondition(&newGw.Status.Conditions, programmedCondition)
@@ -705,3 +1941,60 @@ func setGatewayConditions(newGw *gatewayv1.Gateway, listenerStatuses []gatewayv1
 		ObservedGeneration: newGw.Generation,
 	})
 }
+
+// updateGatewayStatus patches the status of the Gateway with the Gateway-level conditions
+// and the listener statuses computed during translation. No request is sent if the
+// status is already up to date.
+func (t *Translator) updateGatewayStatus(ctx context.Context, gw *gatewayv1.Gateway, listenerStatuses []gatewayv1.ListenerStatus, translateErr error) error {
+	patch, err := gatewayStatusPatch(gw, listenerStatuses, translateErr)
+	if err != nil || patch == nil {
+		return err
+	}
+	_, err = t.gwClient.GatewayV1().Gateways(gw.Namespace).Patch(ctx, gw.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// gatewayStatusPatch returns the merge patch that sets the desired status on the Gateway,
+// or nil if the current status already matches it. Existing conditions are updated in place
+// so that their LastTransitionTime only changes when their status does.
+func gatewayStatusPatch(gw *gatewayv1.Gateway, listenerStatuses []gatewayv1.ListenerStatus, translateErr error) ([]byte, error) {
+	newGw := gw.DeepCopy()
+	setGatewayConditions(newGw, listenerStatuses, translateErr)
+
+	existingListeners := make(map[gatewayv1.SectionName]gatewayv1.ListenerStatus, len(gw.Status.Listeners))
+	for _, listenerStatus := range gw.Status.Listeners {
+		existingListeners[listenerStatus.Name] = listenerStatus
+	}
+	newGw.Status.Listeners = make([]gatewayv1.ListenerStatus, 0, len(listenerStatuses))
+	for _, desired := range listenerStatuses {
+		existing := existingListeners[desired.Name]
+		listenerStatus := *existing.DeepCopy()
+		listenerStatus.Name = desired.Name
+		listenerStatus.SupportedKinds = desired.SupportedKinds
+		listenerStatus.AttachedRoutes = desired.AttachedRoutes
+
+		desiredTypes := sets.New[string]()
+		for _, condition := range desired.Conditions {
+			// Conditions computed during listener validation don't carry a generation.
+			condition.ObservedGeneration = gw.Generation
+			meta.SetStatusCondition(&listenerStatus.Conditions, condition)
+			desiredTypes.Insert(condition.Type)
+		}
+		for _, condition := range slices.Clone(listenerStatus.Conditions) {
+			if !desiredTypes.Has(condition.Type) {
+				meta.RemoveStatusCondition(&listenerStatus.Conditions, condition.Type)
+			}
+		}
+		newGw.Status.Listeners = append(newGw.Status.Listeners, listenerStatus)
+	}
+
+	if reflect.DeepEqual(gw.Status, newGw.Status) {
+		return nil, nil
+	}
+	return json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": newGw.Status.Conditions,
+			"listeners":  newGw.Status.Listeners,
+		},
+	})
+}
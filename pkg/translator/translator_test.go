@@ -0,0 +1,214 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTranslateGatewaysToXDS(t *testing.T) {
+	gwA := testGateway("gw-a", httpListener("http", 80))
+	gwB := testGateway("gw-b", httpListener("http", 8080))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gwA, gwB,
+		testService("backend", 80), testEndpointSlice("backend-1", "backend", 80, "10.1.0.1"),
+		testHTTPRoute("route-a", "gw-a", testBackendRef("backend", 80)),
+		testHTTPRoute("route-b", "gw-b", testBackendRef("backend", 80)))
+
+	resources, err := tl.TranslateGatewaysToXDS(context.Background(), []*gatewayv1.Gateway{gwA, gwB})
+	if err != nil {
+		t.Fatalf("TranslateGatewaysToXDS() error = %v", err)
+	}
+	if names := resourceNames(resources, resourcev3.ClusterType); !slices.Equal(names, []string{clusterName("backend", 80)}) {
+		t.Errorf("clusters = %v, want only %s", names, clusterName("backend", 80))
+	}
+	listeners := resourceNames(resources, resourcev3.ListenerType)
+	sort.Strings(listeners)
+	if want := []string{"listener-80", "listener-8080"}; !slices.Equal(listeners, want) {
+		t.Errorf("listeners = %v, want %v", listeners, want)
+	}
+}
+
+func TestTranslateGatewaysToXDSPortConflict(t *testing.T) {
+	gwA := testGateway("gw-a", httpListener("http", 80))
+	gwB := testGateway("gw-b", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gwA, gwB)
+
+	resources, err := tl.TranslateGatewaysToXDS(context.Background(), []*gatewayv1.Gateway{gwA, gwB})
+	if err == nil || !strings.Contains(err.Error(), "listener-80 of Gateway default/gw-b conflicts with Gateway default/gw-a") {
+		t.Errorf("TranslateGatewaysToXDS() error = %v, want a conflict on listener-80", err)
+	}
+	if listeners := resourceNames(resources, resourcev3.ListenerType); len(listeners) != 1 {
+		t.Errorf("listeners = %v, want only the listener of gw-a", listeners)
+	}
+}
+
+func TestTranslateGatewaysToXDSForeignGatewayClass(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	foreignClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.com/other-controller"},
+	}
+	ours := testGateway("ours", httpListener("http", 80))
+	foreign := testGateway("foreign", httpListener("http", 8080))
+	foreign.Spec.GatewayClassName = "other"
+	tl := newTestTranslator(t, Options{Logger: logger}, testGatewayClass(), foreignClass, ours, foreign,
+		testService("backend", 80),
+		testHTTPRoute("route-ours", "ours", testBackendRef("backend", 80)),
+		testHTTPRoute("route-foreign", "foreign", testBackendRef("backend", 80)))
+
+	resources, err := tl.TranslateGatewaysToXDS(context.Background(), []*gatewayv1.Gateway{ours, foreign})
+	if err != nil {
+		t.Fatalf("TranslateGatewaysToXDS() error = %v", err)
+	}
+	if listeners := resourceNames(resources, resourcev3.ListenerType); !slices.Equal(listeners, []string{"listener-80"}) {
+		t.Errorf("listeners = %v, want only the listener of Gateway ours", listeners)
+	}
+
+	var skipped []map[string]any
+	for _, record := range logRecords(t, &buf) {
+		if record["msg"] == "skipping Gateway" {
+			skipped = append(skipped, record)
+		}
+	}
+	if len(skipped) != 1 || skipped[0]["name"] != "foreign" ||
+		!strings.Contains(skipped[0]["reason"].(string), "managed by controller example.com/other-controller") {
+		t.Errorf("skipped Gateways = %v, want Gateway foreign with the controller of its class", skipped)
+	}
+}
+
+// logRecords decodes the records written by a slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		record := make(map[string]any)
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestTranslateLogsMissingBackend(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	gw := testGateway("gw", httpListener("http", 80))
+	tl := newTestTranslator(t, Options{Logger: logger},
+		testGatewayClass(), gw,
+		testHTTPRoute("web", "gw", testBackendRef("missing", 80)),
+	)
+	translateGateway(t, tl, gw)
+
+	var warning, debug map[string]any
+	for _, record := range logRecords(t, &buf) {
+		switch record["msg"] {
+		case "route has unresolved references":
+			warning = record
+		case "translated Gateway":
+			debug = record
+		}
+	}
+
+	if warning == nil {
+		t.Fatalf("no warning was logged for the missing backend:\n%s", buf.String())
+	}
+	if warning["level"] != slog.LevelWarn.String() {
+		t.Errorf("level = %v, want %v", warning["level"], slog.LevelWarn)
+	}
+	for key, want := range map[string]string{
+		"kind":      "HTTPRoute",
+		"namespace": testNamespace,
+		"name":      "web",
+		"reason":    string(gatewayv1.RouteReasonBackendNotFound),
+	} {
+		if warning[key] != want {
+			t.Errorf("%s = %v, want %q", key, warning[key], want)
+		}
+	}
+
+	// The route statuses are logged as structured attributes keyed by route.
+	if debug == nil {
+		t.Fatalf("the translated Gateway was not logged:\n%s", buf.String())
+	}
+	routeStatuses, _ := debug["routeStatuses"].(map[string]any)
+	httpRoutes, _ := routeStatuses["HTTPRoutes"].(map[string]any)
+	if _, ok := httpRoutes[testNamespace+"/web"]; !ok {
+		t.Errorf("routeStatuses = %v, want the status of %s/web", debug["routeStatuses"], testNamespace)
+	}
+}
+
+// TestTranslatePartialFailure checks that an invalid HTTPRoute is reported without dropping
+// the resources of the other routes of the Gateway.
+func TestTranslatePartialFailure(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	broken := testHTTPRoute("broken", "gw", testBackendRef("legacy", 80))
+	broken.Spec.Rules[0].Matches = []gatewayv1.HTTPRouteMatch{pathMatch(gatewayv1.PathMatchRegularExpression, `/(a)\1`)}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend", 80), testService("legacy", 80),
+		testHTTPRoute("web", "gw", testBackendRef("backend", 80)), broken)
+
+	resources, err := tl.TranslateGatewaysToXDS(context.Background(), []*gatewayv1.Gateway{gw})
+	if err == nil || !strings.Contains(err.Error(), "HTTPRoute default/broken") {
+		t.Fatalf("TranslateGatewaysToXDS() error = %v, want an error of HTTPRoute default/broken", err)
+	}
+	if strings.Contains(err.Error(), "HTTPRoute default/web") {
+		t.Errorf("TranslateGatewaysToXDS() error = %v, want no error of HTTPRoute default/web", err)
+	}
+
+	vh := findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*")
+	if got, want := routeNames(vh), []string{"default-web-rule0-match0", "gw-vh-80-*-not-found"}; !slices.Equal(got, want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+	findCluster(t, resources, clusterName("backend", 80))
+}
+
+// TestTranslateDeduplicatesClusters checks that routes forwarding, splitting and mirroring to
+// the same backends share their clusters.
+func TestTranslateDeduplicatesClusters(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	mirrored := testHTTPRoute("mirrored", "gw", testBackendRef("backend", 80))
+	mirrored.Spec.Hostnames = []gatewayv1.Hostname{"mirrored.example.com"}
+	mirrored.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+		Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: testBackendRef("shadow", 80).BackendObjectReference},
+	}}
+	split := testHTTPRoute("split", "gw", testBackendRef("backend", 80), testBackendRef("shadow", 80))
+	split.Spec.Hostnames = []gatewayv1.Hostname{"split.example.com"}
+	split.Spec.Rules[0].BackendRefs[0].Weight = ptr(int32(90))
+	split.Spec.Rules[0].BackendRefs[1].Weight = ptr(int32(10))
+	plain := testHTTPRoute("plain", "gw", testBackendRef("backend", 80))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw,
+		testService("backend", 80), testEndpointSlice("backend-1", "backend", 80, "10.1.0.1"),
+		testService("shadow", 80), testEndpointSlice("shadow-1", "shadow", 80, "10.1.0.2"),
+		mirrored, split, plain)
+	resources := translateGateway(t, tl, gw)
+
+	want := []string{clusterName("backend", 80), clusterName("shadow", 80)}
+	for _, typeURL := range []resourcev3.Type{resourcev3.ClusterType, resourcev3.EndpointType} {
+		names := resourceNames(resources, typeURL)
+		sort.Strings(names)
+		if !slices.Equal(names, want) {
+			t.Errorf("%s names = %v, want %v", typeURL, names, want)
+		}
+	}
+	routeConfiguration := findRouteConfiguration(t, resources, "route-80")
+	mirrorPolicies := findRoute(t, findVirtualHost(t, routeConfiguration, "mirrored.example.com"), "default-mirrored-rule0-match0").GetRoute().GetRequestMirrorPolicies()
+	if len(mirrorPolicies) != 1 || mirrorPolicies[0].Cluster != clusterName("shadow", 80) {
+		t.Errorf("mirror policies = %v, want one to %s", mirrorPolicies, clusterName("shadow", 80))
+	}
+	weightedClusters := findRoute(t, findVirtualHost(t, routeConfiguration, "split.example.com"), "default-split-rule0-match0").GetRoute().GetWeightedClusters().GetClusters()
+	if len(weightedClusters) != 2 {
+		t.Errorf("weighted clusters = %v, want backend and shadow", weightedClusters)
+	}
+}
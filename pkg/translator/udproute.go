@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	udpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewaylistersv1beta1 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
+)
+
+const udpProxyListenerFilterName = "envoy.filters.udp_listener.udp_proxy"
+
+// translateUDPRoute translates a UDPRoute into an Envoy UdpProxy config.
+// Only a single backend is supported because udp_proxy has no clean equivalent
+// of weighted clusters, so routes referencing more than one backend are rejected.
+func translateUDPRoute(
+	udpRoute *gatewayv1alpha2.UDPRoute,
+	statPrefix string,
+	serviceLister corev1listers.ServiceLister,
+	referenceGrantLister gatewaylistersv1beta1.ReferenceGrantLister,
+) (*udpproxyv3.UdpProxyConfig, []gatewayv1.BackendRef, metav1.Condition) {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range udpRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	if len(backendRefs) > 1 {
+		msg := fmt.Sprintf("UDPRoute supports a single backendRef, but %d were specified", len(backendRefs))
+		return nil, nil, createFailureCondition(gatewayv1.RouteReasonUnsupportedValue, msg, udpRoute.Generation)
+	}
+
+	routeAction, validBackendRefs, err := buildHTTPRouteAction(
+		"UDPRoute",
+		udpRoute.Namespace,
+		backendRefs,
+		clusterVariant{},
+		serviceLister,
+		referenceGrantLister,
+	)
+	condition := createSuccessCondition(udpRoute.Generation)
+	var controllerErr *ControllerError
+	if errors.As(err, &controllerErr) {
+		condition = createFailureCondition(gatewayv1.RouteConditionReason(controllerErr.Reason), controllerErr.Message, udpRoute.Generation)
+	}
+	if errors.Is(err, errAllBackendsZeroWeight) {
+		// No datagrams may be forwarded, so no proxy is configured for the route.
+		return nil, validBackendRefs, condition
+	}
+	if routeAction == nil {
+		return nil, nil, condition
+	}
+
+	udpProxy := &udpproxyv3.UdpProxyConfig{
+		StatPrefix: statPrefix,
+		RouteSpecifier: &udpproxyv3.UdpProxyConfig_Cluster{
+			Cluster: routeAction.GetCluster(),
+		},
+	}
+	return udpProxy, validBackendRefs, condition
+}
+
+// buildUDPListener builds a UDP listener for the given port. Envoy UDP listeners have
+// no filter chains; the udp_proxy is configured as a listener filter instead.
+func buildUDPListener(port uint32, udpProxy *udpproxyv3.UdpProxyConfig) (*listenerv3.Listener, error) {
+	udpProxyAny, err := anypb.New(udpProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	address := createEnvoyAddress(port)
+	address.GetSocketAddress().Protocol = corev3.SocketAddress_UDP
+
+	return &listenerv3.Listener{
+		// UDP listeners may share a port number with TCP listeners, so they need a distinct name.
+		Name:              fmt.Sprintf("listener-udp-%d", port),
+		Address:           address,
+		UdpListenerConfig: &listenerv3.UdpListenerConfig{},
+		// reuse_port lets Envoy spread datagrams across worker threads.
+		EnableReusePort: wrapperspb.Bool(true),
+		ListenerFilters: []*listenerv3.ListenerFilter{{
+			Name: udpProxyListenerFilterName,
+			ConfigType: &listenerv3.ListenerFilter_TypedConfig{
+				TypedConfig: udpProxyAny,
+			},
+		}},
+	}, nil
+}
@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"testing"
+
+	udpproxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func udpListener(name string, port gatewayv1.PortNumber) gatewayv1.Listener {
+	return gatewayv1.Listener{Name: gatewayv1.SectionName(name), Port: port, Protocol: gatewayv1.UDPProtocolType}
+}
+
+func TestTranslateUDPRoute(t *testing.T) {
+	gw := testGateway("gw", udpListener("dns", 53))
+	route := testUDPRoute("dns", "gw", testBackendRef("coredns", 53))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("coredns", 53), route)
+	resources := translateGateway(t, tl, gw)
+
+	listener := findListener(t, resources, "listener-udp-53")
+	if !listener.GetEnableReusePort().GetValue() {
+		t.Errorf("listener enable_reuse_port = %v, want true", listener.GetEnableReusePort())
+	}
+	if listener.UdpListenerConfig == nil {
+		t.Errorf("listener has no udp_listener_config")
+	}
+	if len(listener.ListenerFilters) != 1 || listener.ListenerFilters[0].Name != udpProxyListenerFilterName {
+		t.Fatalf("listener filters = %v, want %s", listener.ListenerFilters, udpProxyListenerFilterName)
+	}
+	udpProxy := &udpproxyv3.UdpProxyConfig{}
+	if err := listener.ListenerFilters[0].GetTypedConfig().UnmarshalTo(udpProxy); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := udpProxy.GetCluster(), clusterName("coredns", 53); got != want {
+		t.Errorf("UDP proxy cluster = %q, want %q", got, want)
+	}
+	findCluster(t, resources, clusterName("coredns", 53))
+}
+
+func TestTranslateUDPRouteMultipleBackends(t *testing.T) {
+	gw := testGateway("gw", udpListener("dns", 53))
+	route := testUDPRoute("dns", "gw", testBackendRef("coredns", 53), testBackendRef("kube-dns", 53))
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("coredns", 53), testService("kube-dns", 53), route)
+	resources, _, routeStatuses, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+	if listener := findResource(resources, resourcev3.ListenerType, "listener-udp-53"); listener != nil {
+		t.Errorf("got listener %v for a UDPRoute with several backends, want none", listener)
+	}
+	parents := routeStatuses.UDPRoutes[types.NamespacedName{Namespace: testNamespace, Name: "dns"}]
+	if len(parents) != 1 {
+		t.Fatalf("got %d parent statuses, want 1", len(parents))
+	}
+	condition := meta.FindStatusCondition(parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != string(gatewayv1.RouteReasonUnsupportedValue) {
+		t.Errorf("ResolvedRefs = %v, want False/%s", condition, gatewayv1.RouteReasonUnsupportedValue)
+	}
+}
@@ -0,0 +1,62 @@
+package translator
+
+import (
+	"fmt"
+	"strconv"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const webSocketUpgradeType = "websocket"
+
+// webSocketAnnotation is the annotation of an HTTPRoute that lets the requests of its routes
+// upgrade their connections to WebSockets.
+const webSocketAnnotation = "gateway.xds/websocket"
+
+// parseWebSocketAnnotations returns the upgrade configs that enable WebSockets on the routes
+// of an HTTPRoute, or nil if the routes keep the default of the HTTP connection manager.
+func parseWebSocketAnnotations(annotations map[string]string) ([]*routev3.RouteAction_UpgradeConfig, error) {
+	value, ok := annotations[webSocketAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", webSocketAnnotation, err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+	return []*routev3.RouteAction_UpgradeConfig{{
+		UpgradeType: webSocketUpgradeType,
+		Enabled:     wrapperspb.Bool(true),
+	}}, nil
+}
+
+// routesUseWebSockets returns whether any route of the virtual hosts enables WebSockets.
+func routesUseWebSockets(virtualHosts []*routev3.VirtualHost) bool {
+	for _, vh := range virtualHosts {
+		for _, route := range vh.Routes {
+			for _, upgradeConfig := range route.GetRoute().GetUpgradeConfigs() {
+				if upgradeConfig.UpgradeType == webSocketUpgradeType {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// buildWebSocketUpgradeConfigs builds the upgrade configs of an HTTP connection manager that
+// accepts WebSockets on all routes if enabled, and otherwise only on the routes that enable
+// them, as Envoy ignores the upgrade configs of routes for upgrade types the connection
+// manager doesn't list. Only HTTP/1.1 requests upgrade their connections, so HTTP/2
+// requests are not affected.
+func buildWebSocketUpgradeConfigs(enabled bool) []*hcm.HttpConnectionManager_UpgradeConfig {
+	return []*hcm.HttpConnectionManager_UpgradeConfig{{
+		UpgradeType: webSocketUpgradeType,
+		Enabled:     wrapperspb.Bool(enabled),
+	}}
+}
@@ -0,0 +1,108 @@
+package translator
+
+import (
+	"slices"
+	"testing"
+
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+)
+
+func TestTranslateHTTPRouteWebSocketAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		options     Options
+		// wantRoute is whether the route enables WebSockets itself.
+		wantRoute bool
+		// wantHCM is the upgrade config of the connection manager, nil if it has none.
+		wantHCM *bool
+	}{
+		{name: "no annotation"},
+		{name: "annotation off", annotations: map[string]string{webSocketAnnotation: "false"}},
+		{
+			name:        "annotation on",
+			annotations: map[string]string{webSocketAnnotation: "true"},
+			wantRoute:   true,
+			wantHCM:     ptr(false),
+		},
+		{name: "enabled for all routes", options: Options{WebSockets: true}, wantHCM: ptr(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := testGateway("gw", httpListener("http", 80))
+			route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+			route.Annotations = tt.annotations
+			tl := newTestTranslator(t, tt.options, testGatewayClass(), gw, testService("backend", 80), route)
+			resources := translateGateway(t, tl, gw)
+
+			envoyRoute := findRoute(t, findVirtualHost(t, findRouteConfiguration(t, resources, "route-80"), "*"), "default-web-rule0-match0")
+			upgradeConfigs := envoyRoute.GetRoute().UpgradeConfigs
+			if tt.wantRoute {
+				if len(upgradeConfigs) != 1 || upgradeConfigs[0].UpgradeType != "websocket" || !upgradeConfigs[0].Enabled.GetValue() {
+					t.Errorf("route upgrade configs = %v, want websocket enabled", upgradeConfigs)
+				}
+			} else if len(upgradeConfigs) != 0 {
+				t.Errorf("route upgrade configs = %v, want none", upgradeConfigs)
+			}
+
+			hcmUpgradeConfigs := listenerHCM(t, findListener(t, resources, "listener-80")).UpgradeConfigs
+			if tt.wantHCM == nil {
+				if len(hcmUpgradeConfigs) != 0 {
+					t.Errorf("HCM upgrade configs = %v, want none", hcmUpgradeConfigs)
+				}
+				return
+			}
+			if len(hcmUpgradeConfigs) != 1 || hcmUpgradeConfigs[0].UpgradeType != "websocket" || hcmUpgradeConfigs[0].Enabled.GetValue() != *tt.wantHCM {
+				t.Errorf("HCM upgrade configs = %v, want websocket enabled %v", hcmUpgradeConfigs, *tt.wantHCM)
+			}
+		})
+	}
+}
+
+func TestTranslateHTTPRouteInvalidWebSocketAnnotation(t *testing.T) {
+	gw := testGateway("gw", httpListener("http", 80))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Annotations = map[string]string{webSocketAnnotation: "sometimes"}
+	tl := newTestTranslator(t, Options{}, testGatewayClass(), gw, testService("backend", 80), route)
+	resources, _, _, _ := tl.buildEnvoyResourcesForGateway(gw)
+
+	for _, vh := range findRouteConfiguration(t, resources, "route-80").VirtualHosts {
+		for _, envoyRoute := range vh.Routes {
+			if envoyRoute.GetRoute().GetCluster() == clusterName("backend", 80) {
+				t.Errorf("route %s of the rejected HTTPRoute was translated", envoyRoute.Name)
+			}
+		}
+	}
+}
+
+// TestTranslateWebSocketsHTTP2Listener checks that enabling WebSockets leaves HTTP/2 alone: the
+// listener still negotiates h2, and the connection manager doesn't accept extended CONNECT,
+// so only HTTP/1.1 requests upgrade their connections.
+func TestTranslateWebSocketsHTTP2Listener(t *testing.T) {
+	gw := testGateway("gw", httpsListener("https", 443, "web-cert"))
+	route := testHTTPRoute("web", "gw", testBackendRef("backend", 80))
+	route.Annotations = map[string]string{webSocketAnnotation: "true"}
+	tl := newTestTranslator(t, Options{WebSockets: true}, testGatewayClass(), gw, testTLSSecret("web-cert"), testService("backend", 80), route)
+	resources := translateGateway(t, tl, gw)
+
+	listener := findListener(t, resources, "listener-443")
+	tlsContext := &tlsv3.DownstreamTlsContext{}
+	if err := listener.FilterChains[0].GetTransportSocket().GetTypedConfig().UnmarshalTo(tlsContext); err != nil {
+		t.Fatal(err)
+	}
+	if alpn := tlsContext.CommonTlsContext.AlpnProtocols; !slices.Contains(alpn, "h2") {
+		t.Errorf("ALPN protocols = %v, want h2", alpn)
+	}
+
+	manager := filterChainHCM(t, listener.FilterChains[0])
+	if len(manager.UpgradeConfigs) != 1 || manager.UpgradeConfigs[0].UpgradeType != "websocket" {
+		t.Errorf("HCM upgrade configs = %v, want websocket", manager.UpgradeConfigs)
+	}
+	if manager.CodecType != hcm.HttpConnectionManager_AUTO {
+		t.Errorf("HCM codec = %v, want %v", manager.CodecType, hcm.HttpConnectionManager_AUTO)
+	}
+	if manager.GetHttp2ProtocolOptions().GetAllowConnect() {
+		t.Error("HCM accepts extended CONNECT, want HTTP/2 requests unaffected")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	endpointservice "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
+	listenerservice "github.com/envoyproxy/go-control-plane/envoy/service/listener/v3"
+	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
+	secretservice "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"google.golang.org/grpc"
+)
+
+// newSnapshotCache creates an ADS snapshot cache holding the given snapshots, keyed by
+// node ID.
+func newSnapshotCache(ctx context.Context, snapshots map[string]*cache.Snapshot) (cache.SnapshotCache, error) {
+	snapshotCache := cache.NewSnapshotCache(true, cache.IDHash{}, nil)
+	for nodeID, snapshot := range snapshots {
+		if err := snapshotCache.SetSnapshot(ctx, nodeID, snapshot); err != nil {
+			return nil, fmt.Errorf("failed to set snapshot for node %s: %w", nodeID, err)
+		}
+	}
+	return snapshotCache, nil
+}
+
+// serveXDS serves the snapshot cache to connecting Envoys on listenAddr until ctx is
+// cancelled, at which point in-flight streams are drained before returning. Both the
+// state-of-the-world and the incremental (delta) variants of the protocols are served, so
+// Envoys configured for delta xDS only receive the resources that changed.
+func serveXDS(ctx context.Context, listenAddr string, snapshotCache cache.SnapshotCache) error {
+	lis, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	xdsServer := serverv3.NewServer(ctx, snapshotCache, nil)
+
+	discoverygrpc.RegisterAggregatedDiscoveryServiceServer(grpcServer, xdsServer)
+	listenerservice.RegisterListenerDiscoveryServiceServer(grpcServer, xdsServer)
+	routeservice.RegisterRouteDiscoveryServiceServer(grpcServer, xdsServer)
+	clusterservice.RegisterClusterDiscoveryServiceServer(grpcServer, xdsServer)
+	endpointservice.RegisterEndpointDiscoveryServiceServer(grpcServer, xdsServer)
+	secretservice.RegisterSecretDiscoveryServiceServer(grpcServer, xdsServer)
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("shutting down xDS server")
+		grpcServer.GracefulStop()
+	}()
+
+	slog.Info("serving xDS", "address", lis.Addr().String())
+	if err := grpcServer.Serve(lis); err != nil {
+		return fmt.Errorf("xDS server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// freeAddress returns a local address that nothing listens on.
+func freeAddress(t testing.TB) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+func TestServeXDS(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	snapshot := testSnapshot(t)
+	snapshotCache, err := newSnapshotCache(ctx, map[string]*cache.Snapshot{"test-node": snapshot})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serveCtx, stop := context.WithCancel(ctx)
+	addr := freeAddress(t)
+	served := make(chan error, 1)
+	go func() { served <- serveXDS(serveCtx, addr, snapshotCache) }()
+
+	// A fake Envoy fetches the clusters of its node over ADS.
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	stream, err := discoverygrpc.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&discoverygrpc.DiscoveryRequest{
+		Node:    &corev3.Node{Id: "test-node"},
+		TypeUrl: resourcev3.ClusterType,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := snapshot.GetVersion(resourcev3.ClusterType); response.VersionInfo != want {
+		t.Errorf("version = %q, want %q", response.VersionInfo, want)
+	}
+	if len(response.Resources) != 1 {
+		t.Fatalf("got %d clusters, want 1", len(response.Resources))
+	}
+	cluster := &clusterv3.Cluster{}
+	if err := response.Resources[0].UnmarshalTo(cluster); err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Name != testClusterName {
+		t.Errorf("cluster = %q, want %q", cluster.Name, testClusterName)
+	}
+
+	// Cancelling the context shuts the server down once the open stream is closed.
+	stop()
+	stream.CloseSend()
+	conn.Close()
+	if err := <-served; err != nil {
+		t.Errorf("serveXDS() error = %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+)
+
+// validationProblem is a problem found in the translated resources in --validate-only mode.
+type validationProblem struct {
+	// Type is the xDS type URL of the invalid resource, empty for snapshot-wide problems.
+	Type string `json:"type,omitempty"`
+	// Name is the name of the invalid resource, empty for snapshot-wide problems.
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// validationReport is the result of validating the translated resources.
+type validationReport struct {
+	Valid    bool                `json:"valid"`
+	Problems []validationProblem `json:"problems,omitempty"`
+}
+
+// validateResources checks every resource against the validation rules of its proto
+// definition, and the snapshot built from the resources for consistency, e.g. that every
+// route config referenced by a listener exists.
+func validateResources(resources map[resourcev3.Type][]envoyproxytypes.Resource, snapshot *cache.Snapshot) validationReport {
+	var problems []validationProblem
+	for typeURL, typeResources := range resources {
+		for _, res := range typeResources {
+			validator, ok := res.(interface{ ValidateAll() error })
+			if !ok {
+				continue
+			}
+			if err := validator.ValidateAll(); err != nil {
+				problems = append(problems, validationProblem{
+					Type:  typeURL,
+					Name:  cache.GetResourceName(res),
+					Error: err.Error(),
+				})
+			}
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Type != problems[j].Type {
+			return problems[i].Type < problems[j].Type
+		}
+		return problems[i].Name < problems[j].Name
+	})
+	if err := snapshot.Consistent(); err != nil {
+		problems = append(problems, validationProblem{Error: err.Error()})
+	}
+	return validationReport{
+		Valid:    len(problems) == 0,
+		Problems: problems,
+	}
+}
+
+// marshalValidationReport serializes the validation report as indented JSON.
+func marshalValidationReport(report validationReport) ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+)
+
+func TestValidateResources(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(resources map[resourcev3.Type][]envoyproxytypes.Resource)
+		want   []validationProblem
+	}{
+		{name: "valid", modify: func(map[resourcev3.Type][]envoyproxytypes.Resource) {}},
+		{
+			name: "invalid route match",
+			modify: func(resources map[resourcev3.Type][]envoyproxytypes.Resource) {
+				route := resources[resourcev3.RouteType][0].(*routev3.RouteConfiguration).VirtualHosts[0].Routes[0]
+				route.Match.Headers = []*routev3.HeaderMatcher{{Name: ""}}
+			},
+			want: []validationProblem{{Type: resourcev3.RouteType, Name: "route-443", Error: "Name"}},
+		},
+		{
+			name: "inconsistent snapshot",
+			modify: func(resources map[resourcev3.Type][]envoyproxytypes.Resource) {
+				delete(resources, resourcev3.RouteType)
+			},
+			want: []validationProblem{{Error: "route-443"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := testResources(t)
+			tc.modify(resources)
+			snapshot, err := generateXDS(resources, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report := validateResources(resources, snapshot)
+			if report.Valid != (len(tc.want) == 0) || len(report.Problems) != len(tc.want) {
+				t.Fatalf("validateResources() = %+v, want %d problems", report, len(tc.want))
+			}
+			// The error of a problem is only checked for the field or resource it names.
+			for i, want := range tc.want {
+				got := report.Problems[i]
+				if got.Type != want.Type || got.Name != want.Name || !strings.Contains(got.Error, want.Error) {
+					t.Errorf("problem %d = %+v, want %+v", i, got, want)
+				}
+			}
+
+			reportOutput, err := marshalValidationReport(report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded validationReport
+			if err := json.Unmarshal(reportOutput, &decoded); err != nil {
+				t.Fatalf("report %s is not valid JSON: %v", reportOutput, err)
+			}
+			if decoded.Valid != report.Valid || len(decoded.Problems) != len(report.Problems) {
+				t.Errorf("decoded report = %+v, want %+v", decoded, report)
+			}
+		})
+	}
+}
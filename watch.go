@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scache "k8s.io/client-go/tools/cache"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+// registerEventHandlers records every change to the given informers' objects in changes and
+// signals trigger. It must be called before the informer factories are started.
+func registerEventHandlers(trigger chan<- struct{}, changes *changeSet, informers ...k8scache.SharedIndexInformer) error {
+	handler := k8scache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			changes.record(obj)
+			notify(trigger)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Periodic resyncs deliver updates for unchanged objects, skip those.
+			oldMeta, oldErr := metaAccessor(oldObj)
+			newMeta, newErr := metaAccessor(newObj)
+			if oldErr == nil && newErr == nil && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+				return
+			}
+			// Both versions are recorded, as a route may have been detached from a Gateway.
+			changes.record(oldObj)
+			changes.record(newObj)
+			notify(trigger)
+		},
+		DeleteFunc: func(obj interface{}) {
+			changes.record(obj)
+			notify(trigger)
+		},
+	}
+	for _, informer := range informers {
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func metaAccessor(obj interface{}) (metav1.Object, error) {
+	if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	m, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("object %T has no metadata", obj)
+	}
+	return m, nil
+}
+
+// notify signals trigger without blocking. A pending signal already covers this event.
+func notify(trigger chan<- struct{}) {
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+}
+
+// debounceMaxWindows bounds, in debounce windows, how long a steady stream of signals can
+// postpone the call of a debounced function.
+const debounceMaxWindows = 10
+
+// debounce calls fn once no signal has arrived on trigger for the given window,
+// so that a burst of events results in a single call. Signals arriving closer together than
+// the window can't postpone the call forever: fn is called at the latest debounceMaxWindows
+// windows after the first signal since the last call. It returns when ctx is cancelled.
+func debounce(ctx context.Context, trigger <-chan struct{}, window time.Duration, fn func()) {
+	timer := time.NewTimer(window)
+	timer.Stop()
+	// deadline is when fn must be called for the pending signals, zero if there are none.
+	var deadline time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-trigger:
+			now := time.Now()
+			if deadline.IsZero() {
+				deadline = now.Add(debounceMaxWindows * window)
+			}
+			timer.Reset(min(window, deadline.Sub(now)))
+		case <-timer.C:
+			deadline = time.Time{}
+			fn()
+		}
+	}
+}
+
+// reconciler re-translates the Gateways and pushes the result to the snapshot cache.
+type reconciler struct {
+	translator    *translator.Translator
+	gatewayLister gatewaylisters.GatewayLister
+	namespace     string
+	// name is the name of the translated Gateway, all Gateways in namespace are translated if empty.
+	name   string
+	nodeID string
+	// nodePerGateway pushes the resources of every Gateway to a node ID of its own instead of
+	// merging them into the snapshot of nodeID.
+	nodePerGateway bool
+	snapshotCache  cache.SnapshotCache
+	// timestampVersions versions the snapshots by time instead of by content.
+	timestampVersions bool
+	// resourcesByNode are the resources of the last snapshots pushed to the cache, keyed by
+	// node ID.
+	resourcesByNode map[string]map[resourcev3.Type][]envoyproxytypes.Resource
+	// bestEffort pushes the resources that could be translated despite translation errors.
+	bestEffort bool
+	// metrics records the translations and snapshots, nothing is recorded if it is nil.
+	metrics *metrics
+	// changes are the objects changed since the last reconcile. Every Gateway is translated
+	// again if it is nil.
+	changes *changeSet
+	// gatewayStates are the last successful translations of the Gateways, which are reused
+	// until a change affects them.
+	gatewayStates map[types.NamespacedName]*gatewayState
+}
+
+// gatewayState is the last successful translation of a Gateway and what it depends on.
+type gatewayState struct {
+	resources    map[resourcev3.Type][]envoyproxytypes.Resource
+	dependencies translator.Dependencies
+}
+
+// reconcile translates the Gateways affected by the changes since the last reconcile again,
+// and only sets a new snapshot for the nodes whose resources differ from their last snapshot.
+// The snapshots of nodes whose Gateway is gone are cleared.
+func (r *reconciler) reconcile(ctx context.Context) {
+	changes := newChangeSet()
+	changes.all = true
+	if r.changes != nil {
+		changes = r.changes.take()
+	}
+
+	gateways, err := getGateways(r.gatewayLister, r.namespace, r.name)
+	if err != nil {
+		slog.Error("failed to fetch Gateways", "err", err)
+		return
+	}
+
+	start := time.Now()
+	resourcesByGateway := make([]map[resourcev3.Type][]envoyproxytypes.Resource, len(gateways))
+	gatewayStates := make(map[types.NamespacedName]*gatewayState, len(gateways))
+	var errs []error
+	for i, gw := range gateways {
+		key := types.NamespacedName{Namespace: gw.Namespace, Name: gw.Name}
+		if state, ok := r.gatewayStates[key]; ok && !changes.affects(key, state.dependencies) {
+			resourcesByGateway[i] = state.resources
+			gatewayStates[key] = state
+			continue
+		}
+		resources, err := r.translator.TranslateManagedGatewayToXDS(ctx, gw)
+		if err != nil {
+			// Gateways that failed to translate are translated again on the next reconcile.
+			errs = append(errs, err)
+		} else {
+			gatewayStates[key] = &gatewayState{resources: resources, dependencies: r.translator.GatewayDependencies(gw)}
+		}
+		resourcesByGateway[i] = resources
+	}
+	r.gatewayStates = gatewayStates
+	resourcesByNode, err := mergeNodes(gateways, resourcesByGateway, r.nodeID, r.nodePerGateway)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	err = errors.Join(errs...)
+	r.metrics.observeTranslation(time.Since(start), err)
+	if err != nil {
+		if !r.bestEffort {
+			logErrors("failed to translate Gateways to XDS", err)
+			return
+		}
+		logErrors("skipped resources that failed to translate", err)
+	}
+
+	for node, resources := range resourcesByNode {
+		if previous, ok := r.resourcesByNode[node]; ok && resourcesEqual(previous, resources) {
+			continue
+		}
+		snapshot, err := generateXDS(resources, r.timestampVersions)
+		if err != nil {
+			slog.Error("failed to generate XDS", "node", node, "err", err)
+			continue
+		}
+		if err := snapshot.Consistent(); err != nil {
+			slog.Error("snapshot is inconsistent", "node", node, "err", err)
+			continue
+		}
+		if err := r.snapshotCache.SetSnapshot(ctx, node, snapshot); err != nil {
+			slog.Error("failed to set snapshot", "node", node, "err", err)
+			continue
+		}
+		r.resourcesByNode[node] = resources
+		slog.Info("pushed snapshot", "node", node, "version", snapshot.GetVersion(resourcev3.ListenerType))
+	}
+	for node := range r.resourcesByNode {
+		if _, ok := resourcesByNode[node]; !ok {
+			r.snapshotCache.ClearSnapshot(node)
+			delete(r.resourcesByNode, node)
+			slog.Info("cleared snapshot", "node", node)
+		}
+	}
+	r.metrics.setSnapshotResources(r.resourcesByNode)
+}
+
+// resourcesEqual reports whether a and b contain the same resources. The order of
+// resources within a type is not significant.
+func resourcesEqual(a, b map[resourcev3.Type][]envoyproxytypes.Resource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for typeURL, resourcesA := range a {
+		resourcesB, ok := b[typeURL]
+		if !ok || len(resourcesA) != len(resourcesB) {
+			return false
+		}
+		byName := make(map[string]envoyproxytypes.Resource, len(resourcesA))
+		for _, res := range resourcesA {
+			byName[cache.GetResourceName(res)] = res
+		}
+		for _, res := range resourcesB {
+			other, ok := byName[cache.GetResourceName(res)]
+			if !ok || !proto.Equal(res, other) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoyproxytypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+
+	"gateway-xds-generator/pkg/translator"
+)
+
+func TestReconcileRebuildsAffectedGateways(t *testing.T) {
+	gatewayA := types.NamespacedName{Namespace: testNamespace, Name: "gw-a"}
+	gatewayB := types.NamespacedName{Namespace: testNamespace, Name: "gw-b"}
+
+	for _, tc := range []struct {
+		name    string
+		changed []runtime.Object
+		want    map[types.NamespacedName]bool
+	}{
+		{
+			name:    "unrelated Service",
+			changed: []runtime.Object{testService("other")},
+			want:    map[types.NamespacedName]bool{gatewayA: false, gatewayB: false},
+		},
+		{
+			name:    "backend of one Gateway",
+			changed: []runtime.Object{testEndpointSlice("backend-a-abc", "backend-a", "10.1.0.2")},
+			want:    map[types.NamespacedName]bool{gatewayA: true, gatewayB: false},
+		},
+		{
+			name:    "route of one Gateway",
+			changed: []runtime.Object{testHTTPRoute("route-b", "gw-b", "backend-a")},
+			want:    map[types.NamespacedName]bool{gatewayA: false, gatewayB: true},
+		},
+		{
+			// Every Gateway falls back to the default backend.
+			name:    "default backend",
+			changed: []runtime.Object{testEndpointSlice("fallback-abc", "fallback", "10.1.0.3")},
+			want:    map[types.NamespacedName]bool{gatewayA: true, gatewayB: true},
+		},
+		{
+			// Kinds that are not tracked per Gateway rebuild every Gateway.
+			name:    "untracked kind",
+			changed: []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "infra"}}},
+			want:    map[types.NamespacedName]bool{gatewayA: true, gatewayB: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			tr, listers := newTestTranslator(t, translator.Options{DefaultBackend: "default/fallback:80"},
+				testGatewayClass(),
+				testGateway("gw-a", 80), testHTTPRoute("route-a", "gw-a", "backend-a"),
+				testService("backend-a"), testEndpointSlice("backend-a-abc", "backend-a", "10.1.0.1"),
+				testGateway("gw-b", 8080), testHTTPRoute("route-b", "gw-b", "backend-b"),
+				testService("backend-b"), testEndpointSlice("backend-b-abc", "backend-b", "10.2.0.1"),
+				testService("fallback"), testEndpointSlice("fallback-abc", "fallback", "10.3.0.1"),
+			)
+			r := &reconciler{
+				translator:      tr,
+				gatewayLister:   gatewaylisters.NewGatewayLister(listers.indexer("Gateway")),
+				namespace:       testNamespace,
+				nodePerGateway:  true,
+				snapshotCache:   cache.NewSnapshotCache(false, cache.IDHash{}, nil),
+				resourcesByNode: make(map[string]map[resourcev3.Type][]envoyproxytypes.Resource),
+				changes:         newChangeSet(),
+			}
+			r.reconcile(ctx)
+			if len(r.gatewayStates) != 2 {
+				t.Fatalf("got %d translated Gateways, want 2", len(r.gatewayStates))
+			}
+			before := make(map[types.NamespacedName]*gatewayState)
+			versions := make(map[string]string)
+			for key, state := range r.gatewayStates {
+				before[key] = state
+				node := key.String()
+				snapshot, err := r.snapshotCache.GetSnapshot(node)
+				if err != nil {
+					t.Fatalf("no snapshot for node %s: %v", node, err)
+				}
+				versions[node] = snapshot.GetVersion(resourcev3.ClusterType)
+			}
+
+			for _, obj := range tc.changed {
+				if _, ok := obj.(*corev1.Namespace); !ok {
+					listers.add(t, obj)
+				}
+				r.changes.record(obj)
+			}
+			r.reconcile(ctx)
+
+			for key, wantRebuilt := range tc.want {
+				state, ok := r.gatewayStates[key]
+				if !ok {
+					t.Fatalf("Gateway %s was not translated", key)
+				}
+				if rebuilt := state != before[key]; rebuilt != wantRebuilt {
+					t.Errorf("Gateway %s translated again = %v, want %v", key, rebuilt, wantRebuilt)
+				}
+				if wantRebuilt {
+					continue
+				}
+				node := key.String()
+				snapshot, err := r.snapshotCache.GetSnapshot(node)
+				if err != nil {
+					t.Fatalf("no snapshot for node %s: %v", node, err)
+				}
+				if got := snapshot.GetVersion(resourcev3.ClusterType); got != versions[node] {
+					t.Errorf("cluster version of node %s = %q, want it unchanged %q", node, got, versions[node])
+				}
+			}
+		})
+	}
+}
+
+// runDebounce runs debounce with the given window until the test ends, and returns a channel
+// that receives a value for every call of the debounced function.
+func runDebounce(t *testing.T, trigger <-chan struct{}, window time.Duration) <-chan struct{} {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	calls := make(chan struct{}, 100)
+	go debounce(ctx, trigger, window, func() { calls <- struct{}{} })
+	return calls
+}
+
+func TestDebounceBurst(t *testing.T) {
+	const window = 50 * time.Millisecond
+	trigger := make(chan struct{})
+	calls := runDebounce(t, trigger, window)
+
+	for range 5 {
+		trigger <- struct{}{}
+	}
+	select {
+	case <-calls:
+	case <-time.After(debounceMaxWindows * window * 2):
+		t.Fatal("debounced function was not called after a burst")
+	}
+	select {
+	case <-calls:
+		t.Error("debounced function was called twice for one burst")
+	case <-time.After(4 * window):
+	}
+}
+
+func TestDebounceMaxWait(t *testing.T) {
+	const window = 50 * time.Millisecond
+	trigger := make(chan struct{})
+	calls := runDebounce(t, trigger, window)
+
+	// Signals closer together than the window postpone the call until the max wait at most.
+	ticker := time.NewTicker(window / 10)
+	defer ticker.Stop()
+	start := time.Now()
+	stop := time.After(debounceMaxWindows * window * 5)
+	for {
+		select {
+		case <-ticker.C:
+			notify(trigger)
+		case <-calls:
+			if elapsed := time.Since(start); elapsed < debounceMaxWindows*window {
+				t.Errorf("debounced function called after %v, before the max wait", elapsed)
+			}
+			return
+		case <-stop:
+			t.Fatal("a steady stream of signals postponed the debounced function forever")
+		}
+	}
+}
+
+func TestResourcesEqual(t *testing.T) {
+	resources := testResources(t)
+	// The order of the resources of a type doesn't matter.
+	reordered := testResources(t)
+	reordered[resourcev3.ClusterType] = append(reordered[resourcev3.ClusterType], &clusterv3.Cluster{Name: "other"})
+	slices.Reverse(reordered[resourcev3.ClusterType])
+	withOther := testResources(t)
+	withOther[resourcev3.ClusterType] = append(withOther[resourcev3.ClusterType], &clusterv3.Cluster{Name: "other"})
+	if !resourcesEqual(withOther, reordered) {
+		t.Error("resourcesEqual() = false for the same resources in another order")
+	}
+
+	changed := testResources(t)
+	changed[resourcev3.ClusterType][0].(*clusterv3.Cluster).ConnectTimeout = durationpb.New(time.Second)
+	renamed := testResources(t)
+	renamed[resourcev3.ClusterType][0].(*clusterv3.Cluster).Name = "renamed"
+	missingType := testResources(t)
+	delete(missingType, resourcev3.SecretType)
+	for name, other := range map[string]map[resourcev3.Type][]envoyproxytypes.Resource{
+		"changed resource": changed,
+		"renamed resource": renamed,
+		"added resource":   withOther,
+		"missing type":     missingType,
+	} {
+		if resourcesEqual(resources, other) || resourcesEqual(other, resources) {
+			t.Errorf("resourcesEqual() = true with a %s", name)
+		}
+	}
+	if !resourcesEqual(resources, testResources(t)) {
+		t.Error("resourcesEqual() = false for the same resources")
+	}
+}